@@ -0,0 +1,156 @@
+package blobstore
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hawks-atlanta/fs-prototype/utils"
+)
+
+var (
+	ErrInvalidHash  = errors.New("invalid hash")
+	ErrHashMismatch = errors.New("hash mismatch")
+	ErrSizeMismatch = errors.New("size mismatch")
+	ErrNotFound     = fmt.Errorf("blob not found: %w", fs.ErrNotExist)
+)
+
+const (
+	blobsDirectory     = "blobs"
+	temporaryDirectory = "tmp"
+)
+
+// Content addressed storage on the local disk.
+// Blobs are identified by the same hash used by the archives of the index
+type Store struct {
+	Root string
+}
+
+func New(root string) (s *Store, err error) {
+	for _, directory := range []string{blobsDirectory, temporaryDirectory, uploadsDirectory} {
+		err = os.MkdirAll(filepath.Join(root, directory), 0o750)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+	return &Store{Root: root}, nil
+}
+
+// Fails with ErrInvalidHash unless the hash is a lowercase hex SHA-256 like the ones computed by Put,
+// so each blob has a single name
+func CheckHash(hash string) error {
+	buf, err := hex.DecodeString(hash)
+	if err != nil || len(buf) != 32 || hex.EncodeToString(buf) != hash {
+		return fmt.Errorf("%w: %q", ErrInvalidHash, hash)
+	}
+	return nil
+}
+
+// Blobs are fanned out in two levels of directories to keep them small
+func (s *Store) path(hash string) (string, error) {
+	err := CheckHash(hash)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, blobsDirectory, hash[0:2], hash[2:4], hash), nil
+}
+
+// Stores the contents read from r. The write only succeeds if the contents match the hash and size,
+// in which case the blob is atomically moved to its final location.
+// Storing a blob that already exists doesn't read r
+func (s *Store) Put(hash string, size uint64, r io.Reader) (err error) {
+	blobPath, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+	found, err := s.Has(hash, size)
+	if err != nil || found {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.Root, temporaryDirectory), hash+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	var hasher = utils.NewHasher()
+	// Read one more byte to detect contents bigger than declared
+	written, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(r, int64(size)+1))
+	if err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if uint64(written) != size {
+		return fmt.Errorf("%w: expecting %d bytes", ErrSizeMismatch, size)
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != hash {
+		return ErrHashMismatch
+	}
+	err = tmp.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync blob: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(blobPath), 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	err = os.Rename(tmp.Name(), blobPath)
+	if err != nil {
+		return fmt.Errorf("failed to move blob: %w", err)
+	}
+	return nil
+}
+
+// Opens the blob for streaming its contents
+func (s *Store) Open(hash string) (blob io.ReadSeekCloser, err error) {
+	blobPath, err := s.path(hash)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+// Checks if the blob is stored with the expected size
+func (s *Store) Has(hash string, size uint64) (found bool, err error) {
+	blobPath, err := s.path(hash)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat blob: %w", err)
+	}
+	return uint64(info.Size()) == size, nil
+}
+
+// Removes the blob, deleting a missing blob is not an error
+func (s *Store) Delete(hash string) (err error) {
+	blobPath, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(blobPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package blobstore
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Put(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var contents = "fmt.Println(`hello`)"
+		err = s.Put(utils.Hash(contents), uint64(len(contents)), strings.NewReader(contents))
+		assertions.Nil(err)
+
+		found, err := s.Has(utils.Hash(contents), uint64(len(contents)))
+		assertions.Nil(err)
+		assertions.True(found)
+
+		blob, err := s.Open(utils.Hash(contents))
+		assertions.Nil(err)
+		defer blob.Close()
+
+		buf, err := io.ReadAll(blob)
+		assertions.Nil(err)
+		assertions.Equal(contents, string(buf))
+	})
+	t.Run("Already stored", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var contents = "fmt.Println(`hello`)"
+		err = s.Put(utils.Hash(contents), uint64(len(contents)), strings.NewReader(contents))
+		assertions.Nil(err)
+
+		var reader = strings.NewReader(contents)
+		err = s.Put(utils.Hash(contents), uint64(len(contents)), reader)
+		assertions.Nil(err)
+		assertions.Equal(len(contents), reader.Len())
+	})
+	t.Run("Hash mismatch", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var contents = "fmt.Println(`hello`)"
+		err = s.Put(utils.Hash("other"), uint64(len(contents)), strings.NewReader(contents))
+		assertions.ErrorIs(err, ErrHashMismatch)
+
+		found, err := s.Has(utils.Hash("other"), uint64(len(contents)))
+		assertions.Nil(err)
+		assertions.False(found)
+	})
+	t.Run("Size mismatch", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var contents = "fmt.Println(`hello`)"
+		err = s.Put(utils.Hash(contents), uint64(len(contents))-1, strings.NewReader(contents))
+		assertions.ErrorIs(err, ErrSizeMismatch)
+
+		err = s.Put(utils.Hash(contents), uint64(len(contents))+1, strings.NewReader(contents))
+		assertions.ErrorIs(err, ErrSizeMismatch)
+	})
+	t.Run("Invalid hash", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		err = s.Put("../../etc/passwd", 1, bytes.NewReader([]byte{0}))
+		assertions.ErrorIs(err, ErrInvalidHash)
+
+		// Uppercase hashes would give the same blob a second name
+		var contents = "hello"
+		err = s.Put(strings.ToUpper(utils.Hash(contents)), uint64(len(contents)), strings.NewReader(contents))
+		assertions.ErrorIs(err, ErrInvalidHash)
+	})
+}
+
+func TestStore_Delete(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var contents = "fmt.Println(`hello`)"
+		err = s.Put(utils.Hash(contents), uint64(len(contents)), strings.NewReader(contents))
+		assertions.Nil(err)
+
+		err = s.Delete(utils.Hash(contents))
+		assertions.Nil(err)
+
+		_, err = s.Open(utils.Hash(contents))
+		assertions.ErrorIs(err, ErrNotFound)
+
+		// Deleting twice is fine
+		err = s.Delete(utils.Hash(contents))
+		assertions.Nil(err)
+	})
+}
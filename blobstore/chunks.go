@@ -0,0 +1,124 @@
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidUpload = errors.New("invalid upload name")
+
+const uploadsDirectory = "uploads"
+
+func (s *Store) uploadPath(upload string) (string, error) {
+	if upload == "" || upload == "." || upload == ".." || strings.ContainsAny(upload, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUpload, upload)
+	}
+	return filepath.Join(s.Root, uploadsDirectory, upload), nil
+}
+
+// Stores one chunk of an upload in progress. Chunks can be written in any order
+// and writing the same chunk again replaces it. The chunk must have exactly the given size
+func (s *Store) PutChunk(upload string, number uint, size uint64, r io.Reader) (err error) {
+	directory, err := s.uploadPath(upload)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(directory, 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.Root, temporaryDirectory), upload+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	written, err := io.Copy(tmp, io.LimitReader(r, int64(size)+1))
+	if err != nil {
+		return fmt.Errorf("failed to write chunk: %w", err)
+	}
+	if uint64(written) != size {
+		return fmt.Errorf("%w: expecting %d bytes", ErrSizeMismatch, size)
+	}
+	err = os.Rename(tmp.Name(), filepath.Join(directory, strconv.FormatUint(uint64(number), 10)))
+	if err != nil {
+		return fmt.Errorf("failed to move chunk: %w", err)
+	}
+	return nil
+}
+
+// Streams the chunks of the upload in order, each chunk is opened only when the previous one is consumed
+func (s *Store) ReadChunks(upload string, count uint) (r io.ReadCloser, err error) {
+	directory, err := s.uploadPath(upload)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkReader{directory: directory, count: count}, nil
+}
+
+// Removes every chunk of the upload
+func (s *Store) DeleteChunks(upload string) (err error) {
+	directory, err := s.uploadPath(upload)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(directory)
+	if err != nil {
+		return fmt.Errorf("failed to delete chunks: %w", err)
+	}
+	return nil
+}
+
+type chunkReader struct {
+	directory string
+	count     uint
+	next      uint
+	current   *os.File
+}
+
+func (cr *chunkReader) Read(p []byte) (n int, err error) {
+	for {
+		if cr.current == nil {
+			if cr.next >= cr.count {
+				return 0, io.EOF
+			}
+			cr.current, err = os.Open(filepath.Join(cr.directory, strconv.FormatUint(uint64(cr.next), 10)))
+			if errors.Is(err, fs.ErrNotExist) {
+				return 0, fmt.Errorf("chunk %d: %w", cr.next, ErrNotFound)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to open chunk %d: %w", cr.next, err)
+			}
+			cr.next++
+		}
+		n, err = cr.current.Read(p)
+		if err == io.EOF {
+			cr.current.Close()
+			cr.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (cr *chunkReader) Close() error {
+	if cr.current != nil {
+		return cr.current.Close()
+	}
+	return nil
+}
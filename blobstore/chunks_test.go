@@ -0,0 +1,68 @@
+package blobstore
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_PutChunk(t *testing.T) {
+	t.Run("Out of order", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		var chunks = []string{"hello ", "chunked ", "world"}
+		for _, number := range []uint{2, 0, 1} {
+			err = s.PutChunk("upload", number, uint64(len(chunks[number])), strings.NewReader(chunks[number]))
+			assertions.Nil(err)
+		}
+
+		r, err := s.ReadChunks("upload", uint(len(chunks)))
+		assertions.Nil(err)
+		defer r.Close()
+
+		buf, err := io.ReadAll(r)
+		assertions.Nil(err)
+		assertions.Equal(strings.Join(chunks, ""), string(buf))
+
+		// Assembled contents can be stored as a blob
+		var contents = strings.Join(chunks, "")
+		r, err = s.ReadChunks("upload", uint(len(chunks)))
+		assertions.Nil(err)
+		defer r.Close()
+		err = s.Put(utils.Hash(contents), uint64(len(contents)), r)
+		assertions.Nil(err)
+
+		err = s.DeleteChunks("upload")
+		assertions.Nil(err)
+
+		r, err = s.ReadChunks("upload", uint(len(chunks)))
+		assertions.Nil(err)
+		defer r.Close()
+		_, err = io.ReadAll(r)
+		assertions.ErrorIs(err, ErrNotFound)
+	})
+	t.Run("Size mismatch", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		err = s.PutChunk("upload", 0, 3, strings.NewReader("hello"))
+		assertions.ErrorIs(err, ErrSizeMismatch)
+	})
+	t.Run("Invalid upload", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s, err := New(t.TempDir())
+		assertions.Nil(err)
+
+		err = s.PutChunk("../upload", 0, 5, strings.NewReader("hello"))
+		assertions.ErrorIs(err, ErrInvalidUpload)
+	})
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"gorm.io/gorm"
+)
+
+var errUsage = errors.New("invalid arguments")
+
+type app struct {
+	backend Backend
+	user    uuid.UUID
+	json    bool
+	stdout  io.Writer
+}
+
+type command struct {
+	usage       string
+	description string
+	run         func(a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"mkdir":          {"mkdir PATH", "create a directory", mkdir},
+	"ls":             {"ls [-sort name|size|createdAt] [-r] [PATH]", "list a directory, the root by default", ls},
+	"mv":             {"mv SOURCE DESTINATION", "move or rename a file", mv},
+	"cp":             {"cp SOURCE DESTINATION", "copy a file or a directory with its contents", cp},
+	"rm":             {"rm [-permanent] FILE", "move a file to the trash", rm},
+	"share":          {"share [-role viewer|commenter|editor|manager] [-for DURATION] FILE USER", "share a file with a user", share},
+	"share-role":     {"share-role FILE USER ROLE", "change the role of a user over a shared file", shareRole},
+	"unshare":        {"unshare FILE USER", "stop sharing a file with a user", unshare},
+	"shared-with-me": {"shared-with-me", "list the files shared with the user", sharedWithMe},
+	"who-has":        {"who-has FILE", "list the users a file is shared with", whoHas},
+	"stat":           {"stat FILE", "show the details of a file", stat},
+	"usage":          {"usage", "show the storage used by the user and their quota", usage},
+}
+
+// Parses the flags of a command, exactly nargs positional arguments are expected
+func parseArgs(flags *flag.FlagSet, args []string, nargs ...int) (err error) {
+	flags.SetOutput(io.Discard)
+	err = flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	for _, n := range nargs {
+		if flags.NArg() == n {
+			return nil
+		}
+	}
+	return errUsage
+}
+
+// Finds the file referenced by a path or a UUID
+func (a *app) resolve(ref string) (file models.File, err error) {
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		return a.backend.GetFile(&controller.GetFile{UserUUID: a.user, FileUUID: id})
+	}
+	return a.backend.ResolvePath(&controller.ResolvePath{UserUUID: a.user, Path: ref})
+}
+
+// Same as resolve but the root directory is allowed, in which case nil is returned
+func (a *app) resolveDirectory(ref string) (directory *uuid.UUID, err error) {
+	if path.Clean("/"+ref) == "/" {
+		return nil, nil
+	}
+	file, err := a.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	if file.ArchiveUUID != nil {
+		return nil, fmt.Errorf("%s: %w", ref, controller.ErrNotDirectory)
+	}
+	return &file.UUID, nil
+}
+
+func mkdir(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("mkdir", flag.ContinueOnError)
+	var conflict = flags.String("conflict", string(controller.ConflictFail), "fail, rename, replace or merge when the name is taken")
+	err = parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	var dirPath = path.Clean("/" + flags.Arg(0))
+	if dirPath == "/" {
+		return fmt.Errorf("%w: the root directory already exists", errUsage)
+	}
+	parent, err := a.resolveDirectory(path.Dir(dirPath))
+	if err != nil {
+		return err
+	}
+	dir, err := a.backend.CreateFile(&controller.CreateFile{
+		OwnerUUID:       a.user,
+		Filename:        path.Base(dirPath),
+		ParentDirectory: parent,
+		Conflict:        controller.ConflictPolicy(*conflict),
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(dir, func(w io.Writer) {
+		fmt.Fprintln(w, dir.UUID)
+	})
+}
+
+func ls(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("ls", flag.ContinueOnError)
+	var (
+		sortBy     = flags.String("sort", string(controller.SortByName), "sort by name, size or createdAt")
+		descending = flags.Bool("r", false, "reverse the order")
+	)
+	err = parseArgs(flags, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	var ld = controller.ListDirectory{
+		UserUUID:   a.user,
+		SortBy:     controller.SortBy(*sortBy),
+		Descending: *descending,
+		Limit:      controller.MaxListLimit,
+	}
+	if flags.NArg() == 1 {
+		ld.ParentUUID, err = a.resolveDirectory(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+	}
+	var files = []models.File{}
+	for {
+		dir, err := a.backend.ListDirectory(&ld)
+		if err != nil {
+			return err
+		}
+		files = append(files, dir.Files...)
+		if dir.NextCursor == "" {
+			break
+		}
+		ld.Cursor = dir.NextCursor
+	}
+	return a.print(files, func(w io.Writer) {
+		table := newTable(w)
+		fmt.Fprintln(table, "TYPE\tSIZE\tNAME\tUUID")
+		for _, file := range files {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", fileType(&file), fileSize(&file), file.Name, file.UUID)
+		}
+		table.Flush()
+	})
+}
+
+// Moves into DESTINATION when it is an existing directory,
+// otherwise the file is moved to the parent of DESTINATION and renamed after it.
+// Existing files are only overwritten with a conflict policy other than fail
+func mv(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("mv", flag.ContinueOnError)
+	var conflict = flags.String("conflict", string(controller.ConflictFail), "fail, rename, replace or merge when the name is taken")
+	err = parseArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	source, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var mf = controller.MoveFile{
+		OwnerUUID: a.user,
+		FileUUID:  source.UUID,
+		Conflict:  controller.ConflictPolicy(*conflict),
+	}
+	destination, err := a.resolve(flags.Arg(1))
+	switch {
+	case errors.Is(err, controller.ErrInvalidPath) && path.Clean("/"+flags.Arg(1)) == "/":
+		mf.ToRoot = true
+	case err == nil && destination.ArchiveUUID == nil:
+		mf.NewLocation = &destination.UUID
+	case err == nil && mf.Conflict == controller.ConflictFail:
+		return fmt.Errorf("%s already exists", flags.Arg(1))
+	case err == nil, errors.Is(err, gorm.ErrRecordNotFound):
+		var destinationPath = path.Clean("/" + flags.Arg(1))
+		parent, err := a.resolveDirectory(path.Dir(destinationPath))
+		if err != nil {
+			return err
+		}
+		mf.ToRoot = parent == nil && source.ParentUUID != nil
+		if parent != nil && (source.ParentUUID == nil || *parent != *source.ParentUUID) {
+			mf.NewLocation = parent
+		}
+		var name = path.Base(destinationPath)
+		if name != source.Name {
+			mf.NewName = &name
+		}
+	default:
+		return err
+	}
+	err = a.backend.MoveFile(&mf)
+	if err != nil {
+		return err
+	}
+	return a.print(mf, func(w io.Writer) {})
+}
+
+// Copies into DESTINATION when it is an existing directory,
+// otherwise the copy is created in the parent of DESTINATION and named after it.
+// Existing files are only overwritten with a conflict policy other than fail
+func cp(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("cp", flag.ContinueOnError)
+	var conflict = flags.String("conflict", string(controller.ConflictFail), "fail, rename, replace or merge when the name is taken")
+	err = parseArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	source, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var cf = controller.CopyFile{
+		UserUUID: a.user,
+		FileUUID: source.UUID,
+		Conflict: controller.ConflictPolicy(*conflict),
+	}
+	destination, err := a.resolve(flags.Arg(1))
+	switch {
+	case errors.Is(err, controller.ErrInvalidPath) && path.Clean("/"+flags.Arg(1)) == "/":
+	case err == nil && destination.ArchiveUUID == nil:
+		cf.NewLocation = &destination.UUID
+	case err == nil && cf.Conflict == controller.ConflictFail:
+		return fmt.Errorf("%s already exists", flags.Arg(1))
+	case err == nil, errors.Is(err, gorm.ErrRecordNotFound):
+		var destinationPath = path.Clean("/" + flags.Arg(1))
+		cf.NewLocation, err = a.resolveDirectory(path.Dir(destinationPath))
+		if err != nil {
+			return err
+		}
+		var name = path.Base(destinationPath)
+		cf.NewName = &name
+	default:
+		return err
+	}
+	copied, err := a.backend.CopyFile(&cf)
+	if err != nil {
+		return err
+	}
+	return a.print(copied, func(w io.Writer) {
+		fmt.Fprintln(w, copied.UUID)
+	})
+}
+
+func rm(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("rm", flag.ContinueOnError)
+	var permanent = flags.Bool("permanent", false, "delete the file instead of moving it to the trash")
+	err = parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	file, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var df = controller.DeleteFile{
+		OwnerUUID: a.user,
+		FileUUID:  file.UUID,
+		Permanent: *permanent,
+	}
+	err = a.backend.DeleteFile(&df)
+	if err != nil {
+		return err
+	}
+	return a.print(df, func(w io.Writer) {})
+}
+
+func shareRequest(a *app, flags *flag.FlagSet, args []string, nargs int) (sr controller.ShareRequest, err error) {
+	err = parseArgs(flags, args, nargs)
+	if err != nil {
+		return sr, err
+	}
+	file, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return sr, err
+	}
+	target, err := uuid.Parse(flags.Arg(1))
+	if err != nil {
+		return sr, fmt.Errorf("%w: invalid user: %w", errUsage, err)
+	}
+	sr = controller.ShareRequest{
+		OwnerUUID:      a.user,
+		FileUUID:       file.UUID,
+		TargetUserUUID: target,
+	}
+	return sr, nil
+}
+
+func share(a *app, args []string) (err error) {
+	var (
+		flags = flag.NewFlagSet("share", flag.ContinueOnError)
+		role  = flags.String("role", string(models.RoleViewer), "")
+		ttl   = flags.Duration("for", 0, "")
+	)
+	sr, err := shareRequest(a, flags, args, 2)
+	if err == nil {
+		sr.Role = models.ShareRole(*role)
+		if *ttl != 0 {
+			expiresAt := time.Now().Add(*ttl)
+			sr.ExpiresAt = &expiresAt
+		}
+		err = a.backend.ShareFile(&sr)
+	}
+	if err != nil {
+		return err
+	}
+	return a.print(sr, func(w io.Writer) {})
+}
+
+func shareRole(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("share-role", flag.ContinueOnError)
+	sr, err := shareRequest(a, flags, args, 3)
+	if err == nil {
+		sr.Role = models.ShareRole(flags.Arg(2))
+		err = a.backend.ChangeShareRole(&sr)
+	}
+	if err != nil {
+		return err
+	}
+	return a.print(sr, func(w io.Writer) {})
+}
+
+func unshare(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("unshare", flag.ContinueOnError)
+	sr, err := shareRequest(a, flags, args, 2)
+	if err == nil {
+		err = a.backend.UnshareFile(&sr)
+	}
+	if err != nil {
+		return err
+	}
+	return a.print(sr, func(w io.Writer) {})
+}
+
+func sharedWithMe(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("shared-with-me", flag.ContinueOnError)
+	var (
+		owner      = flags.String("owner", "", "only the files of this user")
+		descending = flags.Bool("r", false, "newest shares first")
+	)
+	err = parseArgs(flags, args, 0)
+	if err != nil {
+		return err
+	}
+	var swm = controller.ShareWithMe{
+		UserUUID:   a.user,
+		Descending: *descending,
+		Limit:      controller.MaxListLimit,
+	}
+	if *owner != "" {
+		ownerUUID, err := uuid.Parse(*owner)
+		if err != nil {
+			return fmt.Errorf("invalid owner: %w", err)
+		}
+		swm.OwnerUUID = &ownerUUID
+	}
+	var shared = []controller.SharedEntry{}
+	for {
+		page, err := a.backend.ShareWithMe(&swm)
+		if err != nil {
+			return err
+		}
+		shared = append(shared, page.Entries...)
+		if page.NextCursor == "" {
+			break
+		}
+		swm.Cursor = page.NextCursor
+	}
+	var paths = make([]string, 0, len(shared))
+	for _, entry := range shared {
+		filePath, err := a.backend.PathOf(&controller.PathOf{UserUUID: a.user, FileUUID: entry.File.UUID})
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filePath)
+	}
+	return a.print(shared, func(w io.Writer) {
+		table := newTable(w)
+		fmt.Fprintln(table, "TYPE\tSIZE\tPATH\tUUID\tROLE\tSHARED BY")
+		for index, entry := range shared {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", fileType(&entry.File), fileSize(&entry.File), paths[index], entry.File.UUID, entry.Role, entry.SharerUUID)
+		}
+		table.Flush()
+	})
+}
+
+func whoHas(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("who-has", flag.ContinueOnError)
+	err = parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	file, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	shared, err := a.backend.ShareWithWho(&controller.ShareWithWho{OwnerUUID: a.user, FileUUID: file.UUID})
+	if err != nil {
+		return err
+	}
+	return a.print(shared, func(w io.Writer) {
+		table := newTable(w)
+		fmt.Fprintln(table, "USER\tROLE\tEXPIRES")
+		for _, entry := range shared {
+			var expires = "never"
+			if entry.ExpiresAt != nil {
+				expires = entry.ExpiresAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\n", entry.UserUUID, entry.Role, expires)
+		}
+		table.Flush()
+	})
+}
+
+type Stat struct {
+	models.File
+	Path        string `json:"path"`
+	IsDirectory bool   `json:"isDirectory"`
+}
+
+func stat(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("stat", flag.ContinueOnError)
+	err = parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	file, err := a.resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var s = Stat{
+		File:        file,
+		IsDirectory: file.ArchiveUUID == nil,
+	}
+	s.Path, err = a.backend.PathOf(&controller.PathOf{UserUUID: a.user, FileUUID: file.UUID})
+	if err != nil {
+		return err
+	}
+	if !s.IsDirectory {
+		archive, err := a.backend.QueryFile(&controller.QueryFile{UserUUID: a.user, FileUUID: file.UUID})
+		if err != nil && !errors.Is(err, controller.ErrArchiveNotReady) {
+			return err
+		}
+		// The HTTP API doesn't return the archives still being uploaded
+		if archive.UUID != uuid.Nil {
+			s.Archive = &archive
+		}
+	}
+	return a.print(s, func(w io.Writer) {
+		table := newTable(w)
+		fmt.Fprintf(table, "Name:\t%s\n", s.Name)
+		fmt.Fprintf(table, "Path:\t%s\n", s.Path)
+		fmt.Fprintf(table, "UUID:\t%s\n", s.UUID)
+		fmt.Fprintf(table, "Owner:\t%s\n", s.OwnerUUID)
+		if s.IsDirectory {
+			fmt.Fprintf(table, "Type:\tdirectory\n")
+		} else {
+			fmt.Fprintf(table, "Type:\tfile\n")
+			fmt.Fprintf(table, "Size:\t%s\n", fileSize(&s.File))
+			if s.Archive != nil {
+				fmt.Fprintf(table, "Hash:\t%s\n", s.Archive.Hash)
+			}
+			fmt.Fprintf(table, "Ready:\t%t\n", s.Archive != nil && s.Archive.IsReady)
+		}
+		table.Flush()
+	})
+}
+
+func usage(a *app, args []string) (err error) {
+	var flags = flag.NewFlagSet("usage", flag.ContinueOnError)
+	err = parseArgs(flags, args, 0)
+	if err != nil {
+		return err
+	}
+	u, err := a.backend.GetUsage(&controller.GetUsage{UserUUID: a.user})
+	if err != nil {
+		return err
+	}
+	return a.print(u, func(w io.Writer) {
+		table := newTable(w)
+		fmt.Fprintln(table, "\tUSED\tLIMIT\tREMAINING")
+		fmt.Fprintf(table, "Bytes\t%d\t%s\t%s\n", u.Bytes, quotaLimit(u.MaxBytes), quotaRemaining(u.RemainingBytes))
+		fmt.Fprintf(table, "Files\t%d\t%s\t%s\n", u.Files, quotaLimit(u.MaxFiles), quotaRemaining(u.RemainingFiles))
+		table.Flush()
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/server"
+)
+
+// Operations used by the commands, satisfied by both the controller and the HTTP client
+type Backend interface {
+	CreateFile(cf *controller.CreateFile) (models.File, error)
+	ListDirectory(ld *controller.ListDirectory) (controller.Directory, error)
+	QueryFile(qf *controller.QueryFile) (models.Archive, error)
+	GetFile(gf *controller.GetFile) (models.File, error)
+	MoveFile(mf *controller.MoveFile) error
+	CopyFile(cf *controller.CopyFile) (models.File, error)
+	DeleteFile(df *controller.DeleteFile) error
+	ShareFile(sr *controller.ShareRequest) error
+	ChangeShareRole(sr *controller.ShareRequest) error
+	UnshareFile(sr *controller.ShareRequest) error
+	ShareWithMe(swm *controller.ShareWithMe) (controller.SharedWithMe, error)
+	ShareWithWho(sww *controller.ShareWithWho) ([]models.SharedFile, error)
+	ResolvePath(rp *controller.ResolvePath) (models.File, error)
+	PathOf(po *controller.PathOf) (string, error)
+	GetUsage(gu *controller.GetUsage) (controller.Usage, error)
+}
+
+var (
+	_ Backend = (*controller.Controller)(nil)
+	_ Backend = (*server.Client)(nil)
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) (code int) {
+	var flags = flag.NewFlagSet("fsctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		user       = flags.String("user", os.Getenv("FSCTL_USER"), "UUID of the user performing the operations (FSCTL_USER)")
+		serverURL  = flags.String("server", os.Getenv("FSCTL_SERVER"), "base URL of the HTTP API, the database is used directly when empty (FSCTL_SERVER)")
+		dsn        = flags.String("dsn", os.Getenv("FSCTL_DSN"), "database connection string, overrides the configuration when set (FSCTL_DSN)")
+		jsonOutput = flags.Bool("json", false, "print the results as JSON")
+	)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: fsctl [flags] <command> [arguments]\n\nCommands:\n")
+		var names = make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(stderr, "  %-16s %s\n", name, commands[name].description)
+		}
+		fmt.Fprintf(stderr, "\nFiles are referenced by their path, like /docs/notes.txt, or by their UUID.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	cmd, found := commands[flags.Arg(0)]
+	if !found {
+		fmt.Fprintf(stderr, "fsctl: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	userUUID, err := uuid.Parse(*user)
+	if err != nil {
+		fmt.Fprintf(stderr, "fsctl: a valid user UUID is required, use -user or FSCTL_USER\n")
+		return 2
+	}
+	var a = app{
+		user:   userUUID,
+		json:   *jsonOutput,
+		stdout: stdout,
+	}
+	if *serverURL != "" {
+		a.backend = server.NewClient(*serverURL)
+	} else {
+		var cfg config.Config
+		cfg, err = config.Load()
+		if err == nil {
+			if *dsn != "" {
+				cfg.Database.DSN = *dsn
+			}
+			var c *controller.Controller
+			c, err = controller.FromConfig(&cfg)
+			if err == nil {
+				defer c.Close()
+				a.backend = c
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "fsctl: failed to open database: %v\n", err)
+			return 1
+		}
+	}
+
+	err = cmd.run(&a, flags.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "fsctl %s: %v\n", flags.Arg(0), err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "Usage: fsctl %s\n", cmd.usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/database"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
+
+func newTestApp(t *testing.T) (a *app, stdout *bytes.Buffer) {
+	assertions := assert.New(t)
+
+	c, err := controller.Default()
+	assertions.Nil(err)
+	t.Cleanup(func() { c.Close() })
+
+	stdout = &bytes.Buffer{}
+	a = &app{
+		backend: c,
+		user:    uuid.New(),
+		stdout:  stdout,
+	}
+	return a, stdout
+}
+
+func runCommand(a *app, args ...string) (err error) {
+	return commands[args[0]].run(a, args[1:])
+}
+
+func TestCommands(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		a, stdout := newTestApp(t)
+
+		assertions.Nil(runCommand(a, "mkdir", "/docs"))
+		assertions.Nil(runCommand(a, "mkdir", "/docs/archive"))
+
+		var contents = uuid.NewString()
+		dir, err := a.backend.ResolvePath(&controller.ResolvePath{UserUUID: a.user, Path: "/docs"})
+		assertions.Nil(err)
+		_, err = a.backend.CreateFile(&controller.CreateFile{
+			OwnerUUID:       a.user,
+			Filename:        "notes.txt",
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &dir.UUID,
+		})
+		assertions.Nil(err)
+
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "ls", "/docs"))
+		assertions.Contains(stdout.String(), "archive")
+		assertions.Contains(stdout.String(), "notes.txt")
+
+		// Move into a directory and rename
+		assertions.Nil(runCommand(a, "mv", "/docs/notes.txt", "/docs/archive"))
+		assertions.Nil(runCommand(a, "mv", "/docs/archive/notes.txt", "/docs/archive/old.txt"))
+		assertions.NotNil(runCommand(a, "mv", "/docs/archive", "/docs/archive/old.txt"))
+		assertions.ErrorIs(runCommand(a, "mv", "/docs", "/docs/archive"), controller.ErrMoveCycle)
+
+		assertions.NotNil(runCommand(a, "mkdir", "/docs/archive"))
+		assertions.Nil(runCommand(a, "mkdir", "-conflict", "merge", "/docs/archive"))
+
+		// Back and forth from the root
+		assertions.Nil(runCommand(a, "mv", "/docs/archive/old.txt", "/"))
+		assertions.Nil(runCommand(a, "mv", "/old.txt", "/docs/archive"))
+
+		// Copy a file and a whole directory
+		assertions.Nil(runCommand(a, "cp", "/docs/archive/old.txt", "/"))
+		assertions.NotNil(runCommand(a, "cp", "/docs/archive/old.txt", "/old.txt"))
+		assertions.Nil(runCommand(a, "cp", "/docs/archive", "/backup"))
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "ls", "/backup"))
+		assertions.Contains(stdout.String(), "old.txt")
+
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "usage"))
+		assertions.Contains(stdout.String(), "unlimited")
+
+		a.json = true
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "stat", "/docs/archive/old.txt"))
+		var s Stat
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &s))
+		assertions.Equal("/docs/archive/old.txt", s.Path)
+		assertions.False(s.IsDirectory)
+		assertions.Equal(uint64(len(contents)), s.Archive.Size)
+		assertions.False(s.Archive.IsReady)
+
+		// Files can be referenced by UUID too
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "stat", s.UUID.String()))
+
+		var recipient = uuid.New()
+		assertions.Nil(runCommand(a, "share", "/docs", recipient.String()))
+
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "who-has", "/docs"))
+		var shared []models.SharedFile
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &shared))
+		assertions.Len(shared, 1)
+		assertions.Equal(recipient, shared[0].UserUUID)
+		assertions.Equal(models.RoleViewer, shared[0].Role)
+
+		assertions.Nil(runCommand(a, "share-role", "/docs", recipient.String(), "editor"))
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "who-has", "/docs"))
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &shared))
+		assertions.Equal(models.RoleEditor, shared[0].Role)
+
+		var contractor = uuid.New()
+		assertions.Nil(runCommand(a, "share", "-for", "24h", "/docs", contractor.String()))
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "who-has", "/docs"))
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &shared))
+		assertions.Len(shared, 2)
+		for _, entry := range shared {
+			assertions.Equal(entry.UserUUID == contractor, entry.ExpiresAt != nil)
+		}
+		assertions.Nil(runCommand(a, "unshare", "/docs", contractor.String()))
+
+		var owner = a.user
+		a.user, a.json = recipient, false
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "shared-with-me", "-owner", owner.String()))
+		assertions.Contains(stdout.String(), "/docs")
+		assertions.Contains(stdout.String(), owner.String())
+
+		a.user = owner
+		assertions.Nil(runCommand(a, "unshare", "/docs", recipient.String()))
+		assertions.Nil(runCommand(a, "rm", "/docs"))
+		assertions.NotNil(runCommand(a, "stat", "/docs"))
+	})
+	t.Run("UUID references", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		a, stdout := newTestApp(t)
+		a.json = true
+
+		// The recipient has a file of its own with the same name as the shared one
+		var owner = a.user
+		assertions.Nil(runCommand(a, "mkdir", "/docs"))
+		shared, err := a.backend.ResolvePath(&controller.ResolvePath{UserUUID: owner, Path: "/docs"})
+		assertions.Nil(err)
+		var recipient = uuid.New()
+		assertions.Nil(runCommand(a, "share", "/docs", recipient.String()))
+		a.user = recipient
+		assertions.Nil(runCommand(a, "mkdir", "/docs"))
+
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "stat", shared.UUID.String()))
+		var s Stat
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &s))
+		assertions.Equal(shared.UUID, s.UUID)
+		assertions.Equal(owner, s.OwnerUUID)
+
+		// Owners can address their files in the trash
+		a.user = owner
+		assertions.Nil(runCommand(a, "rm", "/docs"))
+		stdout.Reset()
+		assertions.Nil(runCommand(a, "stat", shared.UUID.String()))
+		assertions.Nil(json.Unmarshal(stdout.Bytes(), &s))
+		assertions.NotNil(s.TrashedAt)
+	})
+	t.Run("Invalid arguments", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		a, _ := newTestApp(t)
+
+		assertions.ErrorIs(runCommand(a, "mkdir"), errUsage)
+		assertions.ErrorIs(runCommand(a, "mkdir", "/"), errUsage)
+		assertions.ErrorIs(runCommand(a, "ls", "-unknown"), errUsage)
+		assertions.ErrorIs(runCommand(a, "mv", "/a"), errUsage)
+
+		assertions.Nil(runCommand(a, "mkdir", "/docs"))
+		assertions.ErrorIs(runCommand(a, "share", "/docs", "someone"), errUsage)
+	})
+	t.Run("Usage", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		var stdout, stderr bytes.Buffer
+		assertions.Equal(2, run([]string{}, &stdout, &stderr))
+		assertions.Contains(stderr.String(), "shared-with-me")
+
+		stderr.Reset()
+		assertions.Equal(2, run([]string{"ls"}, &stdout, &stderr))
+		assertions.Contains(stderr.String(), "user UUID is required")
+	})
+}
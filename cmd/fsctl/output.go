@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/hawks-atlanta/fs-prototype/models"
+)
+
+// Prints v as JSON or uses the human readable representation
+func (a *app) print(v any, human func(w io.Writer)) (err error) {
+	if a.json {
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	human(a.stdout)
+	return nil
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+func fileType(file *models.File) string {
+	if file.ArchiveUUID == nil {
+		return "dir"
+	}
+	return "file"
+}
+
+// Directories and the files whose archive was not loaded have no size
+func fileSize(file *models.File) string {
+	if file.Archive == nil {
+		return "-"
+	}
+	return strconv.FormatUint(file.Archive.Size, 10)
+}
+
+// Limits of zero mean unlimited
+func quotaLimit(limit uint64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatUint(limit, 10)
+}
+
+func quotaRemaining(remaining *uint64) string {
+	if remaining == nil {
+		return "-"
+	}
+	return strconv.FormatUint(*remaining, 10)
+}
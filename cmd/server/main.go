@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/rpc"
+	"github.com/hawks-atlanta/fs-prototype/server"
+)
+
+func main() {
+	var (
+		listen     = flag.String("listen", "127.0.0.1:8080", "address to listen on")
+		grpcListen = flag.String("grpc-listen", "", "address to serve the gRPC service on, disabled when empty")
+		dsn        = flag.String("dsn", "", "database connection string, overrides the configuration when set")
+		blobs      = flag.String("blobs", "", "directory of the local blob store, overrides the configuration when set")
+		reap       = flag.Duration("reap-interval", time.Minute, "interval between the removals of expired shares, disabled when zero")
+		purge      = flag.Duration("purge-interval", time.Hour, "interval between the purges of the trash, disabled when zero")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+	if *dsn != "" {
+		cfg.Database.DSN = *dsn
+	}
+	if *blobs != "" {
+		cfg.Blobs.Directory = *blobs
+	}
+	c, err := controller.FromConfig(&cfg)
+	if err != nil {
+		log.Fatalf("failed to open controller: %v", err)
+	}
+	defer c.Close()
+
+	if *reap > 0 {
+		go reapExpiredShares(c, *reap)
+	}
+	if *purge > 0 {
+		go purgeTrash(c, *purge)
+	}
+
+	if *grpcListen != "" {
+		listener, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC: %v", err)
+		}
+		go func() {
+			log.Printf("serving gRPC on %s", *grpcListen)
+			err := rpc.NewServer(c).Serve(listener)
+			if err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	var srv = http.Server{
+		Addr:              *listen,
+		Handler:           server.New(c),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("listening on %s", *listen)
+	err = srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+func reapExpiredShares(c *controller.Controller, interval time.Duration) {
+	for range time.Tick(interval) {
+		reaped, err := c.ReapExpiredShares()
+		if err != nil {
+			log.Printf("failed to reap expired shares: %v", err)
+			continue
+		}
+		for _, share := range reaped.Shares {
+			log.Printf("share of file %s with user %s expired", share.FileUUID, share.UserUUID)
+		}
+		for _, share := range reaped.GroupShares {
+			log.Printf("share of file %s with group %s expired", share.FileUUID, share.GroupUUID)
+		}
+	}
+}
+
+// Removes the files trashed for longer than the retention of the configuration
+func purgeTrash(c *controller.Controller, interval time.Duration) {
+	for range time.Tick(interval) {
+		purged, err := c.PurgeTrash(&controller.PurgeTrash{Retention: c.TrashRetention})
+		if err != nil {
+			log.Printf("failed to purge trash: %v", err)
+			continue
+		}
+		for _, file := range purged {
+			log.Printf("file %s of user %s purged from the trash", file.UUID, file.OwnerUUID)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variable with the path of the configuration file
+const FileEnv = "FS_CONFIG"
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+type Database struct {
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+}
+
+type Log struct {
+	// One of silent, error, warn or info
+	Level string `yaml:"level"`
+}
+
+type Blobs struct {
+	// Root of the local blob store, the store is disabled when empty
+	Directory string `yaml:"directory"`
+}
+
+// Time trashed files are kept before being purged
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+type Trash struct {
+	// Age of the trashed files removed by the periodic purges
+	Retention time.Duration `yaml:"retention"`
+}
+
+// Default limits of every user, zero means unlimited
+type Quota struct {
+	// Added up sizes of the contents of the files the user owns
+	Bytes uint64 `yaml:"bytes"`
+	// Files the user owns, directories included
+	Files uint64 `yaml:"files"`
+}
+
+type Config struct {
+	Database Database `yaml:"database"`
+	Log      Log      `yaml:"log"`
+	Blobs    Blobs    `yaml:"blobs"`
+	Quota    Quota    `yaml:"quota"`
+	Trash    Trash    `yaml:"trash"`
+}
+
+// Matches the database of the docker-compose.yaml used for development
+func Default() Config {
+	return Config{
+		Database: Database{
+			DSN:          "host=127.0.0.1 user=sulcud password=sulcud dbname=sulcud port=5432 sslmode=disable",
+			MaxOpenConns: 10,
+			MaxIdleConns: 2,
+		},
+		Log: Log{
+			Level: "warn",
+		},
+		Trash: Trash{
+			Retention: DefaultTrashRetention,
+		},
+	}
+}
+
+// Loads the configuration, the defaults are overridden by the file pointed by FS_CONFIG
+// and then by the environment variables:
+//
+//	FS_DATABASE_DSN
+//	FS_DATABASE_MAX_OPEN_CONNS
+//	FS_DATABASE_MAX_IDLE_CONNS
+//	FS_DATABASE_CONN_MAX_LIFETIME
+//	FS_LOG_LEVEL
+//	FS_BLOBS_DIRECTORY
+//	FS_QUOTA_BYTES
+//	FS_QUOTA_FILES
+//	FS_TRASH_RETENTION
+func Load() (cfg Config, err error) {
+	return LoadFile(os.Getenv(FileEnv))
+}
+
+// Same as Load but reading the given file, no file is read when the path is empty
+func LoadFile(path string) (cfg Config, err error) {
+	cfg = Default()
+	if path != "" {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read configuration: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(contents))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&cfg)
+		// Empty files are fine
+		if err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+		}
+	}
+	err = cfg.applyEnv()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	return cfg, err
+}
+
+func (cfg *Config) applyEnv() (err error) {
+	var (
+		strings = map[string]*string{
+			"FS_DATABASE_DSN":    &cfg.Database.DSN,
+			"FS_LOG_LEVEL":       &cfg.Log.Level,
+			"FS_BLOBS_DIRECTORY": &cfg.Blobs.Directory,
+		}
+		ints = map[string]*int{
+			"FS_DATABASE_MAX_OPEN_CONNS": &cfg.Database.MaxOpenConns,
+			"FS_DATABASE_MAX_IDLE_CONNS": &cfg.Database.MaxIdleConns,
+		}
+		uints = map[string]*uint64{
+			"FS_QUOTA_BYTES": &cfg.Quota.Bytes,
+			"FS_QUOTA_FILES": &cfg.Quota.Files,
+		}
+		durations = map[string]*time.Duration{
+			"FS_DATABASE_CONN_MAX_LIFETIME": &cfg.Database.ConnMaxLifetime,
+			"FS_TRASH_RETENTION":            &cfg.Trash.Retention,
+		}
+	)
+	for name, target := range strings {
+		if value, found := os.LookupEnv(name); found {
+			*target = value
+		}
+	}
+	for name, target := range ints {
+		if value, found := os.LookupEnv(name); found {
+			*target, err = strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+			}
+		}
+	}
+	for name, target := range uints {
+		if value, found := os.LookupEnv(name); found {
+			*target, err = strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+			}
+		}
+	}
+	for name, target := range durations {
+		if value, found := os.LookupEnv(name); found {
+			*target, err = time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (cfg *Config) Validate() (err error) {
+	switch {
+	case cfg.Database.DSN == "":
+		err = errors.New("database DSN is required")
+	case cfg.Database.MaxOpenConns < 0:
+		err = errors.New("database max open connections can't be negative")
+	case cfg.Database.MaxIdleConns < 0:
+		err = errors.New("database max idle connections can't be negative")
+	case cfg.Database.MaxOpenConns > 0 && cfg.Database.MaxIdleConns > cfg.Database.MaxOpenConns:
+		err = errors.New("database max idle connections can't exceed the max open connections")
+	case cfg.Database.ConnMaxLifetime < 0:
+		err = errors.New("database connection max lifetime can't be negative")
+	case cfg.Trash.Retention <= 0:
+		err = errors.New("trash retention must be positive")
+	}
+	if err == nil {
+		switch cfg.Log.Level {
+		case "silent", "error", "warn", "info":
+		default:
+			err = fmt.Errorf("unknown log level %q", cfg.Log.Level)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return err
+}
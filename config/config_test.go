@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Hides the variables of the environment running the tests
+func clearEnv(t *testing.T) {
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if strings.HasPrefix(name, "FS_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, contents string) (path string) {
+	path = filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(contents), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	clearEnv(t)
+
+	t.Run("Defaults", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		cfg, err := LoadFile("")
+		assertions.Nil(err)
+		assertions.Equal(Default(), cfg)
+	})
+	t.Run("File", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		path := writeConfig(t, `
+database:
+  dsn: host=db user=fs
+  maxOpenConns: 20
+  connMaxLifetime: 5m
+log:
+  level: info
+blobs:
+  directory: /var/lib/fs
+quota:
+  bytes: 1073741824
+trash:
+  retention: 168h
+`)
+		cfg, err := LoadFile(path)
+		assertions.Nil(err)
+		assertions.Equal("host=db user=fs", cfg.Database.DSN)
+		assertions.Equal(20, cfg.Database.MaxOpenConns)
+		assertions.Equal(Default().Database.MaxIdleConns, cfg.Database.MaxIdleConns)
+		assertions.Equal(5*time.Minute, cfg.Database.ConnMaxLifetime)
+		assertions.Equal("info", cfg.Log.Level)
+		assertions.Equal("/var/lib/fs", cfg.Blobs.Directory)
+		assertions.Equal(uint64(1<<30), cfg.Quota.Bytes)
+		assertions.Zero(cfg.Quota.Files)
+		assertions.Equal(7*24*time.Hour, cfg.Trash.Retention)
+	})
+	t.Run("Empty file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		cfg, err := LoadFile(writeConfig(t, ""))
+		assertions.Nil(err)
+		assertions.Equal(Default(), cfg)
+	})
+	t.Run("Environment overrides file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		t.Setenv("FS_DATABASE_DSN", "host=env")
+		t.Setenv("FS_DATABASE_MAX_IDLE_CONNS", "5")
+		t.Setenv("FS_DATABASE_CONN_MAX_LIFETIME", "1h")
+		t.Setenv("FS_QUOTA_FILES", "1000")
+		t.Setenv("FS_TRASH_RETENTION", "24h")
+		cfg, err := LoadFile(writeConfig(t, "database:\n  dsn: host=file\n"))
+		assertions.Nil(err)
+		assertions.Equal("host=env", cfg.Database.DSN)
+		assertions.Equal(5, cfg.Database.MaxIdleConns)
+		assertions.Equal(time.Hour, cfg.Database.ConnMaxLifetime)
+		assertions.Equal(uint64(1000), cfg.Quota.Files)
+		assertions.Equal(24*time.Hour, cfg.Trash.Retention)
+	})
+	t.Run("Load uses FS_CONFIG", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		t.Setenv(FileEnv, writeConfig(t, "log:\n  level: error\n"))
+		cfg, err := Load()
+		assertions.Nil(err)
+		assertions.Equal("error", cfg.Log.Level)
+	})
+	t.Run("Missing file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assertions.ErrorIs(err, os.ErrNotExist)
+	})
+	t.Run("Unknown field", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		_, err := LoadFile(writeConfig(t, "database:\n  host: db\n"))
+		assertions.ErrorIs(err, ErrInvalidConfig)
+	})
+	t.Run("Invalid environment", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		t.Setenv("FS_DATABASE_MAX_OPEN_CONNS", "many")
+		_, err := LoadFile("")
+		assertions.ErrorIs(err, ErrInvalidConfig)
+
+		t.Setenv("FS_DATABASE_MAX_OPEN_CONNS", "")
+		os.Unsetenv("FS_DATABASE_MAX_OPEN_CONNS")
+		t.Setenv("FS_QUOTA_BYTES", "-1")
+		_, err = LoadFile("")
+		assertions.ErrorIs(err, ErrInvalidConfig)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		cfg := Default()
+		assertions.Nil(cfg.Validate())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var cases = map[string]func(cfg *Config){
+			"Empty DSN":         func(cfg *Config) { cfg.Database.DSN = "" },
+			"Negative open":     func(cfg *Config) { cfg.Database.MaxOpenConns = -1 },
+			"Negative idle":     func(cfg *Config) { cfg.Database.MaxIdleConns = -1 },
+			"Idle exceeds open": func(cfg *Config) { cfg.Database.MaxIdleConns = cfg.Database.MaxOpenConns + 1 },
+			"Negative lifetime": func(cfg *Config) { cfg.Database.ConnMaxLifetime = -time.Second },
+			"Unknown log level": func(cfg *Config) { cfg.Log.Level = "debug" },
+			"Zero retention":    func(cfg *Config) { cfg.Trash.Retention = 0 },
+		}
+		for name, modify := range cases {
+			t.Run(name, func(t *testing.T) {
+				assertions := assert.New(t)
+
+				cfg := Default()
+				modify(&cfg)
+				assertions.ErrorIs(cfg.Validate(), ErrInvalidConfig)
+			})
+		}
+	})
+}
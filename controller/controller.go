@@ -1,35 +1,65 @@
 package controller
 
 import (
+	"time"
+
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/config"
 	"github.com/hawks-atlanta/fs-prototype/database"
-	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
 	"gorm.io/gorm"
 )
 
 type Controller struct {
-	DB *gorm.DB
+	Store store.MetadataStore
+	// Optional local storage for the contents of the archives.
+	// When set, archives are only ready once their blob is stored
+	Blobs *blobstore.Store
+	// Default limits of the users without a quota of their own
+	Quota config.Quota
+	// Age of the trashed files removed by the periodic purges
+	TrashRetention time.Duration
 }
 
 func (c *Controller) Close() (err error) {
-	db, err := c.DB.DB()
-	if err == nil {
-		err = db.Close()
-	}
-	return err
+	return c.Store.Close()
 }
 
+// Uses the database through the GORM store, migrating its schema
 func New(db *gorm.DB) (c *Controller, err error) {
-	err = db.AutoMigrate(
-		&models.Archive{}, &models.File{}, &models.SharedFile{},
-	)
-	c = &Controller{db}
+	s, err := store.NewGORM(db)
+	c = NewWithStore(s)
 	return c, err
 }
 
+func NewWithStore(s store.MetadataStore) (c *Controller) {
+	return &Controller{Store: s, TrashRetention: DefaultTrashRetention}
+}
+
+// Opens the database of the configuration and, when a directory is configured, the blob store
+func FromConfig(cfg *config.Config) (c *Controller, err error) {
+	db, err := database.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err = New(db)
+	c.Quota = cfg.Quota
+	c.TrashRetention = cfg.Trash.Retention
+	if err == nil && cfg.Blobs.Directory != "" {
+		c.Blobs, err = blobstore.New(cfg.Blobs.Directory)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Uses the configuration returned by config.Load
 func Default() (c *Controller, err error) {
-	db, err := database.Default()
+	cfg, err := config.Load()
 	if err == nil {
-		c, err = New(db)
+		c, err = FromConfig(&cfg)
 	}
 	return c, err
 }
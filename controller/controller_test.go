@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/database"
+)
+
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
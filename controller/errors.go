@@ -0,0 +1,31 @@
+package controller
+
+import "errors"
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrNotDirectory     = errors.New("not a directory")
+	ErrIsDirectory      = errors.New("is a directory")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidPath      = errors.New("invalid path")
+	ErrAmbiguousPath    = errors.New("ambiguous path")
+	ErrInvalidShare     = errors.New("invalid share")
+	ErrInvalidGroup     = errors.New("invalid group")
+	ErrInvalidMove      = errors.New("invalid move")
+	ErrMoveCycle        = errors.New("can't move a directory inside itself")
+	// Returned by the operations using ConflictFail when the name is already taken in the directory
+	ErrNameConflict          = errors.New("name already in use")
+	ErrInvalidConflictPolicy = errors.New("invalid conflict policy")
+	ErrCopyTooLarge          = errors.New("too many files to copy")
+	// Returned when the operation would take the owner of the files over their limits
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// Returned along with the archive when its contents are still being uploaded
+	ErrArchiveNotReady = errors.New("archive not ready")
+	ErrBlobMissing     = errors.New("archive contents not stored")
+	ErrNoBlobStore     = errors.New("blob store not configured")
+	ErrArchiveReady    = errors.New("archive already ready")
+	ErrMissingChunks   = errors.New("missing chunks")
+	ErrInvalidChunk    = errors.New("invalid chunk")
+	ErrLinkExpired     = errors.New("link expired")
+	ErrDownloadLimit   = errors.New("download limit reached")
+)
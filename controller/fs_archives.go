@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+const (
+	// Minimum time an archive must stay unreferenced before being collected
+	DefaultArchiveGracePeriod = time.Hour
+	// Time an upload can stay pending before being expired
+	DefaultUploadExpiration = 24 * time.Hour
+)
+
+type MarkArchiveReady struct {
+	Hash string `json:"hash"`
+	Size uint64 `json:"size"`
+}
+
+// Flags the archive as ready once its contents are completely stored.
+// Intended to be called by the storage workers, or by UploadArchive when using the blob store
+func (c *Controller) MarkArchiveReady(mar *MarkArchiveReady) (archive models.Archive, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var err error
+		archive, err = tx.FindArchive(mar.Hash, mar.Size)
+		if err != nil {
+			return fmt.Errorf("failed to query archive: %w", err)
+		}
+		if archive.IsReady {
+			return nil
+		}
+		if c.Blobs != nil {
+			found, err := c.Blobs.Has(archive.Hash, archive.Size)
+			if err != nil {
+				return fmt.Errorf("failed to query blob: %w", err)
+			}
+			if !found {
+				return ErrBlobMissing
+			}
+		}
+		archive.IsReady = true
+		archive.UpdatedAt = time.Now()
+		err = tx.SaveArchive(&archive)
+		if err != nil {
+			return fmt.Errorf("failed to mark archive as ready: %w", err)
+		}
+		return nil
+	})
+	return archive, err
+}
+
+type UploadArchive struct {
+	Hash     string    `json:"hash"`
+	Size     uint64    `json:"size"`
+	Contents io.Reader `json:"-"`
+}
+
+// Stores the contents of an archive in the blob store and marks it as ready.
+// The archive must be already indexed by CreateFile or UpdateContent
+func (c *Controller) UploadArchive(ua *UploadArchive) (archive models.Archive, err error) {
+	if c.Blobs == nil {
+		return archive, ErrNoBlobStore
+	}
+	archive, err = c.Store.FindArchive(ua.Hash, ua.Size)
+	if err != nil {
+		return archive, fmt.Errorf("failed to query archive: %w", err)
+	}
+	if archive.IsReady {
+		return archive, nil
+	}
+	err = c.Blobs.Put(ua.Hash, ua.Size, ua.Contents)
+	if err != nil {
+		return archive, fmt.Errorf("failed to store archive contents: %w", err)
+	}
+	var mar = MarkArchiveReady{
+		Hash: ua.Hash,
+		Size: ua.Size,
+	}
+	return c.MarkArchiveReady(&mar)
+}
+
+type ExpireUploads struct {
+	Expiration time.Duration `json:"expiration,omitempty"`
+}
+
+type ExpiredUploads struct {
+	// Files removed from the index since they never had ready contents
+	Removed []models.File `json:"removed"`
+	// Files that went back to their last ready version
+	Reverted []models.File    `json:"reverted"`
+	Archives []models.Archive `json:"archives"`
+	// Upload sessions dropped along with the archives
+	Sessions []models.UploadSession `json:"sessions"`
+}
+
+// Sweeps the archives that stayed pending for longer than the expiration.
+// Files pointing to them are reverted to their last ready version, or removed when they don't have one
+func (c *Controller) ExpireUploads(eu *ExpireUploads) (expired ExpiredUploads, err error) {
+	var expiration = eu.Expiration
+	if expiration <= 0 {
+		expiration = DefaultUploadExpiration
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var err error
+		expired.Archives, err = tx.StaleArchives(time.Now().Add(-expiration))
+		if err != nil || len(expired.Archives) == 0 {
+			return err
+		}
+		var archiveUUIDs = make([]uuid.UUID, 0, len(expired.Archives))
+		for _, archive := range expired.Archives {
+			archiveUUIDs = append(archiveUUIDs, archive.UUID)
+		}
+
+		expired.Sessions, err = tx.UploadSessionsOf(archiveUUIDs)
+		if err != nil {
+			return err
+		}
+
+		files, err := tx.FilesWithArchives(archiveUUIDs)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			previous, err := lastReadyVersion(tx, file.UUID)
+			if err == nil {
+				file.ArchiveUUID = &previous.ArchiveUUID
+				err = tx.SaveFile(&file)
+				if err != nil {
+					return err
+				}
+				expired.Reverted = append(expired.Reverted, file)
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			err = tx.DeleteFiles(file.UUID)
+			if err != nil {
+				return err
+			}
+			expired.Removed = append(expired.Removed, file)
+		}
+
+		// Versions and upload sessions pointing to the archives are removed in cascade
+		return tx.DeleteArchives(archiveUUIDs...)
+	})
+	if err != nil {
+		return expired, fmt.Errorf("failed to expire uploads: %w", err)
+	}
+	err = c.deleteBlobs(expired.Archives)
+	if err != nil || c.Blobs == nil {
+		return expired, err
+	}
+	for _, session := range expired.Sessions {
+		err = c.Blobs.DeleteChunks(session.UUID.String())
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// Finds the newest version of the file whose contents are completely stored
+func lastReadyVersion(tx store.MetadataStore, fileUUID uuid.UUID) (version models.FileVersion, err error) {
+	versions, err := tx.ListVersions(fileUUID)
+	if err != nil {
+		return version, err
+	}
+	for _, version := range versions {
+		if version.Archive != nil && version.Archive.IsReady {
+			version.Archive = nil
+			return version, nil
+		}
+	}
+	return version, gorm.ErrRecordNotFound
+}
+
+type CollectArchives struct {
+	GracePeriod time.Duration `json:"gracePeriod,omitempty"`
+}
+
+type CollectedArchives struct {
+	Archives       []models.Archive `json:"archives"`
+	ReclaimedBytes uint64           `json:"reclaimedBytes"`
+}
+
+// Removes the archives no longer referenced by any file or version.
+// Archives touched during the grace period are kept, this covers uploads in flight and
+// files being created concurrently, since registering an archive always refreshes its update time.
+// The returned archives are the blobs the storage workers can safely delete,
+// they are removed directly when the controller has a blob store
+func (c *Controller) CollectArchives(ca *CollectArchives) (collected CollectedArchives, err error) {
+	var gracePeriod = ca.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultArchiveGracePeriod
+	}
+	collected.Archives, err = c.Store.CollectArchives(time.Now().Add(-gracePeriod))
+	if err != nil {
+		return collected, fmt.Errorf("failed to collect archives: %w", err)
+	}
+	for _, archive := range collected.Archives {
+		collected.ReclaimedBytes += archive.Size
+	}
+	err = c.deleteBlobs(collected.Archives)
+	return collected, err
+}
+
+// Removes the blobs of deleted archives from the blob store, if any.
+// Blobs are kept when the same contents were registered again in the meantime
+func (c *Controller) deleteBlobs(archives []models.Archive) (err error) {
+	if c.Blobs == nil {
+		return nil
+	}
+	for _, archive := range archives {
+		_, err = c.Store.FindArchive(archive.Hash, archive.Size)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to query archive: %w", err)
+		}
+		err = c.Blobs.Delete(archive.Hash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
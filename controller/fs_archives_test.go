@@ -0,0 +1,457 @@
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Makes the archive look untouched for the given duration
+func ageArchive(t *testing.T, c *Controller, archiveUUID uuid.UUID, age time.Duration) {
+	archive, err := c.Store.GetArchive(archiveUUID)
+	assert.Nil(t, err)
+	archive.UpdatedAt = time.Now().Add(-age)
+	err = c.Store.SaveArchive(&archive)
+	assert.Nil(t, err)
+}
+
+func markArchiveReady(t *testing.T, c *Controller, hash string, size uint64) {
+	var mar = MarkArchiveReady{
+		Hash: hash,
+		Size: size,
+	}
+	archive, err := c.MarkArchiveReady(&mar)
+	assert.Nil(t, err)
+	assert.True(t, archive.IsReady)
+}
+
+func collectedContains(collected CollectedArchives, archiveUUID uuid.UUID) bool {
+	for _, archive := range collected.Archives {
+		if archive.UUID == archiveUUID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestController_CollectArchives(t *testing.T) {
+	t.Run("Unreferenced archive", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "garbage.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: cf.OwnerUUID,
+			FileUUID:  file.UUID,
+			Permanent: true,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		ageArchive(t, c, *file.ArchiveUUID, 2*DefaultArchiveGracePeriod)
+
+		var ca CollectArchives
+		collected, err := c.CollectArchives(&ca)
+		assertions.Nil(err)
+
+		assertions.True(collectedContains(collected, *file.ArchiveUUID))
+		assertions.GreaterOrEqual(collected.ReclaimedBytes, cf.Size)
+
+		// Contents can be registered again
+		file, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+		markArchiveReady(t, c, cf.Hash, cf.Size)
+
+		var qf = QueryFile{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: file.UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.Nil(err)
+		assertions.Equal(cf.Hash, archive.Hash)
+	})
+	t.Run("Referenced archive", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner, uuid.NewString())
+		)
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+
+		// Current contents and the previous version
+		for _, version := range versions {
+			ageArchive(t, c, version.ArchiveUUID, 2*DefaultArchiveGracePeriod)
+		}
+
+		var ca CollectArchives
+		collected, err := c.CollectArchives(&ca)
+		assertions.Nil(err)
+
+		for _, version := range versions {
+			assertions.False(collectedContains(collected, version.ArchiveUUID))
+		}
+	})
+	t.Run("Grace period", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "garbage.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: cf.OwnerUUID,
+			FileUUID:  file.UUID,
+			Permanent: true,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		var ca CollectArchives
+		collected, err := c.CollectArchives(&ca)
+		assertions.Nil(err)
+
+		assertions.False(collectedContains(collected, *file.ArchiveUUID))
+	})
+}
+
+func TestController_MarkArchiveReady(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var qf = QueryFile{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: file.UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.ErrorIs(err, ErrArchiveNotReady)
+		assertions.False(archive.IsReady)
+		assertions.Equal(cf.Hash, archive.Hash)
+
+		markArchiveReady(t, c, cf.Hash, cf.Size)
+
+		archive, err = c.QueryFile(&qf)
+		assertions.Nil(err)
+		assertions.True(archive.IsReady)
+
+		// Same contents are ready immediately
+		cf.Filename = "copy.txt"
+		file, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		qf.FileUUID = file.UUID
+		_, err = c.QueryFile(&qf)
+		assertions.Nil(err)
+	})
+	t.Run("Unknown archive", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var mar = MarkArchiveReady{
+			Hash: utils.Hash(uuid.NewString()),
+			Size: 10,
+		}
+		_, err = c.MarkArchiveReady(&mar)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestController_ExpireUploads(t *testing.T) {
+	t.Run("Never completed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		ageArchive(t, c, *file.ArchiveUUID, 2*DefaultUploadExpiration)
+
+		var eu ExpireUploads
+		expired, err := c.ExpireUploads(&eu)
+		assertions.Nil(err)
+
+		var removed bool
+		for _, entry := range expired.Removed {
+			removed = removed || entry.UUID == file.UUID
+		}
+		assertions.True(removed)
+
+		_, err = c.Store.GetFile(file.UUID)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Revert to last version", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner    = uuid.New()
+			file     = createVersionedFile(t, c, owner)
+			contents = uuid.NewString()
+			uc       = UpdateContent{
+				UserUUID: owner,
+				FileUUID: file.UUID,
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+			}
+		)
+		version, err := c.UpdateContent(&uc)
+		assertions.Nil(err)
+
+		ageArchive(t, c, version.ArchiveUUID, 2*DefaultUploadExpiration)
+
+		var eu ExpireUploads
+		expired, err := c.ExpireUploads(&eu)
+		assertions.Nil(err)
+
+		var reverted bool
+		for _, entry := range expired.Reverted {
+			reverted = reverted || entry.UUID == file.UUID
+		}
+		assertions.True(reverted)
+
+		var qf = QueryFile{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.Nil(err)
+		assertions.Equal(utils.Hash("version 1"), archive.Hash)
+
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+		assertions.Len(versions, 1)
+	})
+	t.Run("Pending but recent", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var eu ExpireUploads
+		_, err = c.ExpireUploads(&eu)
+		assertions.Nil(err)
+
+		_, err = c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+	})
+}
+
+func TestController_UploadArchive(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		// Can't be ready without contents
+		var mar = MarkArchiveReady{
+			Hash: cf.Hash,
+			Size: cf.Size,
+		}
+		_, err = c.MarkArchiveReady(&mar)
+		assertions.ErrorIs(err, ErrBlobMissing)
+
+		// Contents must match
+		var ua = UploadArchive{
+			Hash:     cf.Hash,
+			Size:     cf.Size,
+			Contents: strings.NewReader(strings.ToUpper(contents)),
+		}
+		_, err = c.UploadArchive(&ua)
+		assertions.ErrorIs(err, blobstore.ErrHashMismatch)
+
+		ua.Contents = strings.NewReader(contents)
+		archive, err := c.UploadArchive(&ua)
+		assertions.Nil(err)
+		assertions.True(archive.IsReady)
+
+		var qf = QueryFile{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: file.UUID,
+		}
+		_, err = c.QueryFile(&qf)
+		assertions.Nil(err)
+
+		blob, err := c.Blobs.Open(cf.Hash)
+		assertions.Nil(err)
+		defer blob.Close()
+		buf, err := io.ReadAll(blob)
+		assertions.Nil(err)
+		assertions.Equal(contents, string(buf))
+	})
+	t.Run("Collect removes blobs", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var ua = UploadArchive{
+			Hash:     cf.Hash,
+			Size:     cf.Size,
+			Contents: strings.NewReader(contents),
+		}
+		_, err = c.UploadArchive(&ua)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: cf.OwnerUUID,
+			FileUUID:  file.UUID,
+			Permanent: true,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		ageArchive(t, c, *file.ArchiveUUID, 2*DefaultArchiveGracePeriod)
+
+		var ca CollectArchives
+		_, err = c.CollectArchives(&ca)
+		assertions.Nil(err)
+
+		found, err := c.Blobs.Has(cf.Hash, cf.Size)
+		assertions.Nil(err)
+		assertions.False(found)
+	})
+	t.Run("Without blob store", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = uuid.NewString()
+			ua       = UploadArchive{
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+				Contents: strings.NewReader(contents),
+			}
+		)
+		_, err = c.UploadArchive(&ua)
+		assertions.ErrorIs(err, ErrNoBlobStore)
+	})
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+// How to handle a file placed in a directory that already has a file with the same name
+type ConflictPolicy string
+
+const (
+	// Fails with ErrNameConflict
+	ConflictFail ConflictPolicy = "fail"
+	// Appends a counter to the name, like "notes (1).txt"
+	ConflictRename ConflictPolicy = "rename"
+	// Moves the existing file to the trash
+	ConflictReplace ConflictPolicy = "replace"
+	// Merges the contents of both directories, the conflicts inside them are merged too.
+	// Works like ConflictReplace when any of the files is not a directory
+	ConflictMerge ConflictPolicy = "merge"
+)
+
+// Whether the policy is known, empty policies use the default of each operation
+func (p ConflictPolicy) Valid() bool {
+	switch p {
+	case "", ConflictFail, ConflictRename, ConflictReplace, ConflictMerge:
+		return true
+	}
+	return false
+}
+
+func checkConflictPolicy(policy ConflictPolicy) error {
+	if !policy.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidConflictPolicy, policy)
+	}
+	return nil
+}
+
+// File placed in a directory, the file itself is nil when it is being created
+type placement struct {
+	OwnerUUID  uuid.UUID
+	ParentUUID *uuid.UUID
+	Name       string
+	File       *models.File
+	// Original of the file being copied, it can't be replaced by its own copy
+	CopyOf *uuid.UUID
+	// Directories being created can be merged too
+	IsDirectory bool
+}
+
+// Applies the policy when the name is already taken in the directory.
+// Returns the name the file must use, and the existing directory when the file must be merged into it
+func resolveConflict(tx store.MetadataStore, p *placement, policy ConflictPolicy) (name string, merge *models.File, err error) {
+	existing, err := tx.FindChild(p.OwnerUUID, p.ParentUUID, p.Name)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && p.File != nil && existing.UUID == p.File.UUID) {
+		return p.Name, nil, nil
+	}
+	if err != nil {
+		return p.Name, nil, fmt.Errorf("failed to check name availability: %w", err)
+	}
+	switch policy {
+	case ConflictRename:
+		name, err = availableName(tx, p)
+		if err != nil {
+			err = fmt.Errorf("failed to check name availability: %w", err)
+		}
+		return name, nil, err
+	case ConflictReplace, ConflictMerge:
+		var contents = p.CopyOf
+		if p.File != nil {
+			contents = &p.File.UUID
+		}
+		if contents != nil {
+			// The existing file can't be replaced by one of its own contents
+			hierarchy, err := tx.Ancestors(*contents)
+			if err != nil {
+				return p.Name, nil, fmt.Errorf("failed to query file hierarchy: %w", err)
+			}
+			for _, ancestor := range hierarchy {
+				if ancestor.UUID == existing.UUID {
+					return p.Name, nil, fmt.Errorf("%s: %w", existing.Name, ErrMoveCycle)
+				}
+			}
+		}
+		if policy == ConflictMerge && p.IsDirectory && existing.ArchiveUUID == nil {
+			return p.Name, &existing, nil
+		}
+		err = trashFile(tx, &existing)
+		if err != nil {
+			err = fmt.Errorf("failed to replace %s: %w", existing.Name, err)
+		}
+		return p.Name, nil, err
+	default:
+		return p.Name, nil, fmt.Errorf("%w: %q", ErrNameConflict, p.Name)
+	}
+}
+
+// Moves the contents of the directory into the target and removes the emptied directory.
+// Conflicting subdirectories are merged too, any other conflicting file of the target is trashed
+func mergeDirectory(tx store.MetadataStore, source, target *models.File) error {
+	children, err := tx.ListChildren(&store.ListChildren{
+		OwnerUUID:  source.OwnerUUID,
+		ParentUUID: &source.UUID,
+		SortBy:     store.SortByName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", source.Name, err)
+	}
+	for index := range children {
+		var child = &children[index]
+		name, merge, err := resolveConflict(tx, &placement{
+			OwnerUUID:   target.OwnerUUID,
+			ParentUUID:  &target.UUID,
+			Name:        child.Name,
+			File:        child,
+			IsDirectory: child.ArchiveUUID == nil,
+		}, ConflictMerge)
+		if err != nil {
+			return err
+		}
+		if merge != nil {
+			err = mergeDirectory(tx, child, merge)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		child.ParentUUID = &target.UUID
+		child.Name = name
+		err = tx.SaveFile(child)
+		if err != nil {
+			return fmt.Errorf("failed to move %s: %w", child.Name, nameConflict(err, child.Name))
+		}
+	}
+	return tx.DeleteFiles(source.UUID)
+}
+
+// Reports the violations of the unique names of the directories as name conflicts
+func nameConflict(err error, name string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: %q: %w", ErrNameConflict, name, err)
+	}
+	return err
+}
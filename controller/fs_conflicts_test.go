@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Names of the active children of the directory, or of the root of the owner
+func childNames(t *testing.T, c *Controller, ownerUUID uuid.UUID, parentUUID *uuid.UUID) (names []string) {
+	files, err := c.Store.ListChildren(&store.ListChildren{
+		OwnerUUID:  ownerUUID,
+		ParentUUID: parentUUID,
+		SortBy:     store.SortByName,
+	})
+	assert.Nil(t, err)
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestController_ConflictPolicies(t *testing.T) {
+	var contents = "fmt.Println(`hello`)"
+	t.Run("Fail", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var cf = CreateFile{Filename: "docs", OwnerUUID: uuid.New()}
+		dir, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		_, err = c.CreateFile(&cf)
+		assertions.ErrorIs(err, ErrNameConflict)
+
+		cf.ParentDirectory = &dir.UUID
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+		_, err = c.CreateFile(&cf)
+		assertions.ErrorIs(err, ErrNameConflict)
+
+		cf.Conflict = "overwrite"
+		_, err = c.CreateFile(&cf)
+		assertions.ErrorIs(err, ErrInvalidConflictPolicy)
+	})
+	t.Run("Rename", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var cf = CreateFile{
+			Filename:  "notes.txt",
+			OwnerUUID: uuid.New(),
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+			Conflict:  ConflictRename,
+		}
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		assertions.Equal("notes (1).txt", file.Name)
+
+		dir, err := c.CreateFile(&CreateFile{Filename: "notes.txt (1)", OwnerUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+		err = c.MoveFile(&MoveFile{OwnerUUID: cf.OwnerUUID, FileUUID: dir.UUID, NewName: &cf.Filename, Conflict: ConflictRename})
+		assertions.Nil(err)
+		assertions.Equal([]string{"notes (1).txt", "notes.txt", "notes.txt (1)"}, childNames(t, c, cf.OwnerUUID, nil))
+	})
+	t.Run("Replace", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var cf = CreateFile{
+			Filename:  "notes.txt",
+			OwnerUUID: uuid.New(),
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+		}
+		old, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		cf.Conflict = ConflictReplace
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		assertions.NotEqual(old.UUID, file.UUID)
+
+		// The replaced file can be recovered from the trash
+		trash, err := c.ListTrash(&ListTrash{OwnerUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+		assertions.Len(trash, 1)
+		assertions.Equal(old.UUID, trash[0].UUID)
+
+		_, err = c.RestoreFile(&RestoreFile{OwnerUUID: cf.OwnerUUID, FileUUID: old.UUID, Conflict: ConflictFail})
+		assertions.ErrorIs(err, ErrNameConflict)
+		restored, err := c.RestoreFile(&RestoreFile{OwnerUUID: cf.OwnerUUID, FileUUID: old.UUID})
+		assertions.Nil(err)
+		assertions.Equal("notes (1).txt", restored.Name)
+
+		// Files can't replace the directories containing them
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+		var name = "docs"
+		err = c.MoveFile(&MoveFile{OwnerUUID: cf.OwnerUUID, FileUUID: file.UUID, NewLocation: &dir.UUID})
+		assertions.Nil(err)
+		err = c.MoveFile(&MoveFile{OwnerUUID: cf.OwnerUUID, FileUUID: file.UUID, ToRoot: true, NewName: &name, Conflict: ConflictReplace})
+		assertions.ErrorIs(err, ErrMoveCycle)
+	})
+	t.Run("Merge", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		target, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		targetNested, err := c.CreateFile(&CreateFile{Filename: "nested", OwnerUUID: owner, ParentDirectory: &target.UUID})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "old.txt", OwnerUUID: owner, ParentDirectory: &targetNested.UUID})
+		assertions.Nil(err)
+
+		// Creating an existing directory returns it
+		merged, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner, Conflict: ConflictMerge})
+		assertions.Nil(err)
+		assertions.Equal(target.UUID, merged.UUID)
+
+		source, err := c.CreateFile(&CreateFile{Filename: "backup", OwnerUUID: owner})
+		assertions.Nil(err)
+		sourceNested, err := c.CreateFile(&CreateFile{Filename: "nested", OwnerUUID: owner, ParentDirectory: &source.UUID})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "new.txt", OwnerUUID: owner, ParentDirectory: &sourceNested.UUID})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "notes.txt", OwnerUUID: owner, ParentDirectory: &source.UUID})
+		assertions.Nil(err)
+
+		var name = "docs"
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: source.UUID, NewName: &name, Conflict: ConflictMerge})
+		assertions.Nil(err)
+		_, err = c.Store.GetFile(source.UUID)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		assertions.Equal([]string{"docs"}, childNames(t, c, owner, nil))
+		assertions.Equal([]string{"nested", "notes.txt"}, childNames(t, c, owner, &target.UUID))
+		assertions.Equal([]string{"new.txt", "old.txt"}, childNames(t, c, owner, &targetNested.UUID))
+	})
+	t.Run("Restore", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var owner = dir.OwnerUUID
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: owner, FileUUID: dir.UUID}))
+		other, err := c.CreateFile(&CreateFile{Filename: dir.Name, OwnerUUID: owner})
+		assertions.Nil(err)
+
+		restored, err := c.RestoreFile(&RestoreFile{OwnerUUID: owner, FileUUID: dir.UUID, Conflict: ConflictMerge})
+		assertions.Nil(err)
+		assertions.Equal(other.UUID, restored.UUID)
+		moved, err := c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(other.UUID, *moved.ParentUUID)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		// Half of the placements create the name in the root and the other half move a file there
+		var (
+			owner = uuid.New()
+			wg    sync.WaitGroup
+			errs  = make([]error, 10)
+			name  = "docs"
+		)
+		dir, err := c.CreateFile(&CreateFile{Filename: "dir", OwnerUUID: owner})
+		assertions.Nil(err)
+		var moved = make([]models.File, len(errs)/2)
+		for index := range moved {
+			moved[index], err = c.CreateFile(&CreateFile{
+				Filename:        fmt.Sprintf("%d", index),
+				OwnerUUID:       owner,
+				ParentDirectory: &dir.UUID,
+			})
+			assertions.Nil(err)
+		}
+		for index := range errs {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				if index < len(moved) {
+					errs[index] = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: moved[index].UUID, ToRoot: true, NewName: &name})
+					return
+				}
+				_, errs[index] = c.CreateFile(&CreateFile{Filename: name, OwnerUUID: owner})
+			}(index)
+		}
+		wg.Wait()
+
+		var placed int
+		for _, err := range errs {
+			if err == nil {
+				placed++
+			} else {
+				assertions.ErrorIs(err, ErrNameConflict)
+			}
+		}
+		assertions.Equal(1, placed)
+		assertions.Equal([]string{"dir", "docs"}, childNames(t, c, owner, nil))
+	})
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+)
+
+// Maximum number of files copied at once, directories included
+const MaxCopyFiles = 1000
+
+type CopyFile struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+	// Directory to copy the file into, the root of the user when nil
+	NewLocation *uuid.UUID `json:"newLocation,omitempty"`
+	// Keeps the name of the original when nil
+	NewName *string `json:"newName,omitempty"`
+	// ConflictFail when empty, merging a directory copies its contents into the existing one
+	Conflict ConflictPolicy `json:"conflict,omitempty"`
+}
+
+// Duplicates the file, or the directory with all its contents, in another location.
+// The copies point to the same archives as the originals, so they take no extra storage.
+// Any file the user can read can be copied into their root or a directory they can edit,
+// the copies belong to the owner of the destination.
+// Subtrees larger than MaxCopyFiles are rejected with ErrCopyTooLarge.
+// The copies count fully against the quota of their owner, ErrQuotaExceeded is returned when they don't fit.
+// Names already taken in the destination are handled according to the conflict policy
+func (c *Controller) CopyFile(cf *CopyFile) (copied models.File, err error) {
+	err = checkConflictPolicy(cf.Conflict)
+	if err != nil {
+		return copied, err
+	}
+	if cf.NewName != nil && *cf.NewName == "" {
+		return copied, fmt.Errorf("%w: name can't be empty", ErrInvalidMove)
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		source, err := accessibleActiveFile(tx, cf.UserUUID, cf.FileUUID, models.RoleViewer)
+		if err != nil {
+			return err
+		}
+		var (
+			ownerUUID   = cf.UserUUID
+			creatorUUID *uuid.UUID
+		)
+		if cf.NewLocation != nil && *cf.NewLocation != uuid.Nil {
+			location, err := accessibleActiveFile(tx, cf.UserUUID, *cf.NewLocation, models.RoleEditor)
+			if err != nil {
+				return fmt.Errorf("failed to query destination: %w", err)
+			}
+			if location.ArchiveUUID != nil {
+				return fmt.Errorf("destination %s: %w", location.Name, ErrNotDirectory)
+			}
+			if location.OwnerUUID != cf.UserUUID {
+				ownerUUID = location.OwnerUUID
+				creatorUUID = &cf.UserUUID
+			}
+		}
+
+		files, err := tx.Subtree(&store.Subtree{RootUUID: source.UUID, Limit: MaxCopyFiles + 1})
+		if err != nil {
+			return fmt.Errorf("failed to query files to copy: %w", err)
+		}
+		if len(files) > MaxCopyFiles {
+			return fmt.Errorf("%w: more than %d files", ErrCopyTooLarge, MaxCopyFiles)
+		}
+
+		usage, err := c.lockedUsageOf(tx, ownerUUID)
+		if err != nil {
+			return err
+		}
+
+		// Files are sorted by depth so parents are always copied before their children.
+		// Only the root and the contents of merged directories can collide with existing files
+		var (
+			copies   = make(map[uuid.UUID]uuid.UUID, len(files))
+			existing = make(map[uuid.UUID]bool)
+			bytes    uint64
+			created  uint64
+		)
+		for index, file := range files {
+			var (
+				parentUUID = cf.NewLocation
+				name       = file.Name
+				check      = index == 0
+			)
+			if index == 0 {
+				if cf.NewName != nil {
+					name = *cf.NewName
+				}
+			} else {
+				parent, found := copies[*file.ParentUUID]
+				if !found {
+					continue
+				}
+				parentUUID = &parent
+				check = existing[parent]
+			}
+			if parentUUID != nil && *parentUUID == uuid.Nil {
+				parentUUID = nil
+			}
+			if check {
+				resolved, merge, err := resolveConflict(tx, &placement{
+					OwnerUUID:   ownerUUID,
+					ParentUUID:  parentUUID,
+					Name:        name,
+					CopyOf:      &file.UUID,
+					IsDirectory: file.ArchiveUUID == nil,
+				}, cf.Conflict)
+				if err != nil {
+					return err
+				}
+				if merge != nil {
+					copies[file.UUID] = merge.UUID
+					existing[merge.UUID] = true
+					if index == 0 {
+						copied = *merge
+					}
+					continue
+				}
+				name = resolved
+			}
+			var duplicate = models.File{
+				OwnerUUID:   ownerUUID,
+				ParentUUID:  parentUUID,
+				ArchiveUUID: file.ArchiveUUID,
+				Name:        name,
+				CreatorUUID: creatorUUID,
+			}
+			err = tx.CreateFile(&duplicate)
+			if err != nil {
+				return fmt.Errorf("failed to copy %s: %w", file.Name, nameConflict(err, name))
+			}
+			if duplicate.ArchiveUUID != nil {
+				_, err = createVersion(tx, &duplicate, cf.UserUUID)
+				if err != nil {
+					return err
+				}
+				bytes += file.Archive.Size
+			}
+			created++
+			copies[file.UUID] = duplicate.UUID
+			if index == 0 {
+				copied = duplicate
+			}
+		}
+		// Replaced files keep counting from the trash, so the copies are charged in full
+		return usage.reserve(bytes, created)
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to copy file: %w", err)
+	}
+	return copied, err
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestController_CopyFile(t *testing.T) {
+	var contents = "fmt.Println(`hello`)"
+	t.Run("Directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		nested, err := c.CreateFile(&CreateFile{Filename: "nested", OwnerUUID: owner, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+		file, err := c.CreateFile(&CreateFile{
+			Filename:        "notes.txt",
+			OwnerUUID:       owner,
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &nested.UUID,
+		})
+		assertions.Nil(err)
+
+		var name = "backup"
+		copied, err := c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: dir.UUID, NewName: &name})
+		assertions.Nil(err)
+		assertions.Equal(name, copied.Name)
+		assertions.Nil(copied.ParentUUID)
+		assertions.Equal([]string{"backup", "docs"}, childNames(t, c, owner, nil))
+
+		// The copies share the archives of the originals
+		files, err := c.Tree(&Tree{UserUUID: owner, RootUUID: copied.UUID})
+		assertions.Nil(err)
+		assertions.Len(files.Children, 1)
+		assertions.Len(files.Children[0].Children, 1)
+		var duplicate = files.Children[0].Children[0]
+		assertions.NotEqual(file.UUID, duplicate.UUID)
+		assertions.Equal(file.Name, duplicate.Name)
+		assertions.Equal(*file.ArchiveUUID, *duplicate.ArchiveUUID)
+
+		versions, err := c.Store.ListVersions(duplicate.UUID)
+		assertions.Nil(err)
+		assertions.Len(versions, 1)
+
+		// The originals are left untouched
+		assertions.Equal([]string{"notes.txt"}, childNames(t, c, owner, &nested.UUID))
+
+		// Directories can be copied inside themselves, the copy is not copied again
+		inside, err := c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: dir.UUID, NewLocation: &nested.UUID})
+		assertions.Nil(err)
+		assertions.Equal([]string{"nested"}, childNames(t, c, owner, &inside.UUID))
+	})
+	t.Run("Shared", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, viewer := createSharedDirectory(t, c, models.RoleViewer)
+
+		// Viewers can copy into their own files
+		copied, err := c.CopyFile(&CopyFile{UserUUID: viewer, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		assertions.Equal(viewer, copied.OwnerUUID)
+		assertions.Nil(copied.CreatorUUID)
+		assertions.Equal([]string{file.Name}, childNames(t, c, viewer, &copied.UUID))
+
+		_, err = c.CopyFile(&CopyFile{UserUUID: viewer, FileUUID: file.UUID, NewLocation: &dir.UUID, Conflict: ConflictRename})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		// Copies into shared directories belong to their owner
+		var editor = uuid.New()
+		assertions.Nil(c.ShareFile(&ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: editor,
+			Role:           models.RoleEditor,
+		}))
+		copied, err = c.CopyFile(&CopyFile{UserUUID: editor, FileUUID: file.UUID, NewLocation: &dir.UUID, Conflict: ConflictRename})
+		assertions.Nil(err)
+		assertions.Equal(dir.OwnerUUID, copied.OwnerUUID)
+		assertions.Equal(editor, *copied.CreatorUUID)
+		assertions.Equal("hello-world (1).go", copied.Name)
+
+		// Destinations must be directories
+		_, err = c.CopyFile(&CopyFile{UserUUID: dir.OwnerUUID, FileUUID: dir.UUID, NewLocation: &file.UUID})
+		assertions.ErrorIs(err, ErrNotDirectory)
+	})
+	t.Run("Conflicts", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		source, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "new.txt", OwnerUUID: owner, ParentDirectory: &source.UUID})
+		assertions.Nil(err)
+
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID})
+		assertions.ErrorIs(err, ErrNameConflict)
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID, Conflict: ConflictMerge})
+		assertions.ErrorIs(err, ErrMoveCycle)
+		renamed, err := c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID, Conflict: ConflictRename})
+		assertions.Nil(err)
+		assertions.Equal("docs (1)", renamed.Name)
+
+		var empty = ""
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID, NewName: &empty})
+		assertions.ErrorIs(err, ErrInvalidMove)
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID, Conflict: "overwrite"})
+		assertions.ErrorIs(err, ErrInvalidConflictPolicy)
+
+		// Merging copies the contents into the existing directory
+		target, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner, ParentDirectory: &renamed.UUID})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "old.txt", OwnerUUID: owner, ParentDirectory: &target.UUID})
+		assertions.Nil(err)
+		merged, err := c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: source.UUID, NewLocation: &renamed.UUID, Conflict: ConflictMerge})
+		assertions.Nil(err)
+		assertions.Equal(target.UUID, merged.UUID)
+		assertions.Equal([]string{"new.txt", "old.txt"}, childNames(t, c, owner, &target.UUID))
+		assertions.Equal([]string{"new.txt"}, childNames(t, c, owner, &source.UUID))
+	})
+	t.Run("Too large", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		for index := 0; index < MaxCopyFiles; index++ {
+			assertions.Nil(c.Store.CreateFile(&models.File{
+				OwnerUUID:  owner,
+				ParentUUID: &dir.UUID,
+				Name:       fmt.Sprintf("%d", index),
+			}))
+		}
+
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: dir.UUID, Conflict: ConflictRename})
+		assertions.ErrorIs(err, ErrCopyTooLarge)
+		assertions.Equal([]string{"docs"}, childNames(t, c, owner, nil))
+	})
+}
@@ -1,88 +1,119 @@
 package controller
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
 	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type CreateFile struct {
-	Filename        string     `json:"filename"`
-	OwnerUUID       uuid.UUID  `json:"ownerUUID"`
-	Hash            string     `json:"hash,omitempty"`
+	Filename  string    `json:"filename"`
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	Hash      string    `json:"hash,omitempty"`
+	// The file is created in the root when nil or the nil UUID
 	ParentDirectory *uuid.UUID `json:"parentDirectory,omitempty"`
 	Size            uint64     `json:"size,omitempty"`
+	// ConflictFail when empty, merging a new directory returns the existing one
+	Conflict ConflictPolicy `json:"conflict,omitempty"`
 }
 
-// Creates a new file in the filesystem index
+// Creates a new file in the filesystem index.
+// New archives start pending until their contents are uploaded and MarkArchiveReady is called,
+// files with contents already in the index are ready immediately.
+// Editors and managers of a shared directory can create files inside it. Those files belong to the
+// owner of the directory and count against their storage, the editor is only recorded as creator.
+// The files stay with the owner when the share is revoked.
+// Names already taken in the directory are handled according to the conflict policy,
+// parents that are regular files are rejected with ErrNotDirectory.
+// Fails with ErrQuotaExceeded when the owner has no room for the file,
+// and with blobstore.ErrInvalidHash when the contents have a hash the blob store can't hold
 func (c *Controller) CreateFile(cf *CreateFile) (file models.File, err error) {
+	err = checkConflictPolicy(cf.Conflict)
+	if err != nil {
+		return file, err
+	}
+	if cf.Size > 0 {
+		err = blobstore.CheckHash(cf.Hash)
+		if err != nil {
+			return file, err
+		}
+	}
 
-	// Make sure current user is owner of the directory
-	if cf.ParentDirectory != nil && *cf.ParentDirectory != uuid.Nil {
-		var parentDirectory models.File
-		err = c.DB.
-			Where("uuid = ? AND owner_uuid = ?", *cf.ParentDirectory, cf.OwnerUUID).
-			First(&parentDirectory).
-			Error
+	// Make sure current user can write to the directory
+	var (
+		ownerUUID   = cf.OwnerUUID
+		creatorUUID *uuid.UUID
+		parentUUID  = cf.ParentDirectory
+	)
+	if parentUUID != nil && *parentUUID == uuid.Nil {
+		parentUUID = nil
+	}
+	if parentUUID != nil {
+		parent, err := accessibleActiveFile(c.Store, cf.OwnerUUID, *parentUUID, models.RoleEditor)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				err = fmt.Errorf("user doesn't own directory: %w", err)
+			if errors.Is(err, ErrPermissionDenied) || errors.Is(err, gorm.ErrRecordNotFound) {
+				err = fmt.Errorf("user can't write to directory: %w", err)
 			} else {
-				err = fmt.Errorf("something went wrong while checking ownership of parent directory: %w", err)
+				err = fmt.Errorf("something went wrong while checking access to parent directory: %w", err)
 			}
 			return file, err
 		}
+		if parent.ArchiveUUID != nil {
+			return file, fmt.Errorf("parent %s: %w", parent.Name, ErrNotDirectory)
+		}
+		if parent.OwnerUUID != cf.OwnerUUID {
+			ownerUUID = parent.OwnerUUID
+			creatorUUID = &cf.OwnerUUID
+		}
 	}
 
-	if cf.Size != 0 { // Create file
-		err = c.DB.Transaction(func(tx *gorm.DB) (err error) {
-			var archive models.Archive = models.Archive{
-				Hash: cf.Hash,
-				Size: cf.Size,
-			}
-			err = tx.
-				Clauses(clause.OnConflict{DoNothing: true}).
-				Create(&archive).
-				Error
-			if err != nil {
-				return err
-			}
-			archive = models.Archive{}
-			err = tx.
-				Where("hash = ? AND size = ?", cf.Hash, cf.Size).
-				First(&archive).
-				Error
-			if err != nil {
-				return err
-			}
-			file = models.File{
-				OwnerUUID:   cf.OwnerUUID,
-				ArchiveUUID: &archive.UUID,
-				ParentUUID:  cf.ParentDirectory,
-				Name:        cf.Filename,
-			}
-			err = tx.
-				Create(&file).
-				Error
+	err = c.Store.Transaction(func(tx store.MetadataStore) (err error) {
+		name, merge, err := resolveConflict(tx, &placement{
+			OwnerUUID:   ownerUUID,
+			ParentUUID:  parentUUID,
+			Name:        cf.Filename,
+			IsDirectory: cf.Size == 0,
+		}, cf.Conflict)
+		if err != nil {
 			return err
-		})
-	} else { // Create directory
-		err = c.DB.Transaction(func(tx *gorm.DB) error {
-			file = models.File{
-				OwnerUUID:  cf.OwnerUUID,
-				ParentUUID: cf.ParentDirectory,
-				Name:       cf.Filename,
-			}
-			err = tx.
-				Create(&file).
-				Error
+		}
+		if merge != nil {
+			file = *merge
+			return nil
+		}
+		err = c.reserve(tx, ownerUUID, cf.Size, 1)
+		if err != nil {
 			return err
-		})
-	}
+		}
+		file = models.File{
+			OwnerUUID:   ownerUUID,
+			ParentUUID:  parentUUID,
+			Name:        name,
+			CreatorUUID: creatorUUID,
+		}
+		if cf.Size == 0 { // Create directory
+			return nameConflict(tx.CreateFile(&file), name)
+		}
+		archive, err := tx.TouchArchive(cf.Hash, cf.Size)
+		if err != nil {
+			return err
+		}
+		file.ArchiveUUID = &archive.UUID
+		err = tx.CreateFile(&file)
+		if err != nil {
+			return nameConflict(err, name)
+		}
+		_, err = createVersion(tx, &file, cf.OwnerUUID)
+		return err
+	})
 	if err != nil {
 		err = fmt.Errorf("failed to insert file: %w", err)
 	}
@@ -92,18 +123,158 @@ func (c *Controller) CreateFile(cf *CreateFile) (file models.File, err error) {
 type DeleteFile struct {
 	OwnerUUID uuid.UUID `json:"ownerUUID"`
 	FileUUID  uuid.UUID `json:"fileUUID"`
+	Permanent bool      `json:"permanent,omitempty"`
+}
+
+type SortBy string
+
+const (
+	SortByName      SortBy = "name"
+	SortBySize      SortBy = "size"
+	SortByCreatedAt SortBy = "createdAt"
+)
+
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+type ListDirectory struct {
+	UserUUID   uuid.UUID  `json:"userUUID"`
+	ParentUUID *uuid.UUID `json:"parentUUID,omitempty"`
+	SortBy     SortBy     `json:"sortBy,omitempty"`
+	Descending bool       `json:"descending,omitempty"`
+	Cursor     string     `json:"cursor,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+}
+
+type Directory struct {
+	Files      []models.File `json:"files"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// Position of the last entry of a page, used to resume the listing
+type listCursor struct {
+	SortBy     SortBy    `json:"o"`
+	Descending bool      `json:"d,omitempty"`
+	Name       string    `json:"n,omitempty"`
+	Size       uint64    `json:"s,omitempty"`
+	CreatedAt  time.Time `json:"c,omitempty"`
+	UUID       uuid.UUID `json:"u"`
+}
+
+func (lc *listCursor) encode() string {
+	buf, _ := json.Marshal(lc)
+	return base64.RawURLEncoding.EncodeToString(buf)
 }
 
-// TODO: List directory
+func decodeListCursor(s string) (lc listCursor, err error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(buf, &lc)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return lc, err
+}
+
+// Lists the children of a directory, or the root of the user when no parent is given.
+// Recipients of a share can list any directory they can read
+func (c *Controller) ListDirectory(ld *ListDirectory) (dir Directory, err error) {
+	var (
+		sortBy = ld.SortBy
+		limit  = ld.Limit
+	)
+	if sortBy == "" {
+		sortBy = SortByName
+	}
+	if limit <= 0 {
+		limit = DefaultListLimit
+	} else if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	switch sortBy {
+	case SortByName, SortBySize, SortByCreatedAt:
+	default:
+		return dir, fmt.Errorf("unknown sort field: %s", sortBy)
+	}
+
+	var lc = store.ListChildren{
+		OwnerUUID:  ld.UserUUID,
+		SortBy:     store.SortField(sortBy),
+		Descending: ld.Descending,
+		Limit:      limit + 1,
+	}
+	if ld.ParentUUID != nil && *ld.ParentUUID != uuid.Nil {
+		parent, err := c.Store.GetFile(*ld.ParentUUID)
+		if err != nil {
+			return dir, fmt.Errorf("failed to query directory: %w", err)
+		}
+		if parent.ArchiveUUID != nil {
+			return dir, ErrNotDirectory
+		}
+		var crf = CanReadFile{
+			UserUUID: ld.UserUUID,
+			FileUUID: parent.UUID,
+		}
+		err = c.CanReadFile(&crf)
+		if err != nil {
+			return dir, err
+		}
+		lc.ParentUUID = &parent.UUID
+	}
+
+	if ld.Cursor != "" {
+		cursor, err := decodeListCursor(ld.Cursor)
+		if err != nil {
+			return dir, err
+		}
+		if cursor.SortBy != sortBy || cursor.Descending != ld.Descending {
+			return dir, fmt.Errorf("%w: cursor doesn't match requested order", ErrInvalidCursor)
+		}
+		lc.After = &store.Position{
+			Name:      cursor.Name,
+			Size:      cursor.Size,
+			CreatedAt: cursor.CreatedAt,
+			UUID:      cursor.UUID,
+		}
+	}
+
+	dir.Files, err = c.Store.ListChildren(&lc)
+	if err != nil {
+		return dir, fmt.Errorf("failed to list directory: %w", err)
+	}
+	if len(dir.Files) > limit {
+		dir.Files = dir.Files[:limit]
+		last := dir.Files[limit-1]
+		cursor := listCursor{
+			SortBy:     sortBy,
+			Descending: ld.Descending,
+			Name:       last.Name,
+			CreatedAt:  last.CreatedAt,
+			UUID:       last.UUID,
+		}
+		if last.Archive != nil {
+			cursor.Size = last.Archive.Size
+		}
+		dir.NextCursor = cursor.encode()
+	}
+	return dir, nil
+}
 
 type QueryFile struct {
-	UserUUID uuid.UUID `json:"userUUID"`
-	FileUUID uuid.UUID `json:"fileUUID"`
+	UserUUID    uuid.UUID  `json:"userUUID"`
+	FileUUID    uuid.UUID  `json:"fileUUID"`
+	VersionUUID *uuid.UUID `json:"versionUUID,omitempty"`
 }
 
 // Intended to only be used by the Gateway
 // The server checks if the user owns the file.
-// If not the server tries to determine the access to the file by shared files with this account
+// If not the server tries to determine the access to the file by shared files with this account.
+// When a version is requested its archive is returned instead of the current one.
+// Archives still being uploaded are returned along with ErrArchiveNotReady
 func (c *Controller) QueryFile(qf *QueryFile) (archive models.Archive, err error) {
 	var crf = CanReadFile{
 		UserUUID: qf.UserUUID,
@@ -113,59 +284,180 @@ func (c *Controller) QueryFile(qf *QueryFile) (archive models.Archive, err error
 	if err != nil {
 		return archive, err
 	}
-	err = c.DB.
-		Raw(`
-		SELECT archives.* 
-		FROM archives, files 
-		WHERE
-			archives.uuid = files.archive_uuid 
-			AND files.uuid = ?
-		LIMIT 1`, qf.FileUUID).
-		Scan(&archive).
-		Error
+	if qf.VersionUUID != nil {
+		version, err := c.Store.GetVersion(*qf.VersionUUID)
+		if err == nil && version.FileUUID != qf.FileUUID {
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil {
+			return archive, fmt.Errorf("failed to query version: %w", err)
+		}
+		archive, err = c.Store.GetArchive(version.ArchiveUUID)
+		if err == nil && !archive.IsReady {
+			err = ErrArchiveNotReady
+		}
+		return archive, err
+	}
+	file, err := c.Store.GetFile(qf.FileUUID)
+	if err != nil || file.ArchiveUUID == nil {
+		return archive, err
+	}
+	archive, err = c.Store.GetArchive(*file.ArchiveUUID)
+	if err == nil && !archive.IsReady {
+		err = ErrArchiveNotReady
+	}
 	return archive, err
 }
 
-// Deletes file from the index
+type GetFile struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+}
+
+// Returns the metadata of a file the user can read.
+// Owners can also query their files in the trash
+func (c *Controller) GetFile(gf *GetFile) (file models.File, err error) {
+	return accessibleFile(c.Store, gf.UserUUID, gf.FileUUID, models.RoleViewer)
+}
+
+// Moves the file to the trash of its owner, directories are trashed with all their contents.
+// Editors of shared content can trash it too.
+// When Permanent is set the file is removed from the index instead, only its owner can do it
 func (c *Controller) DeleteFile(df *DeleteFile) (err error) {
+	if df.Permanent {
+		err = c.Store.Transaction(func(tx store.MetadataStore) error {
+			file, err := ownedFile(tx, df.OwnerUUID, df.FileUUID)
+			if err != nil {
+				return err
+			}
+			return tx.DeleteFiles(file.UUID)
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to delete file: %w", err)
+		}
+		return err
+	}
 
-	err = c.DB.
-		Where("uuid = ? AND owner_uuid = ?", df.FileUUID, df.OwnerUUID).
-		Delete(&models.File{}).
-		Error
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := accessibleActiveFile(tx, df.OwnerUUID, df.FileUUID, models.RoleEditor)
+		if err != nil {
+			return err
+		}
+		return trashFile(tx, &file)
+	})
 	if err != nil {
-		err = fmt.Errorf("failed to delete file: %w", err)
+		err = fmt.Errorf("failed to move file to trash: %w", err)
 	}
 	return err
 }
 
+// Detaches the file from its parent and moves it to the trash of its owner
+func trashFile(tx store.MetadataStore, file *models.File) error {
+	var now = time.Now()
+	file.TrashedAt = &now
+	file.TrashedFromUUID = file.ParentUUID
+	file.ParentUUID = nil
+	return tx.SaveFile(file)
+}
+
 type MoveFile struct {
-	OwnerUUID   uuid.UUID  `json:"ownerUUID"`
-	FileUUID    uuid.UUID  `json:"fileUUID"`
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	FileUUID  uuid.UUID `json:"fileUUID"`
+	// Directory to move the file into, the file stays where it is when nil unless ToRoot is set.
+	// The nil UUID refers to the root, like in CreateFile and CopyFile
 	NewLocation *uuid.UUID `json:"newLocation,omitempty"`
-	NewName     *string    `json:"newName,omitempty"`
+	// Moves the file to the root of its owner, can't be combined with NewLocation
+	ToRoot  bool    `json:"toRoot,omitempty"`
+	NewName *string `json:"newName,omitempty"`
+	// ConflictFail when empty. Directories merged into an existing one are removed once emptied
+	Conflict ConflictPolicy `json:"conflict,omitempty"`
 }
 
+// Renames the file or moves it to another directory of the same owner.
+// Editors of shared content can move it between the directories they can edit,
+// only the owner can move it to the root.
+// Destinations that are regular files are rejected with ErrNotDirectory,
+// and directories can't be moved inside themselves or their descendants.
+// Names already taken in the destination are handled according to the conflict policy
 func (c *Controller) MoveFile(mf *MoveFile) (err error) {
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		query := tx.
-			Model(&models.File{}).
-			Where("uuid = ? AND owner_uuid = ?", mf.FileUUID, mf.OwnerUUID)
-		if mf.NewLocation != nil {
-			var file models.File
-			err = tx.
-				Where("uuid = ? AND owner_uuid = ?", *mf.NewLocation, mf.OwnerUUID).
-				First(&file).
-				Error
+	err = checkConflictPolicy(mf.Conflict)
+	if err != nil {
+		return err
+	}
+	var (
+		toRoot      = mf.ToRoot
+		newLocation = mf.NewLocation
+	)
+	if newLocation != nil && *newLocation == uuid.Nil {
+		toRoot, newLocation = true, nil
+	}
+	if mf.ToRoot && newLocation != nil {
+		return fmt.Errorf("%w: either move to the root or to a directory", ErrInvalidMove)
+	}
+	if mf.NewName != nil && *mf.NewName == "" {
+		return fmt.Errorf("%w: name can't be empty", ErrInvalidMove)
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := accessibleActiveFile(tx, mf.OwnerUUID, mf.FileUUID, models.RoleEditor)
+		if err != nil {
+			return err
+		}
+		switch {
+		case toRoot:
+			if file.OwnerUUID != mf.OwnerUUID {
+				return fmt.Errorf("%w: only the owner can move files to the root", ErrPermissionDenied)
+			}
+			file.ParentUUID = nil
+		case newLocation != nil:
+			location, err := moveDestination(tx, &file, mf.OwnerUUID, *newLocation)
 			if err != nil {
 				return err
 			}
-			err = query.Update("parent_uuid", file.UUID).Error
+			file.ParentUUID = &location.UUID
 		}
-		if err == nil && mf.NewName != nil {
-			err = query.Update("name", *mf.NewName).Error
+		var name = file.Name
+		if mf.NewName != nil {
+			name = *mf.NewName
 		}
-		return err
+		name, merge, err := resolveConflict(tx, &placement{
+			OwnerUUID:   file.OwnerUUID,
+			ParentUUID:  file.ParentUUID,
+			Name:        name,
+			File:        &file,
+			IsDirectory: file.ArchiveUUID == nil,
+		}, mf.Conflict)
+		if err != nil {
+			return err
+		}
+		if merge != nil {
+			return mergeDirectory(tx, &file, merge)
+		}
+		file.Name = name
+		return nameConflict(tx.SaveFile(&file), name)
 	})
 	return err
 }
+
+// Queries the directory the file is moved into, rejecting the moves that would create a cycle
+func moveDestination(tx store.MetadataStore, file *models.File, userUUID, locationUUID uuid.UUID) (location models.File, err error) {
+	location, err = accessibleActiveFile(tx, userUUID, locationUUID, models.RoleEditor)
+	if err != nil {
+		return location, fmt.Errorf("failed to query destination: %w", err)
+	}
+	if location.ArchiveUUID != nil {
+		return location, fmt.Errorf("destination %s: %w", location.Name, ErrNotDirectory)
+	}
+	if location.OwnerUUID != file.OwnerUUID {
+		return location, fmt.Errorf("%w: destination belongs to another user", ErrPermissionDenied)
+	}
+	hierarchy, err := tx.Ancestors(location.UUID)
+	if err != nil {
+		return location, fmt.Errorf("failed to query destination: %w", err)
+	}
+	for _, ancestor := range hierarchy {
+		if ancestor.UUID == file.UUID {
+			return location, ErrMoveCycle
+		}
+	}
+	return location, nil
+}
@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
 	"github.com/hawks-atlanta/fs-prototype/models"
 	"github.com/hawks-atlanta/fs-prototype/utils"
 	"github.com/stretchr/testify/assert"
@@ -100,6 +103,86 @@ func TestController_CreateFile(t *testing.T) {
 			_, err = c.CreateFile(&cf)
 			assertions.NotNil(err)
 		})
+		t.Run("Nil parent", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			c, err := Default()
+			assertions.Nil(err)
+			defer c.Close()
+
+			// The nil UUID refers to the root
+			var (
+				root = uuid.Nil
+				cf   = CreateFile{
+					Filename:        "Desktop",
+					OwnerUUID:       uuid.New(),
+					ParentDirectory: &root,
+				}
+			)
+			file, err := c.CreateFile(&cf)
+			assertions.Nil(err)
+			assertions.Nil(file.ParentUUID)
+			assertions.Equal([]string{"Desktop"}, childNames(t, c, cf.OwnerUUID, nil))
+
+			_, err = c.CreateFile(&cf)
+			assertions.ErrorIs(err, ErrNameConflict)
+			cf.Conflict = ConflictMerge
+			merged, err := c.CreateFile(&cf)
+			assertions.Nil(err)
+			assertions.Equal(file.UUID, merged.UUID)
+		})
+		t.Run("Regular file parent", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			c, err := Default()
+			assertions.Nil(err)
+			defer c.Close()
+
+			var (
+				contents = "fmt.Println(`hello`)"
+				owner    = uuid.New()
+			)
+			parent, err := c.CreateFile(&CreateFile{
+				Filename:  "hello-world.go",
+				OwnerUUID: owner,
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			})
+			assertions.Nil(err)
+
+			_, err = c.CreateFile(&CreateFile{Filename: "child", OwnerUUID: owner, ParentDirectory: &parent.UUID})
+			assertions.ErrorIs(err, ErrNotDirectory)
+			assertions.Empty(childNames(t, c, owner, &parent.UUID))
+		})
+		t.Run("Invalid hash", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			c, err := Default()
+			assertions.Nil(err)
+			defer c.Close()
+
+			var (
+				contents = "fmt.Println(`hello`)"
+				cf       = CreateFile{
+					Filename:  "hello-world.go",
+					OwnerUUID: uuid.New(),
+					Hash:      "NOT-A-HASH",
+					Size:      uint64(len(contents)),
+				}
+			)
+			_, err = c.CreateFile(&cf)
+			assertions.ErrorIs(err, blobstore.ErrInvalidHash)
+			cf.Hash = strings.ToUpper(utils.Hash(contents))
+			_, err = c.CreateFile(&cf)
+			assertions.ErrorIs(err, blobstore.ErrInvalidHash)
+
+			// Nothing was registered nor charged
+			_, err = c.Store.FindArchive(cf.Hash, cf.Size)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+			usage, err := c.GetUsage(&GetUsage{UserUUID: cf.OwnerUUID})
+			assertions.Nil(err)
+			assertions.Equal(uint64(0), usage.Files)
+		})
 	})
 }
 
@@ -111,14 +194,26 @@ func TestController_DeleteFile(t *testing.T) {
 		assertions.Nil(err)
 		defer c.Close()
 
+		// Create parent
+		var (
+			owner    = uuid.New()
+			parentCf = CreateFile{
+				Filename:  "Desktop",
+				OwnerUUID: owner,
+			}
+		)
+		parent, err := c.CreateFile(&parentCf)
+		assertions.Nil(err)
+
 		// Create file
 		var (
 			contents = "fmt.Println(`hello`)"
 			cf       = CreateFile{
-				Filename:  "hello-world.go",
-				OwnerUUID: uuid.New(),
-				Hash:      utils.Hash(contents),
-				Size:      uint64(len(contents)),
+				Filename:        "hello-world.go",
+				OwnerUUID:       owner,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &parent.UUID,
+				Size:            uint64(len(contents)),
 			}
 		)
 		file, err := c.CreateFile(&cf)
@@ -132,12 +227,17 @@ func TestController_DeleteFile(t *testing.T) {
 		err = c.DeleteFile(&df)
 		assertions.Nil(err)
 
-		// Verify it doesn't exists anymore
-		var check models.File
-		err = c.DB.
-			Where("uuid = ?", file.UUID).
-			First(&check).
-			Error
+		// Verify it was moved to the trash
+		check, err := c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+
+		assertions.NotNil(check.TrashedAt)
+		assertions.Nil(check.ParentUUID)
+		assertions.Equal(parent.UUID, *check.TrashedFromUUID)
+		assertions.Equal(cf.Filename, check.Name)
+
+		// Trashing twice fails
+		err = c.DeleteFile(&df)
 		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
 	})
 	t.Run("Delete directory", func(t *testing.T) {
@@ -180,24 +280,278 @@ func TestController_DeleteFile(t *testing.T) {
 		err = c.DeleteFile(&df)
 		assertions.Nil(err)
 
+		// Verify parent is in the trash
+		check, err := c.Store.GetFile(parent.UUID)
+		assertions.Nil(err)
+		assertions.NotNil(check.TrashedAt)
+
+		// Verify child is kept inside its parent
+		check, err = c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(parent.UUID, *check.ParentUUID)
+		assertions.Nil(check.TrashedAt)
+	})
+	t.Run("Permanently delete directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		// Create parent
+		var (
+			owner    = uuid.New()
+			parentCf = CreateFile{
+				Filename:  "Desktop",
+				OwnerUUID: owner,
+			}
+		)
+		parent, err := c.CreateFile(&parentCf)
+		assertions.Nil(err)
+
+		// Create file
+		var (
+			contents = "fmt.Println(`hello`)"
+			cf       = CreateFile{
+				Filename:        "hello-world.go",
+				OwnerUUID:       owner,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &parent.UUID,
+				Size:            uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		// Delete parent
+		var df = DeleteFile{
+			OwnerUUID: cf.OwnerUUID,
+			FileUUID:  parent.UUID,
+			Permanent: true,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
 		// Verify parent doesn't exists anymore
-		var check models.File
-		err = c.DB.
-			Where("uuid = ?", parent.UUID).
-			First(&check).
-			Error
+		_, err = c.Store.GetFile(parent.UUID)
 		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
 
 		// Verify child doesn't exists anymore
-		check = models.File{}
-		err = c.DB.
-			Where("uuid = ?", file.UUID).
-			First(&check).
-			Error
+		_, err = c.Store.GetFile(file.UUID)
 		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
 	})
 }
 
+func TestController_ListDirectory(t *testing.T) {
+	t.Run("Root directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		for _, name := range []string{"b.txt", "a.txt", "c.txt"} {
+			var cf = CreateFile{
+				Filename:  name,
+				OwnerUUID: owner,
+				Hash:      utils.Hash(name),
+				Size:      uint64(len(name)),
+			}
+			_, err = c.CreateFile(&cf)
+			assertions.Nil(err)
+		}
+
+		var ld = ListDirectory{
+			UserUUID: owner,
+		}
+		dir, err := c.ListDirectory(&ld)
+		assertions.Nil(err)
+
+		assertions.Len(dir.Files, 3)
+		assertions.Equal("a.txt", dir.Files[0].Name)
+		assertions.Equal("b.txt", dir.Files[1].Name)
+		assertions.Equal("c.txt", dir.Files[2].Name)
+		assertions.NotNil(dir.Files[0].Archive)
+		assertions.Empty(dir.NextCursor)
+	})
+	t.Run("Paginate", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner    = uuid.New()
+			parentCf = CreateFile{
+				Filename:  "Desktop",
+				OwnerUUID: owner,
+			}
+		)
+		parent, err := c.CreateFile(&parentCf)
+		assertions.Nil(err)
+
+		for i := 0; i < 5; i++ {
+			var (
+				contents = strings.Repeat("x", i+1)
+				cf       = CreateFile{
+					Filename:        fmt.Sprintf("file-%d.txt", i),
+					OwnerUUID:       owner,
+					Hash:            utils.Hash(contents),
+					ParentDirectory: &parent.UUID,
+					Size:            uint64(len(contents)),
+				}
+			)
+			_, err = c.CreateFile(&cf)
+			assertions.Nil(err)
+		}
+
+		var (
+			ld = ListDirectory{
+				UserUUID:   owner,
+				ParentUUID: &parent.UUID,
+				SortBy:     SortBySize,
+				Descending: true,
+				Limit:      2,
+			}
+			sizes []uint64
+		)
+		for {
+			dir, err := c.ListDirectory(&ld)
+			assertions.Nil(err)
+			assertions.LessOrEqual(len(dir.Files), 2)
+			for _, file := range dir.Files {
+				sizes = append(sizes, file.Archive.Size)
+			}
+			if dir.NextCursor == "" {
+				break
+			}
+			ld.Cursor = dir.NextCursor
+		}
+		assertions.Equal([]uint64{5, 4, 3, 2, 1}, sizes)
+	})
+	t.Run("Invalid cursor", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var ld = ListDirectory{
+			UserUUID: uuid.New(),
+			Cursor:   "not a cursor",
+		}
+		_, err = c.ListDirectory(&ld)
+		assertions.ErrorIs(err, ErrInvalidCursor)
+	})
+	t.Run("Shared directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner    = uuid.New()
+			parentCf = CreateFile{
+				Filename:  "Desktop",
+				OwnerUUID: owner,
+			}
+		)
+		parent, err := c.CreateFile(&parentCf)
+		assertions.Nil(err)
+
+		var childCf = CreateFile{
+			Filename:        "Projects",
+			OwnerUUID:       owner,
+			ParentDirectory: &parent.UUID,
+		}
+		child, err := c.CreateFile(&childCf)
+		assertions.Nil(err)
+
+		var (
+			contents = "fmt.Println(`hello`)"
+			cf       = CreateFile{
+				Filename:        "hello-world.go",
+				OwnerUUID:       owner,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &child.UUID,
+				Size:            uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		// Share parent
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       parent.UUID,
+			TargetUserUUID: uuid.New(),
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		// List nested directory as recipient
+		var ld = ListDirectory{
+			UserUUID:   sr.TargetUserUUID,
+			ParentUUID: &child.UUID,
+		}
+		dir, err := c.ListDirectory(&ld)
+		assertions.Nil(err)
+
+		assertions.Len(dir.Files, 1)
+		assertions.Equal(file.UUID, dir.Files[0].UUID)
+	})
+	t.Run("Zero Access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var parentCf = CreateFile{
+			Filename:  "Desktop",
+			OwnerUUID: uuid.New(),
+		}
+		parent, err := c.CreateFile(&parentCf)
+		assertions.Nil(err)
+
+		var ld = ListDirectory{
+			UserUUID:   uuid.New(),
+			ParentUUID: &parent.UUID,
+		}
+		_, err = c.ListDirectory(&ld)
+		assertions.NotNil(err)
+	})
+	t.Run("Not a directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			contents = "fmt.Println(`hello`)"
+			cf       = CreateFile{
+				Filename:  "hello-world.go",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var ld = ListDirectory{
+			UserUUID:   cf.OwnerUUID,
+			ParentUUID: &file.UUID,
+		}
+		_, err = c.ListDirectory(&ld)
+		assertions.ErrorIs(err, ErrNotDirectory)
+	})
+}
+
 func TestController_QueryFile(t *testing.T) {
 	t.Run("Owned file", func(t *testing.T) {
 		assertions := assert.New(t)
@@ -217,6 +571,7 @@ func TestController_QueryFile(t *testing.T) {
 		)
 		file, err := c.CreateFile(&cf)
 		assertions.Nil(err)
+		markArchiveReady(t, c, cf.Hash, cf.Size)
 
 		var qf = QueryFile{
 			UserUUID: cf.OwnerUUID,
@@ -245,6 +600,7 @@ func TestController_QueryFile(t *testing.T) {
 		)
 		file, err := c.CreateFile(&cf)
 		assertions.Nil(err)
+		markArchiveReady(t, c, cf.Hash, cf.Size)
 
 		var sr = ShareRequest{
 			OwnerUUID:      cf.OwnerUUID,
@@ -292,6 +648,7 @@ func TestController_QueryFile(t *testing.T) {
 		)
 		file, err := c.CreateFile(&cf)
 		assertions.Nil(err)
+		markArchiveReady(t, c, cf.Hash, cf.Size)
 
 		// Share parent
 		var sr = ShareRequest{
@@ -341,6 +698,7 @@ func TestController_QueryFile(t *testing.T) {
 		)
 		file, err := c.CreateFile(&cf)
 		assertions.Nil(err)
+		markArchiveReady(t, c, cf.Hash, cf.Size)
 
 		// Check if can read file inside shared parent
 		var qf = QueryFile{
@@ -354,6 +712,141 @@ func TestController_QueryFile(t *testing.T) {
 	})
 }
 
+func TestController_GetFile(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, viewer := createSharedDirectory(t, c, models.RoleViewer)
+
+		found, err := c.GetFile(&GetFile{UserUUID: viewer, FileUUID: file.UUID})
+		assertions.Nil(err)
+		assertions.Equal(file.Name, found.Name)
+		assertions.Equal(dir.UUID, *found.ParentUUID)
+
+		_, err = c.GetFile(&GetFile{UserUUID: uuid.New(), FileUUID: file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		// Only the owner can reach the files in the trash
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID}))
+		found, err = c.GetFile(&GetFile{UserUUID: dir.OwnerUUID, FileUUID: file.UUID})
+		assertions.Nil(err)
+		assertions.Equal(file.UUID, found.UUID)
+		_, err = c.GetFile(&GetFile{UserUUID: viewer, FileUUID: file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
 func TestController_MoveFile(t *testing.T) {
-	// TODO: implement me!
+	t.Run("Rename and move", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		file, err := c.CreateFile(&CreateFile{Filename: "notes.txt", OwnerUUID: owner})
+		assertions.Nil(err)
+
+		var newName = "old.txt"
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: file.UUID, NewLocation: &dir.UUID, NewName: &newName})
+		assertions.Nil(err)
+		moved, err := c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(dir.UUID, *moved.ParentUUID)
+		assertions.Equal(newName, moved.Name)
+
+		// Without destination the file stays where it is
+		newName = "notes.txt"
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: file.UUID, NewName: &newName})
+		assertions.Nil(err)
+		moved, err = c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(dir.UUID, *moved.ParentUUID)
+	})
+	t.Run("Root", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, editor := createSharedDirectory(t, c, models.RoleEditor)
+		err = c.MoveFile(&MoveFile{OwnerUUID: editor, FileUUID: file.UUID, ToRoot: true})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: file.UUID, ToRoot: true, NewLocation: &dir.UUID})
+		assertions.ErrorIs(err, ErrInvalidMove)
+
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: file.UUID, ToRoot: true})
+		assertions.Nil(err)
+		moved, err := c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Nil(moved.ParentUUID)
+
+		// The nil UUID refers to the root too
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: file.UUID, NewLocation: &dir.UUID})
+		assertions.Nil(err)
+		var root = uuid.Nil
+		err = c.MoveFile(&MoveFile{OwnerUUID: editor, FileUUID: file.UUID, NewLocation: &root})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: file.UUID, NewLocation: &root})
+		assertions.Nil(err)
+		moved, err = c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Nil(moved.ParentUUID)
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: file.UUID, NewLocation: &root, ToRoot: true})
+		assertions.Nil(err)
+	})
+	t.Run("Cycle", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		child, err := c.CreateFile(&CreateFile{Filename: "archive", OwnerUUID: owner, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: dir.UUID, NewLocation: &dir.UUID})
+		assertions.ErrorIs(err, ErrMoveCycle)
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: dir.UUID, NewLocation: &child.UUID})
+		assertions.ErrorIs(err, ErrMoveCycle)
+		assertions.Nil(c.CanReadFile(&CanReadFile{UserUUID: owner, FileUUID: child.UUID}))
+	})
+	t.Run("Invalid destination", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, editor := createSharedDirectory(t, c, models.RoleEditor)
+		other, err := c.CreateFile(&CreateFile{Filename: "other", OwnerUUID: dir.OwnerUUID})
+		assertions.Nil(err)
+
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: other.UUID, NewLocation: &file.UUID})
+		assertions.ErrorIs(err, ErrNotDirectory)
+		var missing = uuid.New()
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: other.UUID, NewLocation: &missing})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		var empty = ""
+		err = c.MoveFile(&MoveFile{OwnerUUID: dir.OwnerUUID, FileUUID: other.UUID, NewName: &empty})
+		assertions.ErrorIs(err, ErrInvalidMove)
+
+		// Editors can't move the shared content to directories of their own
+		own, err := c.CreateFile(&CreateFile{Filename: "mine", OwnerUUID: editor})
+		assertions.Nil(err)
+		err = c.MoveFile(&MoveFile{OwnerUUID: editor, FileUUID: file.UUID, NewLocation: &own.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
 }
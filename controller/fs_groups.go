@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+// Queries a group owned by the user, groups of other users are reported as permission denied
+func ownedGroup(tx store.MetadataStore, ownerUUID, groupUUID uuid.UUID) (group models.Group, err error) {
+	group, err = tx.GetGroup(groupUUID)
+	if err != nil {
+		return group, fmt.Errorf("failed to query group: %w", err)
+	}
+	if group.OwnerUUID != ownerUUID {
+		err = fmt.Errorf("%w: only the owner can manage the group", ErrPermissionDenied)
+	}
+	return group, err
+}
+
+// Whether the user owns the group or is one of its members
+func inGroup(tx store.MetadataStore, userUUID uuid.UUID, group models.Group) (bool, error) {
+	if group.OwnerUUID == userUUID {
+		return true, nil
+	}
+	groups, err := tx.ListGroups(&store.ListGroups{MemberUUID: &userUUID})
+	if err != nil {
+		return false, fmt.Errorf("failed to query groups of user: %w", err)
+	}
+	return slices.ContainsFunc(groups, func(g models.Group) bool { return g.UUID == group.UUID }), nil
+}
+
+type CreateGroup struct {
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	Name      string    `json:"name"`
+}
+
+// Creates an empty group, names are unique per owner.
+// The owner is not a member of the group unless it adds itself
+func (c *Controller) CreateGroup(cg *CreateGroup) (group models.Group, err error) {
+	var name = strings.TrimSpace(cg.Name)
+	if name == "" {
+		return group, fmt.Errorf("%w: name is required", ErrInvalidGroup)
+	}
+	group = models.Group{
+		OwnerUUID: cg.OwnerUUID,
+		Name:      name,
+	}
+	err = c.Store.CreateGroup(&group)
+	if err != nil {
+		err = fmt.Errorf("failed to create group: %w", err)
+	}
+	return group, err
+}
+
+type DeleteGroup struct {
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	GroupUUID uuid.UUID `json:"groupUUID"`
+}
+
+// Removes the group, its members lose the access granted through it
+func (c *Controller) DeleteGroup(dg *DeleteGroup) (err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		_, err := ownedGroup(tx, dg.OwnerUUID, dg.GroupUUID)
+		if err != nil {
+			return err
+		}
+		err = tx.DeleteGroup(dg.GroupUUID)
+		if err != nil {
+			err = fmt.Errorf("failed to delete group: %w", err)
+		}
+		return err
+	})
+	return err
+}
+
+type ListGroups struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+}
+
+// Lists the groups owned by the user followed by the ones of other users it is a member of
+func (c *Controller) ListGroups(lg *ListGroups) (groups []models.Group, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		owned, err := tx.ListGroups(&store.ListGroups{OwnerUUID: &lg.UserUUID})
+		if err != nil {
+			return fmt.Errorf("failed to query owned groups: %w", err)
+		}
+		joined, err := tx.ListGroups(&store.ListGroups{MemberUUID: &lg.UserUUID})
+		if err != nil {
+			return fmt.Errorf("failed to query groups of user: %w", err)
+		}
+		groups = owned
+		for _, group := range joined {
+			if group.OwnerUUID != lg.UserUUID {
+				groups = append(groups, group)
+			}
+		}
+		return nil
+	})
+	return groups, err
+}
+
+type ListGroupMembers struct {
+	UserUUID  uuid.UUID `json:"userUUID"`
+	GroupUUID uuid.UUID `json:"groupUUID"`
+}
+
+// Lists the members of the group in the order they joined, available to its owner and members
+func (c *Controller) ListGroupMembers(lgm *ListGroupMembers) (members []models.GroupMember, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		group, err := tx.GetGroup(lgm.GroupUUID)
+		if err != nil {
+			return fmt.Errorf("failed to query group: %w", err)
+		}
+		member, err := inGroup(tx, lgm.UserUUID, group)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return fmt.Errorf("%w: user doesn't belong to the group", ErrPermissionDenied)
+		}
+		members, err = tx.ListGroupMembers(group.UUID)
+		if err != nil {
+			err = fmt.Errorf("failed to query members: %w", err)
+		}
+		return err
+	})
+	return members, err
+}
+
+type GroupMemberRequest struct {
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	GroupUUID uuid.UUID `json:"groupUUID"`
+	UserUUID  uuid.UUID `json:"userUUID"`
+}
+
+// Adds the user to the group, only the owner of the group can add members
+func (c *Controller) AddGroupMember(gmr *GroupMemberRequest) (err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		_, err := ownedGroup(tx, gmr.OwnerUUID, gmr.GroupUUID)
+		if err != nil {
+			return err
+		}
+		err = tx.CreateGroupMember(&models.GroupMember{
+			GroupUUID: gmr.GroupUUID,
+			UserUUID:  gmr.UserUUID,
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to add member: %w", err)
+		}
+		return err
+	})
+	return err
+}
+
+// Removes the user from the group, the owner can remove anyone and members can leave by themselves
+func (c *Controller) RemoveGroupMember(gmr *GroupMemberRequest) (err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		if gmr.OwnerUUID != gmr.UserUUID {
+			_, err := ownedGroup(tx, gmr.OwnerUUID, gmr.GroupUUID)
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.DeleteGroupMember(gmr.GroupUUID, gmr.UserUUID)
+		if err != nil {
+			err = fmt.Errorf("failed to remove member: %w", err)
+		}
+		return err
+	})
+	return err
+}
+
+// Queries the group a file is about to be shared with, users can only share with groups they belong to
+func sharingGroup(tx store.MetadataStore, userUUID, groupUUID uuid.UUID) (group models.Group, err error) {
+	group, err = tx.GetGroup(groupUUID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%w: unknown group", ErrInvalidShare)
+		} else {
+			err = fmt.Errorf("failed to query group: %w", err)
+		}
+		return group, err
+	}
+	member, err := inGroup(tx, userUUID, group)
+	if err == nil && !member {
+		err = fmt.Errorf("%w: user doesn't belong to the group", ErrPermissionDenied)
+	}
+	return group, err
+}
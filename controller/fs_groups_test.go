@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func createGroup(t *testing.T, c *Controller, ownerUUID uuid.UUID, members ...uuid.UUID) models.Group {
+	assertions := assert.New(t)
+
+	group, err := c.CreateGroup(&CreateGroup{OwnerUUID: ownerUUID, Name: uuid.NewString()})
+	assertions.Nil(err)
+	for _, member := range members {
+		err = c.AddGroupMember(&GroupMemberRequest{
+			OwnerUUID: ownerUUID,
+			GroupUUID: group.UUID,
+			UserUUID:  member,
+		})
+		assertions.Nil(err)
+	}
+	return group
+}
+
+func TestController_Groups(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		group, err := c.CreateGroup(&CreateGroup{OwnerUUID: owner, Name: " team "})
+		assertions.Nil(err)
+		assertions.Equal("team", group.Name)
+
+		_, err = c.CreateGroup(&CreateGroup{OwnerUUID: owner, Name: "team"})
+		assertions.ErrorIs(err, gorm.ErrDuplicatedKey)
+		_, err = c.CreateGroup(&CreateGroup{OwnerUUID: owner, Name: " "})
+		assertions.ErrorIs(err, ErrInvalidGroup)
+	})
+	t.Run("Members", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner  = uuid.New()
+			member = uuid.New()
+			group  = createGroup(t, c, owner, member)
+		)
+		members, err := c.ListGroupMembers(&ListGroupMembers{UserUUID: member, GroupUUID: group.UUID})
+		assertions.Nil(err)
+		assertions.Len(members, 1)
+		assertions.Equal(member, members[0].UserUUID)
+
+		_, err = c.ListGroupMembers(&ListGroupMembers{UserUUID: uuid.New(), GroupUUID: group.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		// Only the owner adds members
+		err = c.AddGroupMember(&GroupMemberRequest{OwnerUUID: member, GroupUUID: group.UUID, UserUUID: uuid.New()})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		groups, err := c.ListGroups(&ListGroups{UserUUID: member})
+		assertions.Nil(err)
+		assertions.Len(groups, 1)
+		groups, err = c.ListGroups(&ListGroups{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Len(groups, 1)
+
+		// Members can leave
+		err = c.RemoveGroupMember(&GroupMemberRequest{OwnerUUID: member, GroupUUID: group.UUID, UserUUID: member})
+		assertions.Nil(err)
+		groups, err = c.ListGroups(&ListGroups{UserUUID: member})
+		assertions.Nil(err)
+		assertions.Len(groups, 0)
+	})
+	t.Run("Delete", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			group = createGroup(t, c, owner)
+		)
+		err = c.DeleteGroup(&DeleteGroup{OwnerUUID: uuid.New(), GroupUUID: group.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		err = c.DeleteGroup(&DeleteGroup{OwnerUUID: owner, GroupUUID: group.UUID})
+		assertions.Nil(err)
+		err = c.DeleteGroup(&DeleteGroup{OwnerUUID: owner, GroupUUID: group.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestController_GroupShares(t *testing.T) {
+	t.Run("Members gain and lose access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var (
+			member = uuid.New()
+			group  = createGroup(t, c, dir.OwnerUUID, member)
+		)
+		err = c.CanReadFile(&CanReadFile{UserUUID: member, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		err = c.ShareFile(&ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetGroupUUID: &group.UUID,
+		})
+		assertions.Nil(err)
+		err = c.CanReadFile(&CanReadFile{UserUUID: member, FileUUID: file.UUID})
+		assertions.Nil(err)
+
+		withMe, err := c.ShareWithMe(&ShareWithMe{UserUUID: member})
+		assertions.Nil(err)
+		assertions.Len(withMe.Entries, 1)
+		assertions.Equal(dir.UUID, withMe.Entries[0].File.UUID)
+		assertions.Equal(group.UUID, *withMe.Entries[0].GroupUUID)
+
+		// New members get access right away
+		var newcomer = uuid.New()
+		err = c.AddGroupMember(&GroupMemberRequest{OwnerUUID: dir.OwnerUUID, GroupUUID: group.UUID, UserUUID: newcomer})
+		assertions.Nil(err)
+		err = c.CanReadFile(&CanReadFile{UserUUID: newcomer, FileUUID: file.UUID})
+		assertions.Nil(err)
+
+		shared, err := c.ShareWithWho(&ShareWithWho{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		assertions.Len(shared, 3)
+
+		err = c.RemoveGroupMember(&GroupMemberRequest{OwnerUUID: dir.OwnerUUID, GroupUUID: group.UUID, UserUUID: member})
+		assertions.Nil(err)
+		err = c.CanReadFile(&CanReadFile{UserUUID: member, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		err = c.UnshareFile(&ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetGroupUUID: &group.UUID,
+		})
+		assertions.Nil(err)
+		err = c.CanReadFile(&CanReadFile{UserUUID: newcomer, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+	t.Run("Roles", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleViewer)
+		var group = createGroup(t, c, dir.OwnerUUID, user)
+		var sr = ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetGroupUUID: &group.UUID,
+			Role:            models.RoleEditor,
+		}
+		assertions.Nil(c.ShareFile(&sr))
+
+		// The highest role between the direct and the group shares applies
+		_, err = c.CreateFile(&CreateFile{Filename: "from-group", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+
+		sr.Role = models.RoleViewer
+		assertions.Nil(c.ChangeShareRole(&sr))
+		_, err = c.CreateFile(&CreateFile{Filename: "denied", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		// Listed once per share
+		shared, err := c.ShareWithMe(&ShareWithMe{UserUUID: user})
+		assertions.Nil(err)
+		assertions.Len(shared.Entries, 2)
+		resolved, err := c.ResolvePath(&ResolvePath{UserUUID: user, Path: "/Shared"})
+		assertions.Nil(err)
+		assertions.Equal(dir.UUID, resolved.UUID)
+	})
+	t.Run("Invalid targets", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var foreign = createGroup(t, c, uuid.New())
+		err = c.ShareFile(&ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetGroupUUID: &foreign.UUID,
+		})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		var missing = uuid.New()
+		err = c.ShareFile(&ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetGroupUUID: &missing,
+		})
+		assertions.ErrorIs(err, ErrInvalidShare)
+
+		var group = createGroup(t, c, dir.OwnerUUID)
+		err = c.ShareFile(&ShareRequest{
+			OwnerUUID:       dir.OwnerUUID,
+			FileUUID:        dir.UUID,
+			TargetUserUUID:  uuid.New(),
+			TargetGroupUUID: &group.UUID,
+		})
+		assertions.ErrorIs(err, ErrInvalidShare)
+	})
+}
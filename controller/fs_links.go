@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Random bytes of the link tokens
+const linkTokenSize = 32
+
+func newLinkToken() (string, error) {
+	var token = make([]byte, linkTokenSize)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Tokens are stored hashed so a leaked index doesn't leak working links
+func hashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Queries a file the user can create and revoke links for, the same users that can share it
+func linkableFile(tx store.MetadataStore, userUUID, fileUUID uuid.UUID) (file models.File, err error) {
+	file, err = accessibleActiveFile(tx, userUUID, fileUUID, models.RoleManager)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+	} else if err != nil && !errors.Is(err, ErrPermissionDenied) {
+		err = fmt.Errorf("failed to query file: %w", err)
+	}
+	return file, err
+}
+
+type CreateShareLink struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+	// Optional, stored hashed
+	Password     string     `json:"password,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	MaxDownloads *uint      `json:"maxDownloads,omitempty"`
+}
+
+// Creates a link granting read access to the file, or to the directory and everything under it,
+// to anyone knowing its token. The owner and managers of the file can create links.
+// The token is only returned here, the links listed afterwards don't include it
+func (c *Controller) CreateShareLink(csl *CreateShareLink) (link models.ShareLink, err error) {
+	if csl.ExpiresAt != nil && !csl.ExpiresAt.After(time.Now()) {
+		return link, fmt.Errorf("%w: expiration must be in the future", ErrInvalidShare)
+	}
+	if csl.MaxDownloads != nil && *csl.MaxDownloads == 0 {
+		return link, fmt.Errorf("%w: maximum downloads must be positive", ErrInvalidShare)
+	}
+	link = models.ShareLink{
+		FileUUID:     csl.FileUUID,
+		CreatorUUID:  csl.UserUUID,
+		ExpiresAt:    csl.ExpiresAt,
+		MaxDownloads: csl.MaxDownloads,
+	}
+	if csl.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(csl.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return link, fmt.Errorf("%w: %w", ErrInvalidShare, err)
+		}
+		link.PasswordHash = string(hash)
+	}
+	link.Token, err = newLinkToken()
+	if err != nil {
+		return link, err
+	}
+	link.TokenHash = hashLinkToken(link.Token)
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		_, err := linkableFile(tx, csl.UserUUID, csl.FileUUID)
+		if err != nil {
+			return err
+		}
+		err = tx.CreateShareLink(&link)
+		if err != nil {
+			err = fmt.Errorf("failed to create link: %w", err)
+		}
+		return err
+	})
+	return link, err
+}
+
+type ListShareLinks struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+}
+
+// Lists the links of the file oldest first, expired and exhausted links included
+func (c *Controller) ListShareLinks(lsl *ListShareLinks) (links []models.ShareLink, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := linkableFile(tx, lsl.UserUUID, lsl.FileUUID)
+		if err != nil {
+			return err
+		}
+		links, err = tx.ListShareLinks(file.UUID)
+		if err != nil {
+			err = fmt.Errorf("failed to query links: %w", err)
+		}
+		return err
+	})
+	return links, err
+}
+
+type RevokeShareLink struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+	LinkUUID uuid.UUID `json:"linkUUID"`
+}
+
+// Removes the link of the file, its token stops working right away
+func (c *Controller) RevokeShareLink(rsl *RevokeShareLink) (err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := linkableFile(tx, rsl.UserUUID, rsl.FileUUID)
+		if err != nil {
+			return err
+		}
+		err = tx.DeleteShareLink(file.UUID, rsl.LinkUUID)
+		if err != nil {
+			err = fmt.Errorf("failed to delete link: %w", err)
+		}
+		return err
+	})
+	return err
+}
+
+type QueryFileByLink struct {
+	Token    string `json:"token"`
+	Password string `json:"password,omitempty"`
+	// File under the linked directory, the linked file itself when nil
+	FileUUID *uuid.UUID `json:"fileUUID,omitempty"`
+}
+
+// Same as QueryFile for anonymous users holding a link.
+// Unknown tokens and files outside of the link are reported as not found, every successful query
+// counts as a download. The password is checked before telling whether the link expired.
+// Links stop working while the file or any of its parents is in the trash,
+// and when their creator can no longer manage the file
+func (c *Controller) QueryFileByLink(qfl *QueryFileByLink) (archive models.Archive, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		link, err := tx.FindShareLink(hashLinkToken(qfl.Token))
+		if err != nil {
+			return fmt.Errorf("failed to query link: %w", err)
+		}
+		if link.Protected() && bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(qfl.Password)) != nil {
+			return fmt.Errorf("%w: invalid password", ErrPermissionDenied)
+		}
+		if link.ExpiresAt != nil && !link.ExpiresAt.After(time.Now()) {
+			return ErrLinkExpired
+		}
+		// Shares of the creator can be revoked or downgraded after the link was created
+		_, err = accessibleFile(tx, link.CreatorUUID, link.FileUUID, models.RoleManager)
+		if errors.Is(err, ErrPermissionDenied) {
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query link: %w", err)
+		}
+
+		var fileUUID = link.FileUUID
+		if qfl.FileUUID != nil {
+			fileUUID = *qfl.FileUUID
+		}
+		hierarchy, err := tx.Ancestors(fileUUID)
+		if err != nil {
+			return fmt.Errorf("failed to query file hierarchy: %w", err)
+		}
+		var linked bool
+		for _, ancestor := range hierarchy {
+			if ancestor.TrashedAt != nil {
+				return fmt.Errorf("failed to query file: %w", gorm.ErrRecordNotFound)
+			}
+			linked = linked || ancestor.UUID == link.FileUUID
+		}
+		if !linked {
+			return fmt.Errorf("file not reachable through link: %w", gorm.ErrRecordNotFound)
+		}
+		var file = hierarchy[0]
+		if file.ArchiveUUID == nil {
+			return ErrIsDirectory
+		}
+		archive, err = tx.GetArchive(*file.ArchiveUUID)
+		if err != nil {
+			return fmt.Errorf("failed to query archive: %w", err)
+		}
+		if !archive.IsReady {
+			return ErrArchiveNotReady
+		}
+		err = tx.CountShareLinkDownload(link.UUID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrDownloadLimit
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, ErrArchiveNotReady) {
+		archive = models.Archive{}
+	}
+	return archive, err
+}
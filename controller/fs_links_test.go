@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Creates a shared directory with a ready file in it
+func createLinkedDirectory(t *testing.T, c *Controller) (dir, file models.File, user uuid.UUID) {
+	dir, file, user = createSharedDirectory(t, c, models.RoleViewer)
+	var contents = "fmt.Println(`hello`)"
+	markArchiveReady(t, c, utils.Hash(contents), uint64(len(contents)))
+	return dir, file, user
+}
+
+func TestController_ShareLinks(t *testing.T) {
+	t.Run("Directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, _ := createLinkedDirectory(t, c)
+		link, err := c.CreateShareLink(&CreateShareLink{UserUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		assertions.NotEmpty(link.Token)
+
+		archive, err := c.QueryFileByLink(&QueryFileByLink{Token: link.Token, FileUUID: &file.UUID})
+		assertions.Nil(err)
+		assertions.Equal(*file.ArchiveUUID, archive.UUID)
+
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.ErrorIs(err, ErrIsDirectory)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: uuid.NewString(), FileUUID: &file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		// Files outside of the link
+		other, err := c.CreateFile(&CreateFile{Filename: "other", OwnerUUID: dir.OwnerUUID})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, FileUUID: &other.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		links, err := c.ListShareLinks(&ListShareLinks{UserUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		assertions.Len(links, 1)
+		assertions.Equal(uint(1), links[0].Downloads)
+
+		// Only the hash of the token is stored
+		assertions.Empty(links[0].Token)
+		assertions.NotEqual(link.Token, links[0].TokenHash)
+		assertions.Equal(hashLinkToken(link.Token), links[0].TokenHash)
+
+		err = c.RevokeShareLink(&RevokeShareLink{UserUUID: dir.OwnerUUID, FileUUID: dir.UUID, LinkUUID: link.UUID})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, FileUUID: &file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Password", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		_, file, _ := createLinkedDirectory(t, c)
+		link, err := c.CreateShareLink(&CreateShareLink{UserUUID: file.OwnerUUID, FileUUID: file.UUID, Password: "secret"})
+		assertions.Nil(err)
+		assertions.True(link.Protected())
+		assertions.NotContains(link.PasswordHash, "secret")
+
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, Password: "wrong"})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, Password: "secret"})
+		assertions.Nil(err)
+
+		// The state of the link is not disclosed without the password
+		stored, err := c.Store.FindShareLink(hashLinkToken(link.Token))
+		assertions.Nil(err)
+		assertions.Nil(c.Store.DeleteShareLink(file.UUID, stored.UUID))
+		var past = time.Now().Add(-time.Minute)
+		stored.ExpiresAt = &past
+		assertions.Nil(c.Store.CreateShareLink(&stored))
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, Password: "wrong"})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, Password: "secret"})
+		assertions.ErrorIs(err, ErrLinkExpired)
+	})
+	t.Run("Expiration", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		_, file, _ := createLinkedDirectory(t, c)
+		var past = time.Now().Add(-time.Minute)
+		_, err = c.CreateShareLink(&CreateShareLink{UserUUID: file.OwnerUUID, FileUUID: file.UUID, ExpiresAt: &past})
+		assertions.ErrorIs(err, ErrInvalidShare)
+
+		var future = time.Now().Add(time.Hour)
+		link, err := c.CreateShareLink(&CreateShareLink{UserUUID: file.OwnerUUID, FileUUID: file.UUID, ExpiresAt: &future})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.Nil(err)
+
+		stored, err := c.Store.FindShareLink(hashLinkToken(link.Token))
+		assertions.Nil(err)
+		assertions.Nil(c.Store.DeleteShareLink(file.UUID, stored.UUID))
+		stored.ExpiresAt = &past
+		assertions.Nil(c.Store.CreateShareLink(&stored))
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.ErrorIs(err, ErrLinkExpired)
+	})
+	t.Run("Download limit", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		_, file, _ := createLinkedDirectory(t, c)
+		var maxDownloads uint = 1
+		link, err := c.CreateShareLink(&CreateShareLink{UserUUID: file.OwnerUUID, FileUUID: file.UUID, MaxDownloads: &maxDownloads})
+		assertions.Nil(err)
+
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token})
+		assertions.ErrorIs(err, ErrDownloadLimit)
+	})
+	t.Run("Permissions", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, viewer := createLinkedDirectory(t, c)
+		_, err = c.CreateShareLink(&CreateShareLink{UserUUID: viewer, FileUUID: dir.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+		_, err = c.CreateShareLink(&CreateShareLink{UserUUID: uuid.New(), FileUUID: dir.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		link, err := c.CreateShareLink(&CreateShareLink{UserUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		err = c.RevokeShareLink(&RevokeShareLink{UserUUID: viewer, FileUUID: dir.UUID, LinkUUID: link.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		// Links stop working when their creator can no longer manage the file
+		var manager = uuid.New()
+		var sr = ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: manager,
+			Role:           models.RoleManager,
+		}
+		assertions.Nil(c.ShareFile(&sr))
+		managed, err := c.CreateShareLink(&CreateShareLink{UserUUID: manager, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: managed.Token, FileUUID: &file.UUID})
+		assertions.Nil(err)
+		sr.Role = models.RoleEditor
+		assertions.Nil(c.ChangeShareRole(&sr))
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: managed.Token, FileUUID: &file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		assertions.Nil(c.UnshareFile(&sr))
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: managed.Token, FileUUID: &file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		// Trashing the directory disables the link
+		err = c.DeleteFile(&DeleteFile{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		_, err = c.QueryFileByLink(&QueryFileByLink{Token: link.Token, FileUUID: &file.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+type ResolvePath struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	Path     string    `json:"path"`
+}
+
+// Finds the file located at the given path.
+// The first component is searched in the root of the user and then between the files shared with it,
+// so "/Shared Folder/notes.txt" works the same for the owner and for the recipients of "Shared Folder".
+// The resolved file must be readable by the user, like in QueryFile
+func (c *Controller) ResolvePath(rp *ResolvePath) (file models.File, err error) {
+	var cleaned = path.Clean("/" + rp.Path)
+	if cleaned == "/" {
+		return file, fmt.Errorf("%w: path refers to the root directory", ErrInvalidPath)
+	}
+	components := strings.Split(cleaned[1:], "/")
+
+	// Resolve first component
+	file, err = c.Store.FindChild(rp.UserUUID, nil, components[0])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		shares, err := sharesOf(c.Store, rp.UserUUID, time.Now(), store.ListShares{
+			Name:        components[0],
+			SkipTrashed: true,
+		})
+		if err != nil {
+			return file, fmt.Errorf("failed to query shared files: %w", err)
+		}
+		// The same file can be shared directly and through groups
+		var candidates []uuid.UUID
+		for _, share := range shares {
+			if !slices.Contains(candidates, share.FileUUID) {
+				candidates = append(candidates, share.FileUUID)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			return file, fmt.Errorf("failed to resolve %s: %w", components[0], gorm.ErrRecordNotFound)
+		case 1:
+			file, err = c.Store.GetFile(candidates[0])
+			if err != nil {
+				return file, fmt.Errorf("failed to query shared file: %w", err)
+			}
+		default:
+			return file, fmt.Errorf("%w: multiple files shared as %s", ErrAmbiguousPath, components[0])
+		}
+	} else if err != nil {
+		return file, fmt.Errorf("failed to query file: %w", err)
+	}
+
+	// Walk the rest of the path
+	for index, name := range components[1:] {
+		if file.ArchiveUUID != nil {
+			return models.File{}, fmt.Errorf("failed to resolve %s: %w", path.Join(components[:index+1]...), ErrNotDirectory)
+		}
+		child, err := c.Store.FindChild(file.OwnerUUID, &file.UUID, name)
+		if err != nil {
+			return models.File{}, fmt.Errorf("failed to resolve %s: %w", path.Join(components[:index+2]...), err)
+		}
+		file = child
+	}
+
+	// Shares can expire and their parents can be in the trash, even when the share was found by name
+	_, err = accessibleFile(c.Store, rp.UserUUID, file.UUID, models.RoleViewer)
+	if err != nil {
+		return models.File{}, fmt.Errorf("failed to resolve %s: %w", cleaned, err)
+	}
+	return file, nil
+}
+
+type PathOf struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+}
+
+// Builds the path of a file as seen by the user.
+// Owners receive the full path from their root. Recipients of a share receive the path starting
+// at the top most directory shared with them, since they can't see anything above it
+func (c *Controller) PathOf(po *PathOf) (filePath string, err error) {
+	var crf = CanReadFile{
+		UserUUID: po.UserUUID,
+		FileUUID: po.FileUUID,
+	}
+	err = c.CanReadFile(&crf)
+	if err != nil {
+		return "", err
+	}
+
+	// Ancestors are returned from the file up to the root
+	hierarchy, err := c.Store.Ancestors(po.FileUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to query file hierarchy: %w", err)
+	}
+	slices.Reverse(hierarchy)
+	var fileUUIDs = make([]uuid.UUID, 0, len(hierarchy))
+	for _, entry := range hierarchy {
+		fileUUIDs = append(fileUUIDs, entry.UUID)
+	}
+	shares, err := sharesOf(c.Store, po.UserUUID, time.Now(), store.ListShares{FileUUIDs: fileUUIDs})
+	if err != nil {
+		return "", fmt.Errorf("failed to query shared files: %w", err)
+	}
+	var shared = make(map[uuid.UUID]bool, len(shares))
+	for _, share := range shares {
+		shared[share.FileUUID] = true
+	}
+
+	var start = -1
+	if hierarchy[0].OwnerUUID == po.UserUUID {
+		start = 0
+	} else {
+		for index, entry := range hierarchy {
+			if shared[entry.UUID] {
+				start = index
+				break
+			}
+		}
+	}
+	if start == -1 {
+		return "", ErrPermissionDenied
+	}
+
+	var names = make([]string, 0, len(hierarchy)-start)
+	for _, entry := range hierarchy[start:] {
+		names = append(names, entry.Name)
+	}
+	return "/" + strings.Join(names, "/"), nil
+}
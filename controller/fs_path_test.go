@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestController_ResolvePath(t *testing.T) {
+	t.Run("Owned file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/projects/main.go",
+		}
+		file, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		assertions.Equal("main.go", file.Name)
+		assertions.NotNil(file.ArchiveUUID)
+
+		rp.Path = "Desktop/./empty/../projects/"
+		file, err = c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		assertions.Equal("projects", file.Name)
+		assertions.Equal(root.UUID, *file.ParentUUID)
+	})
+	t.Run("Shared file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       root.UUID,
+			TargetUserUUID: uuid.New(),
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		var rp = ResolvePath{
+			UserUUID: sr.TargetUserUUID,
+			Path:     "/Desktop/projects/main.go",
+		}
+		file, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		assertions.Equal("main.go", file.Name)
+	})
+	t.Run("Shared inside trashed directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		projects, err := c.ResolvePath(&ResolvePath{UserUUID: owner, Path: "/Desktop/projects"})
+		assertions.Nil(err)
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       projects.UUID,
+			TargetUserUUID: uuid.New(),
+		}
+		assertions.Nil(c.ShareFile(&sr))
+
+		var rp = ResolvePath{
+			UserUUID: sr.TargetUserUUID,
+			Path:     "/projects/main.go",
+		}
+		_, err = c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: owner, FileUUID: root.UUID}))
+		_, err = c.ResolvePath(&rp)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Not found", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		createTestTree(t, c, owner)
+
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/missing.txt",
+		}
+		_, err = c.ResolvePath(&rp)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		rp.UserUUID = uuid.New()
+		rp.Path = "/Desktop"
+		_, err = c.ResolvePath(&rp)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Through file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		createTestTree(t, c, owner)
+
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/notes.txt/other",
+		}
+		_, err = c.ResolvePath(&rp)
+		assertions.ErrorIs(err, ErrNotDirectory)
+	})
+	t.Run("Root", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var rp = ResolvePath{
+			UserUUID: uuid.New(),
+			Path:     "/",
+		}
+		_, err = c.ResolvePath(&rp)
+		assertions.ErrorIs(err, ErrInvalidPath)
+	})
+}
+
+func TestController_PathOf(t *testing.T) {
+	t.Run("Owned file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		createTestTree(t, c, owner)
+
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/projects/main.go",
+		}
+		file, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		var po = PathOf{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		filePath, err := c.PathOf(&po)
+		assertions.Nil(err)
+
+		assertions.Equal(rp.Path, filePath)
+	})
+	t.Run("Shared inside directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		createTestTree(t, c, owner)
+
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/projects/main.go",
+		}
+		file, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		// Share only the nested directory
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       *file.ParentUUID,
+			TargetUserUUID: uuid.New(),
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		var po = PathOf{
+			UserUUID: sr.TargetUserUUID,
+			FileUUID: file.UUID,
+		}
+		filePath, err := c.PathOf(&po)
+		assertions.Nil(err)
+
+		assertions.Equal("/projects/main.go", filePath)
+
+		// The path is valid for the recipient
+		rp = ResolvePath{
+			UserUUID: sr.TargetUserUUID,
+			Path:     filePath,
+		}
+		resolved, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		assertions.Equal(file.UUID, resolved.UUID)
+	})
+	t.Run("Zero Access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var root = createTestTree(t, c, uuid.New())
+
+		var po = PathOf{
+			UserUUID: uuid.New(),
+			FileUUID: root.UUID,
+		}
+		_, err = c.PathOf(&po)
+		assertions.NotNil(err)
+	})
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+type GetUsage struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+}
+
+// Storage used by a user along with the limits that apply to them, limits of zero mean unlimited.
+// Every file counts the full size of its current contents, even when they are shared with other files.
+// Trashed files count until they are purged, old versions don't count
+type Usage struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	Bytes    uint64    `json:"bytes"`
+	Files    uint64    `json:"files"`
+	MaxBytes uint64    `json:"maxBytes,omitempty"`
+	MaxFiles uint64    `json:"maxFiles,omitempty"`
+	// Nil when unlimited, zero once the usage reaches or exceeds the limit
+	RemainingBytes *uint64 `json:"remainingBytes,omitempty"`
+	RemainingFiles *uint64 `json:"remainingFiles,omitempty"`
+}
+
+// Fails with ErrQuotaExceeded when the usage can't grow by the given amounts
+func (u *Usage) reserve(bytes, files uint64) error {
+	if u.RemainingBytes != nil && bytes > *u.RemainingBytes {
+		return fmt.Errorf("%w: %d bytes required, %d available", ErrQuotaExceeded, bytes, *u.RemainingBytes)
+	}
+	if u.RemainingFiles != nil && files > *u.RemainingFiles {
+		return fmt.Errorf("%w: %d files required, %d available", ErrQuotaExceeded, files, *u.RemainingFiles)
+	}
+	return nil
+}
+
+// Reports the storage used by the user and what is left of their quota
+func (c *Controller) GetUsage(gu *GetUsage) (usage Usage, err error) {
+	return c.usageOf(c.Store, gu.UserUUID)
+}
+
+type SetQuota struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	// The default of the configuration applies when nil, zero means unlimited
+	MaxBytes *uint64 `json:"maxBytes,omitempty"`
+	MaxFiles *uint64 `json:"maxFiles,omitempty"`
+}
+
+// Overrides the default limits of the user, intended for the administrators.
+// Files already above the new limits are kept, but the user can't add more until freeing enough space
+func (c *Controller) SetQuota(sq *SetQuota) (usage Usage, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		err := tx.SaveQuota(&models.Quota{
+			UserUUID: sq.UserUUID,
+			MaxBytes: sq.MaxBytes,
+			MaxFiles: sq.MaxFiles,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save quota: %w", err)
+		}
+		usage, err = c.usageOf(tx, sq.UserUUID)
+		return err
+	})
+	return usage, err
+}
+
+// Usage of the user with the defaults of the controller overridden by their own quota
+func (c *Controller) usageOf(tx store.MetadataStore, userUUID uuid.UUID) (usage Usage, err error) {
+	quota, err := tx.GetQuota(userUUID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return usage, fmt.Errorf("failed to query quota: %w", err)
+	}
+	return c.usageWith(tx, userUUID, &quota)
+}
+
+// Same as usageOf but the quota stays locked until the transaction ends,
+// so concurrent operations of the same owner can't both take the remaining space
+func (c *Controller) lockedUsageOf(tx store.MetadataStore, userUUID uuid.UUID) (usage Usage, err error) {
+	quota, err := tx.LockQuota(userUUID)
+	if err != nil {
+		return usage, fmt.Errorf("failed to lock quota: %w", err)
+	}
+	return c.usageWith(tx, userUUID, &quota)
+}
+
+func (c *Controller) usageWith(tx store.MetadataStore, userUUID uuid.UUID, quota *models.Quota) (usage Usage, err error) {
+	usage = Usage{
+		UserUUID: userUUID,
+		MaxBytes: c.Quota.Bytes,
+		MaxFiles: c.Quota.Files,
+	}
+	if quota.MaxBytes != nil {
+		usage.MaxBytes = *quota.MaxBytes
+	}
+	if quota.MaxFiles != nil {
+		usage.MaxFiles = *quota.MaxFiles
+	}
+	used, err := tx.Usage(userUUID)
+	if err != nil {
+		return usage, fmt.Errorf("failed to query usage: %w", err)
+	}
+	usage.Bytes = used.Bytes
+	usage.Files = used.Files
+	usage.RemainingBytes = remaining(usage.Bytes, usage.MaxBytes)
+	usage.RemainingFiles = remaining(usage.Files, usage.MaxFiles)
+	return usage, nil
+}
+
+func remaining(used, limit uint64) *uint64 {
+	if limit == 0 {
+		return nil
+	}
+	var left uint64
+	if used < limit {
+		left = limit - used
+	}
+	return &left
+}
+
+// Fails with ErrQuotaExceeded when the usage of the owner can't grow by the given amounts
+func (c *Controller) reserve(tx store.MetadataStore, ownerUUID uuid.UUID, bytes, files uint64) error {
+	usage, err := c.lockedUsageOf(tx, ownerUUID)
+	if err != nil {
+		return err
+	}
+	return usage.reserve(bytes, files)
+}
+
+// Same as reserve for the growth of the file when its contents are replaced, shrinking is always allowed
+func (c *Controller) reserveContents(tx store.MetadataStore, file *models.File, size uint64) error {
+	current, err := tx.GetArchive(*file.ArchiveUUID)
+	if err != nil {
+		return fmt.Errorf("failed to query current contents: %w", err)
+	}
+	if size <= current.Size {
+		return nil
+	}
+	return c.reserve(tx, file.OwnerUUID, size-current.Size, 0)
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestController_Quotas(t *testing.T) {
+	var contents = "fmt.Println(`hello`)"
+	t.Run("Unlimited", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		_, err = c.CreateFile(&CreateFile{
+			Filename:  "hello.go",
+			OwnerUUID: owner,
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+		})
+		assertions.Nil(err)
+
+		usage, err := c.GetUsage(&GetUsage{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Equal(Usage{UserUUID: owner, Bytes: uint64(len(contents)), Files: 1}, usage)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+		c.Quota = config.Quota{Bytes: 2 * uint64(len(contents)), Files: 3}
+
+		var cf = CreateFile{
+			Filename:  "hello.go",
+			OwnerUUID: uuid.New(),
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+			Conflict:  ConflictRename,
+		}
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+		// Files with the same contents count in full
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+		_, err = c.CreateFile(&cf)
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "more", OwnerUUID: cf.OwnerUUID})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+
+		usage, err := c.GetUsage(&GetUsage{UserUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+		assertions.Equal(uint64(3), usage.Files)
+		assertions.Equal(c.Quota.Bytes, usage.Bytes)
+		assertions.Zero(*usage.RemainingBytes)
+		assertions.Zero(*usage.RemainingFiles)
+
+		// Trashed files keep counting until they are purged
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: cf.OwnerUUID, FileUUID: dir.UUID}))
+		_, err = c.CreateFile(&CreateFile{Filename: "more", OwnerUUID: cf.OwnerUUID})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: cf.OwnerUUID, FileUUID: dir.UUID, Permanent: true}))
+		_, err = c.CreateFile(&CreateFile{Filename: "more", OwnerUUID: cf.OwnerUUID})
+		assertions.Nil(err)
+	})
+	t.Run("Overrides", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+		c.Quota = config.Quota{Bytes: 1, Files: 1}
+
+		var (
+			owner    = uuid.New()
+			maxBytes = uint64(len(contents))
+			noLimit  = uint64(0)
+		)
+		usage, err := c.SetQuota(&SetQuota{UserUUID: owner, MaxBytes: &maxBytes, MaxFiles: &noLimit})
+		assertions.Nil(err)
+		assertions.Equal(maxBytes, usage.MaxBytes)
+		assertions.Zero(usage.MaxFiles)
+		assertions.Equal(maxBytes, *usage.RemainingBytes)
+		assertions.Nil(usage.RemainingFiles)
+
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{
+			Filename:        "hello.go",
+			OwnerUUID:       owner,
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &dir.UUID,
+		})
+		assertions.Nil(err)
+
+		// Other users keep the defaults
+		usage, err = c.GetUsage(&GetUsage{UserUUID: uuid.New()})
+		assertions.Nil(err)
+		assertions.Equal(uint64(1), usage.MaxBytes)
+		assertions.Equal(uint64(1), usage.MaxFiles)
+
+		// Removing the override brings the defaults back
+		usage, err = c.SetQuota(&SetQuota{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Equal(uint64(1), usage.MaxFiles)
+		assertions.Zero(*usage.RemainingFiles)
+	})
+	t.Run("Shared directories", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, editor := createSharedDirectory(t, c, models.RoleEditor)
+		var limit = uint64(2)
+		_, err = c.SetQuota(&SetQuota{UserUUID: dir.OwnerUUID, MaxFiles: &limit})
+		assertions.Nil(err)
+
+		// Files created by editors are charged to the owner of the directory
+		_, err = c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: editor, ParentDirectory: &dir.UUID})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+		_, err = c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: editor})
+		assertions.Nil(err)
+	})
+	t.Run("Contents", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner  = uuid.New()
+			bigger = contents + contents
+			limit  = uint64(len(bigger))
+		)
+		_, err = c.SetQuota(&SetQuota{UserUUID: owner, MaxBytes: &limit})
+		assertions.Nil(err)
+		file, err := c.CreateFile(&CreateFile{
+			Filename:  "hello.go",
+			OwnerUUID: owner,
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+		})
+		assertions.Nil(err)
+
+		_, err = c.UpdateContent(&UpdateContent{UserUUID: owner, FileUUID: file.UUID, Hash: utils.Hash(bigger), Size: uint64(len(bigger))})
+		assertions.Nil(err)
+		_, err = c.UpdateContent(&UpdateContent{UserUUID: owner, FileUUID: file.UUID, Hash: utils.Hash(bigger + "!"), Size: uint64(len(bigger)) + 1})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+
+		// Shrinking is allowed even above the limit
+		limit = 1
+		_, err = c.SetQuota(&SetQuota{UserUUID: owner, MaxBytes: &limit})
+		assertions.Nil(err)
+		versions, err := c.ListVersions(&ListVersions{UserUUID: owner, FileUUID: file.UUID})
+		assertions.Nil(err)
+		assertions.Len(versions, 2)
+		_, err = c.RestoreVersion(&RestoreVersion{UserUUID: owner, FileUUID: file.UUID, VersionUUID: versions[1].UUID})
+		assertions.Nil(err)
+		_, err = c.RestoreVersion(&RestoreVersion{UserUUID: owner, FileUUID: file.UUID, VersionUUID: versions[0].UUID})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+	})
+	t.Run("Copies", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			limit = uint64(3 * len(contents))
+		)
+		_, err = c.SetQuota(&SetQuota{UserUUID: owner, MaxBytes: &limit})
+		assertions.Nil(err)
+		dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+		assertions.Nil(err)
+		for _, name := range []string{"a.go", "b.go"} {
+			_, err = c.CreateFile(&CreateFile{
+				Filename:        name,
+				OwnerUUID:       owner,
+				Hash:            utils.Hash(contents),
+				Size:            uint64(len(contents)),
+				ParentDirectory: &dir.UUID,
+			})
+			assertions.Nil(err)
+		}
+
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: dir.UUID, Conflict: ConflictRename})
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+		assertions.Equal([]string{"docs"}, childNames(t, c, owner, nil))
+
+		usage, err := c.GetUsage(&GetUsage{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Equal(uint64(3), usage.Files)
+		assertions.Equal(uint64(len(contents)), *usage.RemainingBytes)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+		c.Quota = config.Quota{Files: 5}
+
+		// Reservations of the same owner are serialized, so only the files that fit are created
+		var (
+			owner = uuid.New()
+			wg    sync.WaitGroup
+			errs  = make([]error, 10)
+		)
+		for index := range errs {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				_, errs[index] = c.CreateFile(&CreateFile{Filename: fmt.Sprintf("%d", index), OwnerUUID: owner})
+			}(index)
+		}
+		wg.Wait()
+
+		var created int
+		for _, err := range errs {
+			if err == nil {
+				created++
+			} else {
+				assertions.ErrorIs(err, ErrQuotaExceeded)
+			}
+		}
+		assertions.Equal(5, created)
+		usage, err := c.GetUsage(&GetUsage{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Equal(uint64(5), usage.Files)
+	})
+}
@@ -1,27 +1,165 @@
 package controller
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
 	"gorm.io/gorm"
 )
 
+// Order of the cursors of ShareWithMe, shares are always sorted by their creation
+const sortBySharedAt SortBy = "sharedAt"
+
 type ShareWithMe struct {
 	UserUUID uuid.UUID `json:"userUUID"`
+	// Only the files owned by this user when set
+	OwnerUUID *uuid.UUID `json:"ownerUUID,omitempty"`
+	// Oldest shares first unless set
+	Descending bool   `json:"descending,omitempty"`
+	Cursor     string `json:"cursor,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
 }
 
-// Used to list all the files shared with the current user
-func (c *Controller) ShareWithMe(swm *ShareWithMe) (shared []models.SharedFile, err error) {
-	err = c.DB.
-		Where("user_uuid = ?", swm.UserUUID).
-		Find(&shared).
-		Error
-	if err != nil {
-		err = fmt.Errorf("failed to obtain share files for user: %w", err)
+// File shared with the user along with the share granting access to it
+type SharedEntry struct {
+	File      models.File `json:"file"`
+	ShareUUID uuid.UUID   `json:"shareUUID"`
+	// The owner of the file for the shares created before sharers were recorded
+	SharerUUID uuid.UUID `json:"sharerUUID"`
+	SharedAt   time.Time `json:"sharedAt"`
+	// Role granted by this share
+	Role models.ShareRole `json:"role"`
+	// Effective role over the file, including the shares of its parents and groups
+	Access    models.ShareRole `json:"access"`
+	ExpiresAt *time.Time       `json:"expiresAt,omitempty"`
+	// Set when shared through a group
+	GroupUUID *uuid.UUID `json:"groupUUID,omitempty"`
+}
+
+type SharedWithMe struct {
+	Entries    []SharedEntry `json:"entries"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// Whether the share goes before the other one when sorted by creation
+func sharedBefore(share, other models.Model, descending bool) bool {
+	if !share.CreatedAt.Equal(other.CreatedAt) {
+		return share.CreatedAt.Before(other.CreatedAt) != descending
+	}
+	return bytes.Compare(share.UUID[:], other.UUID[:]) < 0 != descending
+}
+
+// Used to list all the files shared with the current user, directly or through its groups, sorted by share date.
+// A file shared in several ways is listed once per share.
+// Files the user can't reach anymore, like those with a parent in the trash, are skipped
+func (c *Controller) ShareWithMe(swm *ShareWithMe) (shared SharedWithMe, err error) {
+	var limit = swm.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	} else if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	var after *store.Position
+	if swm.Cursor != "" {
+		cursor, err := decodeListCursor(swm.Cursor)
+		if err != nil {
+			return shared, err
+		}
+		if cursor.SortBy != sortBySharedAt || cursor.Descending != swm.Descending {
+			return shared, fmt.Errorf("%w: cursor doesn't match requested order", ErrInvalidCursor)
+		}
+		after = &store.Position{UUID: cursor.UUID, CreatedAt: cursor.CreatedAt}
 	}
+
+	shared.Entries = make([]SharedEntry, 0)
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var (
+			now       = time.Now()
+			fileUUIDs []uuid.UUID
+		)
+		// Shares of unreachable files are skipped, so more pages are fetched until the requested one is full.
+		// One more share than needed is fetched to know whether there is a next page
+		for shared.NextCursor == "" {
+			var batch = limit + 1 - len(shared.Entries)
+			shares, err := sharesOf(tx, swm.UserUUID, now, store.ListShares{
+				OwnerUUID:   swm.OwnerUUID,
+				SkipTrashed: true,
+				Descending:  swm.Descending,
+				After:       after,
+				Limit:       batch,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to obtain share files for user: %w", err)
+			}
+			var batchUUIDs = make([]uuid.UUID, 0, len(shares))
+			for _, share := range shares {
+				batchUUIDs = append(batchUUIDs, share.FileUUID)
+			}
+			access, err := fileRoles(tx, swm.UserUUID, batchUUIDs)
+			if err != nil {
+				return fmt.Errorf("failed to query file access: %w", err)
+			}
+
+			for _, share := range shares {
+				role := access[share.FileUUID]
+				if role == "" {
+					continue
+				}
+				if len(shared.Entries) == limit {
+					var last = shared.Entries[limit-1]
+					cursor := listCursor{
+						SortBy:     sortBySharedAt,
+						Descending: swm.Descending,
+						CreatedAt:  last.SharedAt,
+						UUID:       last.ShareUUID,
+					}
+					shared.NextCursor = cursor.encode()
+					break
+				}
+				var entry = SharedEntry{
+					File:      models.File{Model: models.Model{UUID: share.FileUUID}},
+					ShareUUID: share.UUID,
+					SharedAt:  share.CreatedAt,
+					Role:      share.Role,
+					Access:    role,
+					ExpiresAt: share.ExpiresAt,
+					GroupUUID: share.GroupUUID,
+				}
+				if share.SharerUUID != nil {
+					entry.SharerUUID = *share.SharerUUID
+				}
+				shared.Entries = append(shared.Entries, entry)
+				fileUUIDs = append(fileUUIDs, share.FileUUID)
+			}
+			if len(shares) < batch {
+				break
+			}
+			var last = shares[len(shares)-1]
+			after = &store.Position{UUID: last.UUID, CreatedAt: last.CreatedAt}
+		}
+
+		files, err := tx.GetFiles(fileUUIDs)
+		if err != nil {
+			return fmt.Errorf("failed to query shared files: %w", err)
+		}
+		var byUUID = make(map[uuid.UUID]models.File, len(files))
+		for _, file := range files {
+			byUUID[file.UUID] = file
+		}
+		for i := range shared.Entries {
+			var entry = &shared.Entries[i]
+			entry.File = byUUID[entry.File.UUID]
+			if entry.SharerUUID == uuid.Nil {
+				entry.SharerUUID = entry.File.OwnerUUID
+			}
+		}
+		return nil
+	})
 	return shared, err
 }
 
@@ -30,30 +168,46 @@ type ShareWithWho struct {
 	FileUUID  uuid.UUID `json:"fileUUID"`
 }
 
-// Used to query users that have access to a file
+// Used to query users that have access to a file, available to its owner and managers.
+// Shares of groups are listed once per current member of the group
 func (c *Controller) ShareWithWho(sww *ShareWithWho) (shared []models.SharedFile, err error) {
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		var file models.File
-		err := tx.
-			Where("uuid = ? AND owner_uuid = ?", sww.FileUUID, sww.OwnerUUID).
-			First(&file).
-			Error
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := accessibleFile(tx, sww.OwnerUUID, sww.FileUUID, models.RoleManager)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = fmt.Errorf("user doesn't have permissions over file: %w: %w", ErrPermissionDenied, err)
+			} else if errors.Is(err, ErrPermissionDenied) {
 				err = fmt.Errorf("user doesn't have permissions over file: %w", err)
 			} else {
 				err = fmt.Errorf("failed to query file access: %w", err)
 			}
 			return err
 		}
-		err = tx.
-			Where("file_uuid = ?", file.UUID).
-			Find(&shared).
-			Error
+		var now = time.Now()
+		shared, err = tx.ListShares(&store.ListShares{
+			FileUUIDs: []uuid.UUID{file.UUID},
+			ActiveAt:  &now,
+		})
 		if err != nil {
-			err = fmt.Errorf("failed to query files: %w", err)
+			return fmt.Errorf("failed to query files: %w", err)
 		}
-		return err
+		grants, err := tx.ListGroupShares(&store.ListGroupShares{
+			FileUUIDs: []uuid.UUID{file.UUID},
+			ActiveAt:  &now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query group shares: %w", err)
+		}
+		for _, grant := range grants {
+			members, err := tx.ListGroupMembers(grant.GroupUUID)
+			if err != nil {
+				return fmt.Errorf("failed to query group members: %w", err)
+			}
+			for _, member := range members {
+				shared = append(shared, groupShareOf(grant, member.UserUUID))
+			}
+		}
+		return nil
 	})
 	return shared, err
 }
@@ -62,31 +216,81 @@ type ShareRequest struct {
 	OwnerUUID      uuid.UUID `json:"ownerUUID"`
 	FileUUID       uuid.UUID `json:"fileUUID"`
 	TargetUserUUID uuid.UUID `json:"targetUserUUID"`
+	// Targets the members of the group instead of a single user
+	TargetGroupUUID *uuid.UUID `json:"targetGroupUUID,omitempty"`
+	// Viewer when empty
+	Role models.ShareRole `json:"role,omitempty"`
+	// Never expires when nil, only used by ShareFile
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
-// Use to share a file other users in the system
-// Intended to be called after obtaining the UUID of the account thanks to the authentication service
+// Validates the requested role and queries the file the user manages
+func managedFile(tx store.MetadataStore, sr *ShareRequest) (file models.File, role models.ShareRole, err error) {
+	role = sr.Role
+	if role == "" {
+		role = models.RoleViewer
+	}
+	if !role.Valid() {
+		return file, role, fmt.Errorf("%w: unknown role %q", ErrInvalidShare, role)
+	}
+	if sr.TargetGroupUUID != nil && sr.TargetUserUUID != uuid.Nil {
+		return file, role, fmt.Errorf("%w: target either a user or a group", ErrInvalidShare)
+	}
+	file, err = accessibleFile(tx, sr.OwnerUUID, sr.FileUUID, models.RoleManager)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+		} else if !errors.Is(err, ErrPermissionDenied) {
+			err = fmt.Errorf("failed to query file: %w", err)
+		}
+		return file, role, err
+	}
+	if sr.TargetGroupUUID != nil {
+		_, err = sharingGroup(tx, sr.OwnerUUID, *sr.TargetGroupUUID)
+	} else if sr.TargetUserUUID == file.OwnerUUID {
+		err = fmt.Errorf("%w: the owner already has full access", ErrInvalidShare)
+	}
+	return file, role, err
+}
+
+// Use to share a file other users in the system, the owner and managers of the file can share it.
+// Intended to be called after obtaining the UUID of the account thanks to the authentication service.
+// Files can also be shared with the groups the user owns or belongs to.
+// Expired shares of the same target are replaced
 func (c *Controller) ShareFile(sr *ShareRequest) (err error) {
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		var file models.File
-		err := tx.
-			Where("uuid = ? AND owner_uuid = ?", sr.FileUUID, sr.OwnerUUID).
-			First(&file).
-			Error
+	var now = time.Now()
+	if sr.ExpiresAt != nil && !sr.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiration must be in the future", ErrInvalidShare)
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, role, err := managedFile(tx, sr)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				err = fmt.Errorf("permission denied: %w", err)
-			} else {
-				err = fmt.Errorf("failed to query file: %w", err)
+			return err
+		}
+		err = dropExpiredShare(tx, file.UUID, sr, now)
+		if err != nil {
+			return fmt.Errorf("failed to replace expired share: %w", err)
+		}
+		if sr.TargetGroupUUID != nil {
+			err = tx.CreateGroupShare(&models.GroupShare{
+				FileUUID:   file.UUID,
+				GroupUUID:  *sr.TargetGroupUUID,
+				Role:       role,
+				SharerUUID: &sr.OwnerUUID,
+				ExpiresAt:  sr.ExpiresAt,
+			})
+			if err != nil {
+				err = fmt.Errorf("failed to create group shared entry: %w", err)
 			}
 			return err
 		}
-		err = tx.
-			Create(&models.SharedFile{
-				FileUUID: file.UUID,
-				UserUUID: sr.TargetUserUUID,
-			}).
-			Error
+		err = tx.CreateShare(&models.SharedFile{
+			FileUUID:   file.UUID,
+			UserUUID:   sr.TargetUserUUID,
+			Role:       role,
+			SharerUUID: &sr.OwnerUUID,
+			ExpiresAt:  sr.ExpiresAt,
+		})
 		if err != nil {
 			err = fmt.Errorf("failed to create shared entry: %w", err)
 		}
@@ -95,26 +299,57 @@ func (c *Controller) ShareFile(sr *ShareRequest) (err error) {
 	return err
 }
 
+// Changes the role of an existing share, the owner and managers of the file can change it.
+// The expiration of the share is kept, expired shares are reported as not found
+func (c *Controller) ChangeShareRole(sr *ShareRequest) (err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, role, err := managedFile(tx, sr)
+		if err != nil {
+			return err
+		}
+		var now = time.Now()
+		if sr.TargetGroupUUID != nil {
+			return changeGroupShareRole(tx, file.UUID, *sr.TargetGroupUUID, role, now)
+		}
+		shares, err := tx.ListShares(&store.ListShares{
+			UserUUID:  &sr.TargetUserUUID,
+			FileUUIDs: []uuid.UUID{file.UUID},
+			ActiveAt:  &now,
+		})
+		if err == nil && len(shares) == 0 {
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query shared entry: %w", err)
+		}
+		var share = shares[0]
+		share.Role = role
+		err = tx.SaveShare(&share)
+		if err != nil {
+			err = fmt.Errorf("failed to update shared entry: %w", err)
+		}
+		return err
+	})
+	return err
+}
+
 // Work almost the same as the ShareFile but intended to remove files
 func (c *Controller) UnshareFile(sr *ShareRequest) (err error) {
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		var file models.File
-		err := tx.
-			Where("uuid = ? AND owner_uuid = ?", sr.FileUUID, sr.OwnerUUID).
-			First(&file).
-			Error
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := accessibleFile(tx, sr.OwnerUUID, sr.FileUUID, models.RoleManager)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				err = fmt.Errorf("permission denied: %w", err)
-			} else {
+				err = fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+			} else if !errors.Is(err, ErrPermissionDenied) {
 				err = fmt.Errorf("failed to query file: %w", err)
 			}
 			return err
 		}
-		err = tx.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			Delete(&models.SharedFile{}).
-			Error
+		if sr.TargetGroupUUID != nil {
+			err = tx.DeleteGroupShare(file.UUID, *sr.TargetGroupUUID)
+		} else {
+			err = tx.DeleteShare(file.UUID, sr.TargetUserUUID)
+		}
 		if err != nil {
 			err = fmt.Errorf("failed to create shared entry: %w", err)
 		}
@@ -122,3 +357,87 @@ func (c *Controller) UnshareFile(sr *ShareRequest) (err error) {
 	})
 	return err
 }
+
+func changeGroupShareRole(tx store.MetadataStore, fileUUID, groupUUID uuid.UUID, role models.ShareRole, now time.Time) error {
+	grants, err := tx.ListGroupShares(&store.ListGroupShares{
+		GroupUUIDs: []uuid.UUID{groupUUID},
+		FileUUIDs:  []uuid.UUID{fileUUID},
+		ActiveAt:   &now,
+	})
+	if err == nil && len(grants) == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query group shared entry: %w", err)
+	}
+	var grant = grants[0]
+	grant.Role = role
+	err = tx.SaveGroupShare(&grant)
+	if err != nil {
+		err = fmt.Errorf("failed to update group shared entry: %w", err)
+	}
+	return err
+}
+
+// Removes the share of the target when it is already expired, so it can be shared again before being reaped
+func dropExpiredShare(tx store.MetadataStore, fileUUID uuid.UUID, sr *ShareRequest, now time.Time) error {
+	if sr.TargetGroupUUID != nil {
+		grants, err := tx.ListGroupShares(&store.ListGroupShares{
+			GroupUUIDs: []uuid.UUID{*sr.TargetGroupUUID},
+			FileUUIDs:  []uuid.UUID{fileUUID},
+			ExpiredAt:  &now,
+		})
+		if err != nil || len(grants) == 0 {
+			return err
+		}
+		return tx.DeleteGroupShare(fileUUID, *sr.TargetGroupUUID)
+	}
+	shares, err := tx.ListShares(&store.ListShares{
+		UserUUID:  &sr.TargetUserUUID,
+		FileUUIDs: []uuid.UUID{fileUUID},
+		ExpiredAt: &now,
+	})
+	if err != nil || len(shares) == 0 {
+		return err
+	}
+	return tx.DeleteShare(fileUUID, sr.TargetUserUUID)
+}
+
+type ReapedShares struct {
+	Shares      []models.SharedFile `json:"shares"`
+	GroupShares []models.GroupShare `json:"groupShares"`
+}
+
+// Deletes the expired shares and returns them, intended to be called periodically.
+// Expired shares grant nothing even before being reaped
+func (c *Controller) ReapExpiredShares() (reaped ReapedShares, err error) {
+	var now = time.Now()
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var err error
+		reaped.Shares, err = tx.ListShares(&store.ListShares{ExpiredAt: &now})
+		if err != nil {
+			return err
+		}
+		for _, share := range reaped.Shares {
+			err = tx.DeleteShare(share.FileUUID, share.UserUUID)
+			if err != nil {
+				return err
+			}
+		}
+		reaped.GroupShares, err = tx.ListGroupShares(&store.ListGroupShares{ExpiredAt: &now})
+		if err != nil {
+			return err
+		}
+		for _, grant := range reaped.GroupShares {
+			err = tx.DeleteGroupShare(grant.FileUUID, grant.GroupUUID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to reap expired shares: %w", err)
+	}
+	return reaped, err
+}
@@ -1,14 +1,34 @@
 package controller
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
 	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
 	"github.com/hawks-atlanta/fs-prototype/utils"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
+func findShare(c *Controller, fileUUID, userUUID uuid.UUID) (share models.SharedFile, err error) {
+	shares, err := c.Store.ListShares(&store.ListShares{
+		UserUUID:  &userUUID,
+		FileUUIDs: []uuid.UUID{fileUUID},
+	})
+	if err == nil && len(shares) == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return share, err
+	}
+	return shares[0], nil
+}
+
 func TestController_ShareWithMe(t *testing.T) {
 	t.Run("Succeed", func(t *testing.T) {
 		assertions := assert.New(t)
@@ -43,7 +63,138 @@ func TestController_ShareWithMe(t *testing.T) {
 		shared, err := c.ShareWithMe(&swm)
 		assertions.Nil(err)
 
-		assertions.Len(shared, 1)
+		assertions.Len(shared.Entries, 1)
+		var entry = shared.Entries[0]
+		assertions.Equal(file.UUID, entry.File.UUID)
+		assertions.Equal(cf.Filename, entry.File.Name)
+		assertions.NotNil(entry.File.Archive)
+		assertions.Equal(cf.Size, entry.File.Archive.Size)
+		assertions.Equal(cf.OwnerUUID, entry.SharerUUID)
+		assertions.Equal(models.RoleViewer, entry.Role)
+		assertions.False(entry.SharedAt.IsZero())
+		assertions.Empty(shared.NextCursor)
+	})
+	t.Run("Inherited access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, user := createSharedDirectory(t, c, models.RoleEditor)
+		var manager = uuid.New()
+		assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID, TargetUserUUID: manager, Role: models.RoleManager}))
+		assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: manager, FileUUID: file.UUID, TargetUserUUID: user}))
+
+		shared, err := c.ShareWithMe(&ShareWithMe{UserUUID: user})
+		assertions.Nil(err)
+		assertions.Len(shared.Entries, 2)
+		var entry = shared.Entries[1]
+		assertions.Equal(file.UUID, entry.File.UUID)
+		assertions.Equal(manager, entry.SharerUUID)
+		assertions.Equal(models.RoleViewer, entry.Role)
+		assertions.Equal(models.RoleEditor, entry.Access)
+
+		// Files under a trashed directory are skipped
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID}))
+		shared, err = c.ShareWithMe(&ShareWithMe{UserUUID: user})
+		assertions.Nil(err)
+		assertions.Len(shared.Entries, 0)
+	})
+	t.Run("Pagination", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owners    = []uuid.UUID{uuid.New(), uuid.New()}
+			user      = uuid.New()
+			fileUUIDs []uuid.UUID
+		)
+		for index := 0; index < 3; index++ {
+			var owner = owners[index%2]
+			file, err := c.CreateFile(&CreateFile{Filename: fmt.Sprintf("shared-%d", index), OwnerUUID: owner})
+			assertions.Nil(err)
+			assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: owner, FileUUID: file.UUID, TargetUserUUID: user}))
+			fileUUIDs = append(fileUUIDs, file.UUID)
+		}
+
+		var swm = ShareWithMe{UserUUID: user, Limit: 2}
+		page, err := c.ShareWithMe(&swm)
+		assertions.Nil(err)
+		assertions.Len(page.Entries, 2)
+		assertions.Equal(fileUUIDs[0], page.Entries[0].File.UUID)
+		assertions.Equal(fileUUIDs[1], page.Entries[1].File.UUID)
+		assertions.NotEmpty(page.NextCursor)
+
+		swm.Cursor = page.NextCursor
+		page, err = c.ShareWithMe(&swm)
+		assertions.Nil(err)
+		assertions.Len(page.Entries, 1)
+		assertions.Equal(fileUUIDs[2], page.Entries[0].File.UUID)
+		assertions.Empty(page.NextCursor)
+
+		swm.Descending = true
+		_, err = c.ShareWithMe(&swm)
+		assertions.ErrorIs(err, ErrInvalidCursor)
+
+		swm.Cursor = ""
+		page, err = c.ShareWithMe(&swm)
+		assertions.Nil(err)
+		assertions.Equal(fileUUIDs[2], page.Entries[0].File.UUID)
+
+		page, err = c.ShareWithMe(&ShareWithMe{UserUUID: user, OwnerUUID: &owners[1]})
+		assertions.Nil(err)
+		assertions.Len(page.Entries, 1)
+		assertions.Equal(fileUUIDs[1], page.Entries[0].File.UUID)
+	})
+	t.Run("Pagination skipping unreachable files", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			user  = uuid.New()
+			group = createGroup(t, c, owner, user)
+		)
+		first, err := c.CreateFile(&CreateFile{Filename: "first", OwnerUUID: owner})
+		assertions.Nil(err)
+		assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: owner, FileUUID: first.UUID, TargetUserUUID: user}))
+
+		// Shares of files under a trashed directory are skipped, even when they fill whole batches
+		trashed, err := c.CreateFile(&CreateFile{Filename: "trashed", OwnerUUID: owner})
+		assertions.Nil(err)
+		for index := 0; index < 3; index++ {
+			file, err := c.CreateFile(&CreateFile{Filename: fmt.Sprintf("hidden-%d", index), OwnerUUID: owner, ParentDirectory: &trashed.UUID})
+			assertions.Nil(err)
+			assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: owner, FileUUID: file.UUID, TargetUserUUID: user}))
+		}
+		assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: owner, FileUUID: trashed.UUID}))
+
+		last, err := c.CreateFile(&CreateFile{Filename: "last", OwnerUUID: owner})
+		assertions.Nil(err)
+		assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: owner, FileUUID: last.UUID, TargetGroupUUID: &group.UUID, Role: models.RoleEditor}))
+
+		var swm = ShareWithMe{UserUUID: user, Limit: 1}
+		page, err := c.ShareWithMe(&swm)
+		assertions.Nil(err)
+		assertions.Len(page.Entries, 1)
+		assertions.Equal(first.UUID, page.Entries[0].File.UUID)
+		assertions.NotEmpty(page.NextCursor)
+
+		swm.Cursor = page.NextCursor
+		page, err = c.ShareWithMe(&swm)
+		assertions.Nil(err)
+		assertions.Len(page.Entries, 1)
+		assertions.Equal(last.UUID, page.Entries[0].File.UUID)
+		assertions.Equal(models.RoleEditor, page.Entries[0].Access)
+		assertions.Equal(group.UUID, *page.Entries[0].GroupUUID)
+		assertions.Empty(page.NextCursor)
 	})
 }
 
@@ -150,11 +301,7 @@ func TestController_ShareFile(t *testing.T) {
 		err = c.ShareFile(&sr)
 		assertions.Nil(err)
 
-		var check models.SharedFile
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err := findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.Nil(err)
 
 		assertions.Equal(file.UUID, check.FileUUID)
@@ -187,11 +334,7 @@ func TestController_ShareFile(t *testing.T) {
 		err = c.ShareFile(&sr)
 		assertions.NotNil(err)
 
-		var check models.SharedFile
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		_, err = findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.NotNil(err)
 	})
 	t.Run("Share twice to same user", func(t *testing.T) {
@@ -221,11 +364,7 @@ func TestController_ShareFile(t *testing.T) {
 		err = c.ShareFile(&sr)
 		assertions.Nil(err)
 
-		var check models.SharedFile
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err := findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.Nil(err)
 
 		assertions.Equal(file.UUID, check.FileUUID)
@@ -264,11 +403,7 @@ func TestController_UnshareFile(t *testing.T) {
 		err = c.ShareFile(&sr)
 		assertions.Nil(err)
 
-		var check models.SharedFile
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err := findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.Nil(err)
 
 		assertions.Equal(file.UUID, check.FileUUID)
@@ -278,11 +413,7 @@ func TestController_UnshareFile(t *testing.T) {
 		assertions.Nil(err)
 
 		// Check share is removed
-		check = models.SharedFile{}
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err = findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.NotNil(err)
 	})
 	t.Run("Not owned file", func(t *testing.T) {
@@ -312,11 +443,7 @@ func TestController_UnshareFile(t *testing.T) {
 		err = c.ShareFile(&sr)
 		assertions.Nil(err)
 
-		var check models.SharedFile
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err := findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.Nil(err)
 
 		assertions.Equal(file.UUID, check.FileUUID)
@@ -327,14 +454,465 @@ func TestController_UnshareFile(t *testing.T) {
 		assertions.NotNil(err)
 
 		// Check share is removed
-		check = models.SharedFile{}
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", file.UUID, sr.TargetUserUUID).
-			First(&check).
-			Error
+		check, err = findShare(c, file.UUID, sr.TargetUserUUID)
 		assertions.Nil(err)
 
 		assertions.Equal(file.UUID, check.FileUUID)
 		assertions.Equal(sr.TargetUserUUID, check.UserUUID)
 	})
 }
+
+// Creates a directory with a file inside and shares the directory with a new user
+func createSharedDirectory(t *testing.T, c *Controller, role models.ShareRole) (dir, file models.File, user uuid.UUID) {
+	assertions := assert.New(t)
+
+	var owner = uuid.New()
+	dir, err := c.CreateFile(&CreateFile{Filename: "Shared", OwnerUUID: owner})
+	assertions.Nil(err)
+	var contents = "fmt.Println(`hello`)"
+	file, err = c.CreateFile(&CreateFile{
+		Filename:        "hello-world.go",
+		OwnerUUID:       owner,
+		Hash:            utils.Hash(contents),
+		ParentDirectory: &dir.UUID,
+		Size:            uint64(len(contents)),
+	})
+	assertions.Nil(err)
+
+	user = uuid.New()
+	err = c.ShareFile(&ShareRequest{
+		OwnerUUID:      owner,
+		FileUUID:       dir.UUID,
+		TargetUserUUID: user,
+		Role:           role,
+	})
+	assertions.Nil(err)
+	return dir, file, user
+}
+
+func TestController_ShareRoles(t *testing.T) {
+	t.Run("Default role", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, user := createSharedDirectory(t, c, "")
+		check, err := findShare(c, dir.UUID, user)
+		assertions.Nil(err)
+		assertions.Equal(models.RoleViewer, check.Role)
+	})
+	t.Run("Invalid role", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, _ := createSharedDirectory(t, c, models.RoleViewer)
+		for _, role := range []models.ShareRole{"admin", models.RoleOwner} {
+			var sr = ShareRequest{
+				OwnerUUID:      dir.OwnerUUID,
+				FileUUID:       dir.UUID,
+				TargetUserUUID: uuid.New(),
+				Role:           role,
+			}
+			err = c.ShareFile(&sr)
+			assertions.ErrorIs(err, ErrInvalidShare)
+		}
+	})
+	t.Run("Viewer", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, user := createSharedDirectory(t, c, models.RoleViewer)
+		err = c.CanReadFile(&CanReadFile{UserUUID: user, FileUUID: file.UUID})
+		assertions.Nil(err)
+
+		_, err = c.CreateFile(&CreateFile{Filename: "new", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		var newName = "renamed.go"
+		err = c.MoveFile(&MoveFile{OwnerUUID: user, FileUUID: file.UUID, NewName: &newName})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		err = c.DeleteFile(&DeleteFile{OwnerUUID: user, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		err = c.ShareFile(&ShareRequest{OwnerUUID: user, FileUUID: file.UUID, TargetUserUUID: uuid.New()})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+	t.Run("Editor", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, user := createSharedDirectory(t, c, models.RoleEditor)
+
+		// Files created in the shared directory belong to its owner
+		sub, err := c.CreateFile(&CreateFile{Filename: "sub", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+		assertions.Equal(dir.OwnerUUID, sub.OwnerUUID)
+
+		var newName = "renamed.go"
+		err = c.MoveFile(&MoveFile{OwnerUUID: user, FileUUID: file.UUID, NewLocation: &sub.UUID, NewName: &newName})
+		assertions.Nil(err)
+		moved, err := c.Store.GetFile(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(sub.UUID, *moved.ParentUUID)
+		assertions.Equal(newName, moved.Name)
+
+		// Content can't be moved out of the shared hierarchy into the editor's files
+		own, err := c.CreateFile(&CreateFile{Filename: "own", OwnerUUID: user})
+		assertions.Nil(err)
+		err = c.MoveFile(&MoveFile{OwnerUUID: user, FileUUID: file.UUID, NewLocation: &own.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		// Only the owner can delete permanently
+		err = c.DeleteFile(&DeleteFile{OwnerUUID: user, FileUUID: file.UUID, Permanent: true})
+		assertions.NotNil(err)
+		err = c.DeleteFile(&DeleteFile{OwnerUUID: user, FileUUID: file.UUID})
+		assertions.Nil(err)
+		trash, err := c.ListTrash(&ListTrash{OwnerUUID: dir.OwnerUUID})
+		assertions.Nil(err)
+		assertions.Len(trash, 1)
+
+		err = c.ShareFile(&ShareRequest{OwnerUUID: user, FileUUID: dir.UUID, TargetUserUUID: uuid.New()})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+	t.Run("Manager", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, user := createSharedDirectory(t, c, models.RoleManager)
+
+		var sr = ShareRequest{
+			OwnerUUID:      user,
+			FileUUID:       file.UUID,
+			TargetUserUUID: uuid.New(),
+			Role:           models.RoleEditor,
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		shared, err := c.ShareWithWho(&ShareWithWho{OwnerUUID: user, FileUUID: file.UUID})
+		assertions.Nil(err)
+		assertions.Len(shared, 1)
+
+		// Sharing with the owner is pointless
+		sr.TargetUserUUID = dir.OwnerUUID
+		err = c.ShareFile(&sr)
+		assertions.ErrorIs(err, ErrInvalidShare)
+	})
+	t.Run("Highest role of the hierarchy", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, user := createSharedDirectory(t, c, models.RoleViewer)
+		err = c.ShareFile(&ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       file.UUID,
+			TargetUserUUID: user,
+			Role:           models.RoleEditor,
+		})
+		assertions.Nil(err)
+
+		var newName = "renamed.go"
+		err = c.MoveFile(&MoveFile{OwnerUUID: user, FileUUID: file.UUID, NewName: &newName})
+		assertions.Nil(err)
+		_, err = c.CreateFile(&CreateFile{Filename: "new", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+}
+
+func TestController_ChangeShareRole(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleViewer)
+		var sr = ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: user,
+			Role:           models.RoleEditor,
+		}
+		err = c.ChangeShareRole(&sr)
+		assertions.Nil(err)
+
+		check, err := findShare(c, dir.UUID, user)
+		assertions.Nil(err)
+		assertions.Equal(models.RoleEditor, check.Role)
+
+		_, err = c.CreateFile(&CreateFile{Filename: "new", OwnerUUID: user, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+	})
+	t.Run("Not shared", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var sr = ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: uuid.New(),
+			Role:           models.RoleEditor,
+		}
+		err = c.ChangeShareRole(&sr)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Not manager", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleEditor)
+		var sr = ShareRequest{
+			OwnerUUID:      user,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: user,
+			Role:           models.RoleManager,
+		}
+		err = c.ChangeShareRole(&sr)
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+}
+
+func TestController_CollaborativeDirectory(t *testing.T) {
+	t.Run("Create and upload as editor", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleEditor)
+		var (
+			contents = "collaborative contents"
+			cf       = CreateFile{
+				Filename:        "contribution.txt",
+				OwnerUUID:       user,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &dir.UUID,
+				Size:            uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		assertions.Equal(dir.OwnerUUID, file.OwnerUUID)
+		assertions.Equal(user, *file.CreatorUUID)
+
+		versions, err := c.Store.ListVersions(file.UUID)
+		assertions.Nil(err)
+		assertions.Equal(user, versions[0].AuthorUUID)
+
+		session, err := c.StartUpload(&StartUpload{UserUUID: user, FileUUID: file.UUID})
+		assertions.Nil(err)
+		_, err = c.UploadChunk(&UploadChunk{
+			UserUUID:    user,
+			SessionUUID: session.UUID,
+			Contents:    strings.NewReader(contents),
+		})
+		assertions.Nil(err)
+		archive, err := c.FinalizeUpload(&FinalizeUpload{UserUUID: user, SessionUUID: session.UUID})
+		assertions.Nil(err)
+		assertions.True(archive.IsReady)
+
+		// Owners creating in their own directories are not recorded as creators
+		own, err := c.CreateFile(&CreateFile{Filename: "own", OwnerUUID: dir.OwnerUUID, ParentDirectory: &dir.UUID})
+		assertions.Nil(err)
+		assertions.Nil(own.CreatorUUID)
+	})
+	t.Run("Upload as viewer", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		_, file, user := createSharedDirectory(t, c, models.RoleViewer)
+		_, err = c.StartUpload(&StartUpload{UserUUID: user, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+	t.Run("Revoked share", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleEditor)
+		var (
+			contents = "collaborative contents"
+			cf       = CreateFile{
+				Filename:        "contribution.txt",
+				OwnerUUID:       user,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &dir.UUID,
+				Size:            uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+		session, err := c.StartUpload(&StartUpload{UserUUID: user, FileUUID: file.UUID})
+		assertions.Nil(err)
+
+		err = c.UnshareFile(&ShareRequest{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID, TargetUserUUID: user})
+		assertions.Nil(err)
+
+		// The creator loses access while the owner keeps the file
+		_, err = c.UploadChunk(&UploadChunk{
+			UserUUID:    user,
+			SessionUUID: session.UUID,
+			Contents:    strings.NewReader(contents),
+		})
+		assertions.NotNil(err)
+		err = c.CanReadFile(&CanReadFile{UserUUID: user, FileUUID: file.UUID})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+
+		dirList, err := c.ListDirectory(&ListDirectory{UserUUID: dir.OwnerUUID, ParentUUID: &dir.UUID})
+		assertions.Nil(err)
+		var names []string
+		for _, child := range dirList.Files {
+			names = append(names, child.Name)
+		}
+		assertions.Contains(names, "contribution.txt")
+	})
+}
+
+// Makes the share of the user look expired
+func expireShare(t *testing.T, c *Controller, fileUUID, userUUID uuid.UUID) {
+	share, err := findShare(c, fileUUID, userUUID)
+	assert.Nil(t, err)
+	var past = time.Now().Add(-time.Minute)
+	share.ExpiresAt = &past
+	assert.Nil(t, c.Store.SaveShare(&share))
+}
+
+func TestController_ExpiringShares(t *testing.T) {
+	t.Run("Expiration", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var (
+			contractor = uuid.New()
+			past       = time.Now().Add(-time.Minute)
+			future     = time.Now().Add(time.Hour)
+			sr         = ShareRequest{
+				OwnerUUID:      dir.OwnerUUID,
+				FileUUID:       dir.UUID,
+				TargetUserUUID: contractor,
+				ExpiresAt:      &past,
+			}
+		)
+		assertions.ErrorIs(c.ShareFile(&sr), ErrInvalidShare)
+
+		sr.ExpiresAt = &future
+		assertions.Nil(c.ShareFile(&sr))
+		assertions.Nil(c.CanReadFile(&CanReadFile{UserUUID: contractor, FileUUID: file.UUID}))
+		check, err := findShare(c, dir.UUID, contractor)
+		assertions.Nil(err)
+		assertions.True(future.Equal(*check.ExpiresAt))
+
+		expireShare(t, c, dir.UUID, contractor)
+		assertions.ErrorIs(c.CanReadFile(&CanReadFile{UserUUID: contractor, FileUUID: file.UUID}), ErrPermissionDenied)
+		withMe, err := c.ShareWithMe(&ShareWithMe{UserUUID: contractor})
+		assertions.Nil(err)
+		assertions.Len(withMe.Entries, 0)
+		shared, err := c.ShareWithWho(&ShareWithWho{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID})
+		assertions.Nil(err)
+		assertions.Len(shared, 1)
+		assertions.NotEqual(contractor, shared[0].UserUUID)
+		err = c.ChangeShareRole(&ShareRequest{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID, TargetUserUUID: contractor, Role: models.RoleEditor})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		// Sharing again replaces the expired share
+		sr.ExpiresAt = nil
+		assertions.Nil(c.ShareFile(&sr))
+		assertions.Nil(c.CanReadFile(&CanReadFile{UserUUID: contractor, FileUUID: file.UUID}))
+	})
+	t.Run("Groups", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, _ := createSharedDirectory(t, c, models.RoleViewer)
+		var (
+			member = uuid.New()
+			group  = createGroup(t, c, dir.OwnerUUID, member)
+			past   = time.Now().Add(-time.Minute)
+		)
+		assertions.Nil(c.Store.CreateGroupShare(&models.GroupShare{FileUUID: dir.UUID, GroupUUID: group.UUID, ExpiresAt: &past}))
+		assertions.ErrorIs(c.CanReadFile(&CanReadFile{UserUUID: member, FileUUID: file.UUID}), ErrPermissionDenied)
+		shared, err := c.ShareWithMe(&ShareWithMe{UserUUID: member})
+		assertions.Nil(err)
+		assertions.Len(shared.Entries, 0)
+	})
+	t.Run("Reap", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, _, user := createSharedDirectory(t, c, models.RoleViewer)
+		var (
+			group = createGroup(t, c, dir.OwnerUUID)
+			past  = time.Now().Add(-time.Minute)
+		)
+		assertions.Nil(c.Store.CreateGroupShare(&models.GroupShare{FileUUID: dir.UUID, GroupUUID: group.UUID, ExpiresAt: &past}))
+		expireShare(t, c, dir.UUID, user)
+		var permanent = uuid.New()
+		assertions.Nil(c.ShareFile(&ShareRequest{OwnerUUID: dir.OwnerUUID, FileUUID: dir.UUID, TargetUserUUID: permanent}))
+
+		reaped, err := c.ReapExpiredShares()
+		assertions.Nil(err)
+		var reapedUsers []uuid.UUID
+		for _, share := range reaped.Shares {
+			reapedUsers = append(reapedUsers, share.UserUUID)
+		}
+		assertions.Contains(reapedUsers, user)
+		assertions.NotContains(reapedUsers, permanent)
+		assertions.NotEmpty(reaped.GroupShares)
+
+		_, err = findShare(c, dir.UUID, user)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		_, err = findShare(c, dir.UUID, permanent)
+		assertions.Nil(err)
+	})
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+// Time trashed files are kept before being purged, unless configured otherwise
+const DefaultTrashRetention = config.DefaultTrashRetention
+
+type ListTrash struct {
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+}
+
+// Lists the files in the trash of the user, most recently trashed first
+func (c *Controller) ListTrash(lt *ListTrash) (files []models.File, err error) {
+	files, err = c.Store.ListTrashed(&store.ListTrashed{
+		OwnerUUID: &lt.OwnerUUID,
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to list trash: %w", err)
+	}
+	return files, err
+}
+
+type RestoreFile struct {
+	OwnerUUID uuid.UUID `json:"ownerUUID"`
+	FileUUID  uuid.UUID `json:"fileUUID"`
+	// ConflictRename when empty
+	Conflict ConflictPolicy `json:"conflict,omitempty"`
+}
+
+// Puts a trashed file back in its original directory.
+// If the directory no longer exists or is in the trash too, the file is restored in the root.
+// When the original name is already taken the conflict policy applies, the file is renamed by default.
+// Directories merged into an existing one are removed and the existing one is returned
+func (c *Controller) RestoreFile(rf *RestoreFile) (file models.File, err error) {
+	err = checkConflictPolicy(rf.Conflict)
+	if err != nil {
+		return file, err
+	}
+	var policy = rf.Conflict
+	if policy == "" {
+		policy = ConflictRename
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var err error
+		file, err = ownedFile(tx, rf.OwnerUUID, rf.FileUUID)
+		if err == nil && file.TrashedAt == nil {
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query trashed file: %w", err)
+		}
+
+		var destination *uuid.UUID
+		if file.TrashedFromUUID != nil {
+			parent, err := ownedFile(tx, file.OwnerUUID, *file.TrashedFromUUID)
+			if err == nil {
+				trashed, err := isTrashed(tx, parent.UUID)
+				if err != nil {
+					return err
+				}
+				if !trashed {
+					destination = &parent.UUID
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to query original directory: %w", err)
+			}
+		}
+
+		name, merge, err := resolveConflict(tx, &placement{
+			OwnerUUID:   file.OwnerUUID,
+			ParentUUID:  destination,
+			Name:        file.Name,
+			File:        &file,
+			IsDirectory: file.ArchiveUUID == nil,
+		}, policy)
+		if err != nil {
+			return err
+		}
+		if merge != nil {
+			err = mergeDirectory(tx, &file, merge)
+			file = *merge
+			return err
+		}
+		file.ParentUUID = destination
+		file.Name = name
+		file.TrashedAt = nil
+		file.TrashedFromUUID = nil
+		err = tx.SaveFile(&file)
+		if err != nil {
+			return fmt.Errorf("failed to restore file: %w", nameConflict(err, name))
+		}
+		return nil
+	})
+	return file, err
+}
+
+type PurgeTrash struct {
+	OwnerUUID *uuid.UUID    `json:"ownerUUID,omitempty"`
+	Retention time.Duration `json:"retention"`
+}
+
+// Permanently deletes the files trashed before the retention period.
+// Intended to be called periodically, optionally restricted to a single user.
+// A zero retention empties the trash
+func (c *Controller) PurgeTrash(pt *PurgeTrash) (purged []models.File, err error) {
+	var cutoff = time.Now().Add(-pt.Retention)
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		var err error
+		purged, err = tx.ListTrashed(&store.ListTrashed{
+			OwnerUUID: pt.OwnerUUID,
+			Before:    &cutoff,
+		})
+		if err != nil || len(purged) == 0 {
+			return err
+		}
+		var uuids = make([]uuid.UUID, 0, len(purged))
+		for _, file := range purged {
+			uuids = append(uuids, file.UUID)
+		}
+		return tx.DeleteFiles(uuids...)
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return purged, err
+}
+
+// Checks if the file or any of its parents is in the trash
+func isTrashed(tx store.MetadataStore, fileUUID uuid.UUID) (trashed bool, err error) {
+	hierarchy, err := tx.Ancestors(fileUUID)
+	if err != nil {
+		return false, fmt.Errorf("failed to query file hierarchy: %w", err)
+	}
+	for _, file := range hierarchy {
+		if file.TrashedAt != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,352 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestController_ListTrash(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var df = DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  root.UUID,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		var lt = ListTrash{
+			OwnerUUID: owner,
+		}
+		trash, err := c.ListTrash(&lt)
+		assertions.Nil(err)
+
+		assertions.Len(trash, 1)
+		assertions.Equal(root.UUID, trash[0].UUID)
+
+		// Trashed files are not listed in the root
+		var ld = ListDirectory{
+			UserUUID: owner,
+		}
+		dir, err := c.ListDirectory(&ld)
+		assertions.Nil(err)
+
+		assertions.Len(dir.Files, 0)
+	})
+}
+
+func TestController_RestoreFile(t *testing.T) {
+	t.Run("Original location", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		createTestTree(t, c, owner)
+
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/projects",
+		}
+		projects, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  projects.UUID,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		var rf = RestoreFile{
+			OwnerUUID: owner,
+			FileUUID:  projects.UUID,
+		}
+		restored, err := c.RestoreFile(&rf)
+		assertions.Nil(err)
+
+		assertions.Equal(*projects.ParentUUID, *restored.ParentUUID)
+		assertions.Equal("projects", restored.Name)
+		assertions.Nil(restored.TrashedAt)
+
+		rp.Path = "/Desktop/projects/main.go"
+		_, err = c.ResolvePath(&rp)
+		assertions.Nil(err)
+	})
+	t.Run("Name conflict", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/notes.txt",
+		}
+		notes, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  notes.UUID,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		// Take the name while the file is in the trash
+		var (
+			contents = "new notes"
+			cf       = CreateFile{
+				Filename:        "notes.txt",
+				OwnerUUID:       owner,
+				Hash:            utils.Hash(contents),
+				ParentDirectory: &root.UUID,
+				Size:            uint64(len(contents)),
+			}
+		)
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var rf = RestoreFile{
+			OwnerUUID: owner,
+			FileUUID:  notes.UUID,
+		}
+		restored, err := c.RestoreFile(&rf)
+		assertions.Nil(err)
+
+		assertions.Equal(root.UUID, *restored.ParentUUID)
+		assertions.Equal("notes (1).txt", restored.Name)
+	})
+	t.Run("Original directory trashed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var rp = ResolvePath{
+			UserUUID: owner,
+			Path:     "/Desktop/notes.txt",
+		}
+		notes, err := c.ResolvePath(&rp)
+		assertions.Nil(err)
+
+		for _, fileUUID := range []uuid.UUID{notes.UUID, root.UUID} {
+			var df = DeleteFile{
+				OwnerUUID: owner,
+				FileUUID:  fileUUID,
+			}
+			err = c.DeleteFile(&df)
+			assertions.Nil(err)
+		}
+
+		var rf = RestoreFile{
+			OwnerUUID: owner,
+			FileUUID:  notes.UUID,
+		}
+		restored, err := c.RestoreFile(&rf)
+		assertions.Nil(err)
+
+		assertions.Nil(restored.ParentUUID)
+	})
+	t.Run("Not trashed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var rf = RestoreFile{
+			OwnerUUID: owner,
+			FileUUID:  root.UUID,
+		}
+		_, err = c.RestoreFile(&rf)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Shared access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       root.UUID,
+			TargetUserUUID: uuid.New(),
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		var df = DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  root.UUID,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		var crf = CanReadFile{
+			UserUUID: sr.TargetUserUUID,
+			FileUUID: root.UUID,
+		}
+		err = c.CanReadFile(&crf)
+		assertions.NotNil(err)
+
+		var rf = RestoreFile{
+			OwnerUUID: owner,
+			FileUUID:  root.UUID,
+		}
+		_, err = c.RestoreFile(&rf)
+		assertions.Nil(err)
+
+		err = c.CanReadFile(&crf)
+		assertions.Nil(err)
+	})
+}
+
+// Creates docs/nested and moves docs to the trash, nested stays attached to it
+func createTrashedHierarchy(t *testing.T, c *Controller, owner uuid.UUID) (nested models.File) {
+	assertions := assert.New(t)
+
+	dir, err := c.CreateFile(&CreateFile{Filename: "docs", OwnerUUID: owner})
+	assertions.Nil(err)
+	nested, err = c.CreateFile(&CreateFile{Filename: "nested", OwnerUUID: owner, ParentDirectory: &dir.UUID})
+	assertions.Nil(err)
+	assertions.Nil(c.DeleteFile(&DeleteFile{OwnerUUID: owner, FileUUID: dir.UUID}))
+	return nested
+}
+
+func TestController_TrashedHierarchy(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		nested := createTrashedHierarchy(t, c, owner)
+		_, err = c.CreateFile(&CreateFile{Filename: "notes", OwnerUUID: owner, ParentDirectory: &nested.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Move", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		nested := createTrashedHierarchy(t, c, owner)
+		file, err := c.CreateFile(&CreateFile{Filename: "notes", OwnerUUID: owner})
+		assertions.Nil(err)
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: file.UUID, NewLocation: &nested.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		// Nor can the files inside be moved out
+		err = c.MoveFile(&MoveFile{OwnerUUID: owner, FileUUID: nested.UUID, ToRoot: true})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Copy", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var owner = uuid.New()
+		nested := createTrashedHierarchy(t, c, owner)
+		file, err := c.CreateFile(&CreateFile{Filename: "notes", OwnerUUID: owner})
+		assertions.Nil(err)
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: file.UUID, NewLocation: &nested.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		_, err = c.CopyFile(&CopyFile{UserUUID: owner, FileUUID: nested.UUID})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestController_PurgeTrash(t *testing.T) {
+	t.Run("Retention", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var df = DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  root.UUID,
+		}
+		err = c.DeleteFile(&df)
+		assertions.Nil(err)
+
+		// Not old enough
+		var pt = PurgeTrash{
+			OwnerUUID: &owner,
+			Retention: time.Hour,
+		}
+		purged, err := c.PurgeTrash(&pt)
+		assertions.Nil(err)
+		assertions.Len(purged, 0)
+
+		// Age the trashed file
+		trashed, err := c.Store.GetFile(root.UUID)
+		assertions.Nil(err)
+		var trashedAt = time.Now().Add(-2 * time.Hour)
+		trashed.TrashedAt = &trashedAt
+		err = c.Store.SaveFile(&trashed)
+		assertions.Nil(err)
+		contents, err := c.Store.Subtree(&store.Subtree{RootUUID: root.UUID})
+		assertions.Nil(err)
+
+		purged, err = c.PurgeTrash(&pt)
+		assertions.Nil(err)
+		assertions.Len(purged, 1)
+		assertions.Equal(root.UUID, purged[0].UUID)
+
+		// Contents are removed too
+		assertions.Greater(len(contents), 1)
+		for _, file := range contents {
+			_, err = c.Store.GetFile(file.UUID)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		}
+	})
+}
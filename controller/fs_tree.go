@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+)
+
+type TreeFilter string
+
+const (
+	TreeAll         TreeFilter = "all"
+	TreeFiles       TreeFilter = "files"
+	TreeDirectories TreeFilter = "directories"
+)
+
+type Tree struct {
+	UserUUID uuid.UUID  `json:"userUUID"`
+	RootUUID uuid.UUID  `json:"rootUUID"`
+	MaxDepth int        `json:"maxDepth,omitempty"`
+	Filter   TreeFilter `json:"filter,omitempty"`
+}
+
+type TreeNode struct {
+	models.File
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// Returns the whole hierarchy under a directory.
+// A MaxDepth of zero means no limit, the root is at depth zero.
+// When filtering by files, directories are only kept if they lead to a file
+func (c *Controller) Tree(t *Tree) (root *TreeNode, err error) {
+	var filter = t.Filter
+	if filter == "" {
+		filter = TreeAll
+	}
+	if filter != TreeAll && filter != TreeFiles && filter != TreeDirectories {
+		return nil, fmt.Errorf("unknown tree filter: %s", filter)
+	}
+
+	var crf = CanReadFile{
+		UserUUID: t.UserUUID,
+		FileUUID: t.RootUUID,
+	}
+	err = c.CanReadFile(&crf)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := c.Store.Subtree(&store.Subtree{
+		RootUUID:        t.RootUUID,
+		MaxDepth:        t.MaxDepth,
+		DirectoriesOnly: filter == TreeDirectories,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tree: %w", err)
+	}
+	if files[0].ArchiveUUID != nil {
+		return nil, ErrNotDirectory
+	}
+
+	// Files are sorted by depth so parents are always seen before their children
+	var nodes = make(map[uuid.UUID]*TreeNode, len(files))
+	for _, file := range files {
+		node := &TreeNode{File: file}
+		nodes[node.UUID] = node
+		if root == nil {
+			root = node
+			continue
+		}
+		if parent, found := nodes[*node.ParentUUID]; found {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	if filter == TreeFiles {
+		root.prune()
+	}
+	return root, nil
+}
+
+// Removes the directories that don't contain any file.
+// Returns true when the node still holds files
+func (tn *TreeNode) prune() (hasFiles bool) {
+	var children []*TreeNode
+	for _, child := range tn.Children {
+		if child.ArchiveUUID != nil || child.prune() {
+			children = append(children, child)
+		}
+	}
+	tn.Children = children
+	return len(children) > 0
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Creates the following hierarchy for the owner:
+//
+//	Desktop/
+//	├── empty/
+//	├── notes.txt
+//	└── projects/
+//	    └── main.go
+func createTestTree(t *testing.T, c *Controller, owner uuid.UUID) (root models.File) {
+	assertions := assert.New(t)
+
+	var err error
+	root, err = c.CreateFile(&CreateFile{Filename: "Desktop", OwnerUUID: owner})
+	assertions.Nil(err)
+	projects, err := c.CreateFile(&CreateFile{Filename: "projects", OwnerUUID: owner, ParentDirectory: &root.UUID})
+	assertions.Nil(err)
+	_, err = c.CreateFile(&CreateFile{Filename: "empty", OwnerUUID: owner, ParentDirectory: &root.UUID})
+	assertions.Nil(err)
+	for _, cf := range []CreateFile{
+		{Filename: "main.go", ParentDirectory: &projects.UUID},
+		{Filename: "notes.txt", ParentDirectory: &root.UUID},
+	} {
+		cf.OwnerUUID = owner
+		cf.Hash = utils.Hash(cf.Filename)
+		cf.Size = uint64(len(cf.Filename))
+		_, err = c.CreateFile(&cf)
+		assertions.Nil(err)
+	}
+	return root
+}
+
+func TestController_Tree(t *testing.T) {
+	t.Run("Full tree", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var tr = Tree{
+			UserUUID: owner,
+			RootUUID: root.UUID,
+		}
+		tree, err := c.Tree(&tr)
+		assertions.Nil(err)
+
+		assertions.Equal(root.UUID, tree.UUID)
+		assertions.Len(tree.Children, 3)
+		assertions.Equal("empty", tree.Children[0].Name)
+		assertions.Equal("notes.txt", tree.Children[1].Name)
+		assertions.Equal("projects", tree.Children[2].Name)
+		assertions.NotNil(tree.Children[1].Archive)
+		assertions.Len(tree.Children[2].Children, 1)
+		assertions.Equal("main.go", tree.Children[2].Children[0].Name)
+	})
+	t.Run("Max depth", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var tr = Tree{
+			UserUUID: owner,
+			RootUUID: root.UUID,
+			MaxDepth: 1,
+		}
+		tree, err := c.Tree(&tr)
+		assertions.Nil(err)
+
+		assertions.Len(tree.Children, 3)
+		assertions.Len(tree.Children[2].Children, 0)
+	})
+	t.Run("Only directories", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var tr = Tree{
+			UserUUID: owner,
+			RootUUID: root.UUID,
+			Filter:   TreeDirectories,
+		}
+		tree, err := c.Tree(&tr)
+		assertions.Nil(err)
+
+		assertions.Len(tree.Children, 2)
+		assertions.Equal("empty", tree.Children[0].Name)
+		assertions.Equal("projects", tree.Children[1].Name)
+		assertions.Len(tree.Children[1].Children, 0)
+	})
+	t.Run("Only files", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var tr = Tree{
+			UserUUID: owner,
+			RootUUID: root.UUID,
+			Filter:   TreeFiles,
+		}
+		tree, err := c.Tree(&tr)
+		assertions.Nil(err)
+
+		assertions.Len(tree.Children, 2)
+		assertions.Equal("notes.txt", tree.Children[0].Name)
+		assertions.Equal("projects", tree.Children[1].Name)
+		assertions.Len(tree.Children[1].Children, 1)
+	})
+	t.Run("Shared directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var sr = ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       root.UUID,
+			TargetUserUUID: uuid.New(),
+		}
+		err = c.ShareFile(&sr)
+		assertions.Nil(err)
+
+		var tr = Tree{
+			UserUUID: sr.TargetUserUUID,
+			RootUUID: root.UUID,
+		}
+		tree, err := c.Tree(&tr)
+		assertions.Nil(err)
+
+		assertions.Len(tree.Children, 3)
+	})
+	t.Run("Zero Access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var root = createTestTree(t, c, uuid.New())
+		var tr = Tree{
+			UserUUID: uuid.New(),
+			RootUUID: root.UUID,
+		}
+		_, err = c.Tree(&tr)
+		assertions.NotNil(err)
+	})
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultChunkSize uint64 = 8 << 20
+	MinChunkSize     uint64 = 64 << 10
+	MaxChunkSize     uint64 = 64 << 20
+	// Chunks of a single session, larger archives need larger chunks
+	MaxChunkCount uint64 = 10000
+)
+
+type StartUpload struct {
+	UserUUID  uuid.UUID `json:"userUUID"`
+	FileUUID  uuid.UUID `json:"fileUUID"`
+	ChunkSize uint64    `json:"chunkSize,omitempty"`
+}
+
+// Opens a resumable upload for the pending contents of a file, editors of shared files can upload them too.
+// Starting an upload for a file that already has one resumes the existing session.
+// Chunk sizes outside MinChunkSize and MaxChunkSize, or splitting the archive in more than MaxChunkCount chunks,
+// are rejected with ErrInvalidChunk. Archives with a hash the blob store can't hold fail with blobstore.ErrInvalidHash
+func (c *Controller) StartUpload(su *StartUpload) (session models.UploadSession, err error) {
+	if c.Blobs == nil {
+		return session, ErrNoBlobStore
+	}
+	var chunkSize = su.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize < MinChunkSize || chunkSize > MaxChunkSize {
+		return session, fmt.Errorf("%w: chunk size must be between %d and %d bytes", ErrInvalidChunk, MinChunkSize, MaxChunkSize)
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := accessibleActiveFile(tx, su.UserUUID, su.FileUUID, models.RoleEditor)
+		if err != nil {
+			return fmt.Errorf("failed to query file: %w", err)
+		}
+		if file.ArchiveUUID == nil {
+			return ErrIsDirectory
+		}
+		archive, err := tx.GetArchive(*file.ArchiveUUID)
+		if err != nil {
+			return fmt.Errorf("failed to query archive: %w", err)
+		}
+		if archive.IsReady {
+			return ErrArchiveReady
+		}
+		// Archives registered before the hashes were checked can never be stored
+		err = blobstore.CheckHash(archive.Hash)
+		if err != nil {
+			return err
+		}
+
+		session, err = tx.FindUploadSession(su.UserUUID, file.UUID, archive.UUID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to query upload session: %w", err)
+		}
+		var chunkCount = (archive.Size + chunkSize - 1) / chunkSize
+		if chunkCount > MaxChunkCount {
+			return fmt.Errorf("%w: %d chunks required, at most %d allowed", ErrInvalidChunk, chunkCount, MaxChunkCount)
+		}
+		session = models.UploadSession{
+			OwnerUUID:   su.UserUUID,
+			FileUUID:    file.UUID,
+			ArchiveUUID: archive.UUID,
+			ChunkSize:   chunkSize,
+			ChunkCount:  uint(chunkCount),
+		}
+		err = tx.CreateUploadSession(&session)
+		if err != nil {
+			err = fmt.Errorf("failed to create upload session: %w", err)
+		}
+		return err
+	})
+	return session, err
+}
+
+type UploadChunk struct {
+	UserUUID    uuid.UUID `json:"userUUID"`
+	SessionUUID uuid.UUID `json:"sessionUUID"`
+	Number      uint      `json:"number"`
+	Contents    io.Reader `json:"-"`
+}
+
+// Stores a chunk of the upload, chunks are numbered from zero and can be sent in any order.
+// Every chunk must have the chunk size of the session, except for the last one that holds the remaining bytes
+func (c *Controller) UploadChunk(uc *UploadChunk) (chunk models.UploadChunk, err error) {
+	session, err := c.queryUploadSession(uc.UserUUID, uc.SessionUUID)
+	if err != nil {
+		return chunk, err
+	}
+	if uc.Number >= session.ChunkCount {
+		return chunk, fmt.Errorf("%w: session has %d chunks", ErrInvalidChunk, session.ChunkCount)
+	}
+	var size = session.ChunkSize
+	if uc.Number == session.ChunkCount-1 {
+		size = session.Archive.Size - session.ChunkSize*uint64(session.ChunkCount-1)
+	}
+	err = c.Blobs.PutChunk(session.UUID.String(), uc.Number, size, uc.Contents)
+	if err != nil {
+		return chunk, fmt.Errorf("%w: %w", ErrInvalidChunk, err)
+	}
+
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		chunk = models.UploadChunk{
+			SessionUUID: session.UUID,
+			Number:      uc.Number,
+			Size:        size,
+		}
+		err := tx.TouchUploadChunk(&chunk)
+		if err != nil {
+			return fmt.Errorf("failed to register chunk: %w", err)
+		}
+		// Keep the upload from being expired while it makes progress
+		archive, err := tx.GetArchive(session.ArchiveUUID)
+		if err == nil {
+			archive.UpdatedAt = chunk.UpdatedAt
+			err = tx.SaveArchive(&archive)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to refresh archive: %w", err)
+		}
+		return err
+	})
+	return chunk, err
+}
+
+type MissingChunks struct {
+	UserUUID    uuid.UUID `json:"userUUID"`
+	SessionUUID uuid.UUID `json:"sessionUUID"`
+}
+
+// Lists the chunks not uploaded yet, used by the clients to resume an upload
+func (c *Controller) MissingChunks(mc *MissingChunks) (missing []uint, err error) {
+	session, err := c.queryUploadSession(mc.UserUUID, mc.SessionUUID)
+	if err != nil {
+		return missing, err
+	}
+	return missingChunks(c.Store, &session)
+}
+
+type FinalizeUpload struct {
+	UserUUID    uuid.UUID `json:"userUUID"`
+	SessionUUID uuid.UUID `json:"sessionUUID"`
+}
+
+// Assembles the chunks into the blob of the archive and marks it as ready.
+// When the assembled contents don't match the archive, the chunks are discarded and must be uploaded again
+func (c *Controller) FinalizeUpload(fu *FinalizeUpload) (archive models.Archive, err error) {
+	session, err := c.queryUploadSession(fu.UserUUID, fu.SessionUUID)
+	if err != nil {
+		return archive, err
+	}
+	archive = *session.Archive
+
+	if !archive.IsReady {
+		missing, err := missingChunks(c.Store, &session)
+		if err != nil {
+			return archive, err
+		}
+		if len(missing) > 0 {
+			return archive, fmt.Errorf("%w: %v", ErrMissingChunks, missing)
+		}
+
+		r, err := c.Blobs.ReadChunks(session.UUID.String(), session.ChunkCount)
+		if err != nil {
+			return archive, err
+		}
+		err = c.Blobs.Put(archive.Hash, archive.Size, r)
+		r.Close()
+		if err != nil {
+			resetErr := c.Store.DeleteUploadChunks(session.UUID)
+			if resetErr == nil {
+				resetErr = c.Blobs.DeleteChunks(session.UUID.String())
+			}
+			return archive, errors.Join(fmt.Errorf("failed to assemble upload: %w", err), resetErr)
+		}
+
+		var mar = MarkArchiveReady{
+			Hash: archive.Hash,
+			Size: archive.Size,
+		}
+		archive, err = c.MarkArchiveReady(&mar)
+		if err != nil {
+			return archive, err
+		}
+	}
+
+	// Chunks are removed in cascade
+	err = c.Store.DeleteUploadSession(session.UUID)
+	if err != nil {
+		return archive, fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	err = c.Blobs.DeleteChunks(session.UUID.String())
+	return archive, err
+}
+
+func (c *Controller) queryUploadSession(userUUID, sessionUUID uuid.UUID) (session models.UploadSession, err error) {
+	if c.Blobs == nil {
+		return session, ErrNoBlobStore
+	}
+	session, err = c.Store.GetUploadSession(sessionUUID)
+	if err == nil && session.OwnerUUID != userUUID {
+		err = gorm.ErrRecordNotFound
+	}
+	// Editors can't keep uploading once their access is revoked
+	if err == nil {
+		_, err = accessibleFile(c.Store, userUUID, session.FileUUID, models.RoleEditor)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to query upload session: %w", err)
+	}
+	return session, err
+}
+
+func missingChunks(tx store.MetadataStore, session *models.UploadSession) (missing []uint, err error) {
+	uploaded, err := tx.UploadedChunks(session.UUID)
+	if err != nil {
+		return missing, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	var found = make(map[uint]bool, len(uploaded))
+	for _, number := range uploaded {
+		found[number] = true
+	}
+	for number := uint(0); number < session.ChunkCount; number++ {
+		if !found[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing, nil
+}
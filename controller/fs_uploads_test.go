@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func startTestUpload(t *testing.T, c *Controller, contents string, chunkSize uint64) (cf CreateFile, session models.UploadSession) {
+	assertions := assert.New(t)
+
+	cf = CreateFile{
+		Filename:  "upload.txt",
+		OwnerUUID: uuid.New(),
+		Hash:      utils.Hash(contents),
+		Size:      uint64(len(contents)),
+	}
+	file, err := c.CreateFile(&cf)
+	assertions.Nil(err)
+
+	var su = StartUpload{
+		UserUUID:  cf.OwnerUUID,
+		FileUUID:  file.UUID,
+		ChunkSize: chunkSize,
+	}
+	session, err = c.StartUpload(&su)
+	assertions.Nil(err)
+	return cf, session
+}
+
+func TestController_ChunkedUpload(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			size     = int(MinChunkSize)
+			contents = strings.Repeat("0123456789abcdef", size/4) + "-"
+		)
+		cf, upload := startTestUpload(t, c, contents, MinChunkSize)
+		assertions.Equal(uint(5), upload.ChunkCount)
+
+		// Chunks can arrive in any order
+		for _, number := range []uint{4, 2, 0, 3, 1} {
+			var end = min(int(number+1)*size, len(contents))
+			var uc = UploadChunk{
+				UserUUID:    cf.OwnerUUID,
+				SessionUUID: upload.UUID,
+				Number:      number,
+				Contents:    strings.NewReader(contents[int(number)*size : end]),
+			}
+			_, err = c.UploadChunk(&uc)
+			assertions.Nil(err)
+		}
+
+		var fu = FinalizeUpload{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+		}
+		archive, err := c.FinalizeUpload(&fu)
+		assertions.Nil(err)
+		assertions.True(archive.IsReady)
+
+		var qf = QueryFile{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: upload.FileUUID,
+		}
+		_, err = c.QueryFile(&qf)
+		assertions.Nil(err)
+
+		blob, err := c.Blobs.Open(cf.Hash)
+		assertions.Nil(err)
+		defer blob.Close()
+		buf, err := io.ReadAll(blob)
+		assertions.Nil(err)
+		assertions.Equal(contents, string(buf))
+
+		// Session is gone
+		var mc = MissingChunks{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+		}
+		_, err = c.MissingChunks(&mc)
+		assertions.NotNil(err)
+	})
+	t.Run("Resume", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			size     = int(MinChunkSize)
+			contents = strings.Repeat("0123456789", size/4)
+		)
+		cf, upload := startTestUpload(t, c, contents, MinChunkSize)
+
+		var uc = UploadChunk{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+			Number:      1,
+			Contents:    strings.NewReader(contents[size : 2*size]),
+		}
+		_, err = c.UploadChunk(&uc)
+		assertions.Nil(err)
+
+		var su = StartUpload{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: upload.FileUUID,
+		}
+		resumed, err := c.StartUpload(&su)
+		assertions.Nil(err)
+		assertions.Equal(upload.UUID, resumed.UUID)
+		assertions.Equal(MinChunkSize, resumed.ChunkSize)
+
+		var mc = MissingChunks{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: resumed.UUID,
+		}
+		missing, err := c.MissingChunks(&mc)
+		assertions.Nil(err)
+		assertions.Equal([]uint{0, 2}, missing)
+
+		var fu = FinalizeUpload{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: resumed.UUID,
+		}
+		_, err = c.FinalizeUpload(&fu)
+		assertions.ErrorIs(err, ErrMissingChunks)
+	})
+	t.Run("Invalid chunk", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			size     = int(MinChunkSize)
+			contents = strings.Repeat("0123456789", size/4)
+		)
+		cf, upload := startTestUpload(t, c, contents, MinChunkSize)
+
+		var uc = UploadChunk{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+			Number:      3,
+			Contents:    strings.NewReader(contents[2*size:]),
+		}
+		_, err = c.UploadChunk(&uc)
+		assertions.ErrorIs(err, ErrInvalidChunk)
+
+		// Last chunk only holds the remaining bytes
+		uc.Number = 2
+		uc.Contents = strings.NewReader(contents[size : 2*size])
+		_, err = c.UploadChunk(&uc)
+		assertions.ErrorIs(err, blobstore.ErrSizeMismatch)
+
+		// Other users can't write into the session
+		uc.UserUUID = uuid.New()
+		uc.Contents = strings.NewReader(contents[2*size:])
+		_, err = c.UploadChunk(&uc)
+		assertions.NotNil(err)
+	})
+	t.Run("Hash mismatch", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			size     = int(MinChunkSize)
+			contents = strings.Repeat("01", size)
+		)
+		cf, upload := startTestUpload(t, c, contents, MinChunkSize)
+
+		for number, chunk := range []string{contents[:size], strings.Repeat("X", size)} {
+			var uc = UploadChunk{
+				UserUUID:    cf.OwnerUUID,
+				SessionUUID: upload.UUID,
+				Number:      uint(number),
+				Contents:    strings.NewReader(chunk),
+			}
+			_, err = c.UploadChunk(&uc)
+			assertions.Nil(err)
+		}
+
+		var fu = FinalizeUpload{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+		}
+		_, err = c.FinalizeUpload(&fu)
+		assertions.ErrorIs(err, blobstore.ErrHashMismatch)
+
+		// Chunks must be uploaded again
+		var mc = MissingChunks{
+			UserUUID:    cf.OwnerUUID,
+			SessionUUID: upload.UUID,
+		}
+		missing, err := c.MissingChunks(&mc)
+		assertions.Nil(err)
+		assertions.Equal([]uint{0, 1}, missing)
+	})
+	t.Run("Already ready", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var (
+			contents = uuid.NewString()
+			cf       = CreateFile{
+				Filename:  "upload.txt",
+				OwnerUUID: uuid.New(),
+				Hash:      utils.Hash(contents),
+				Size:      uint64(len(contents)),
+			}
+		)
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var ua = UploadArchive{
+			Hash:     cf.Hash,
+			Size:     cf.Size,
+			Contents: strings.NewReader(contents),
+		}
+		_, err = c.UploadArchive(&ua)
+		assertions.Nil(err)
+
+		var su = StartUpload{
+			UserUUID: cf.OwnerUUID,
+			FileUUID: file.UUID,
+		}
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, ErrArchiveReady)
+	})
+	t.Run("Chunk limits", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		var cf = CreateFile{
+			Filename:  "upload.txt",
+			OwnerUUID: uuid.New(),
+			Hash:      utils.Hash(uuid.NewString()),
+			Size:      MaxChunkCount*MinChunkSize + 1,
+		}
+		file, err := c.CreateFile(&cf)
+		assertions.Nil(err)
+
+		var su = StartUpload{
+			UserUUID:  cf.OwnerUUID,
+			FileUUID:  file.UUID,
+			ChunkSize: MinChunkSize - 1,
+		}
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, ErrInvalidChunk)
+		su.ChunkSize = MaxChunkSize + 1
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, ErrInvalidChunk)
+
+		// Too many chunks for the size of the archive
+		su.ChunkSize = MinChunkSize
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, ErrInvalidChunk)
+
+		su.ChunkSize = MaxChunkSize
+		session, err := c.StartUpload(&su)
+		assertions.Nil(err)
+		assertions.Equal(uint((cf.Size+MaxChunkSize-1)/MaxChunkSize), session.ChunkCount)
+	})
+	t.Run("Invalid hash", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		c.Blobs, err = blobstore.New(t.TempDir())
+		assertions.Nil(err)
+
+		// Registered before the hashes were checked
+		archive, err := c.Store.TouchArchive("NOT-A-HASH", 5)
+		assertions.Nil(err)
+		var file = models.File{
+			OwnerUUID:   uuid.New(),
+			Name:        "upload.txt",
+			ArchiveUUID: &archive.UUID,
+		}
+		assertions.Nil(c.Store.CreateFile(&file))
+
+		var su = StartUpload{
+			UserUUID: file.OwnerUUID,
+			FileUUID: file.UUID,
+		}
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, blobstore.ErrInvalidHash)
+	})
+	t.Run("Without blob store", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var su = StartUpload{
+			UserUUID: uuid.New(),
+			FileUUID: uuid.New(),
+		}
+		_, err = c.StartUpload(&su)
+		assertions.ErrorIs(err, ErrNoBlobStore)
+	})
+}
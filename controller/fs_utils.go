@@ -3,9 +3,14 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
 	"gorm.io/gorm"
 )
 
@@ -16,75 +21,234 @@ type CanReadFile struct {
 
 // Can read file is inteded to be used internally by other operations of the metadata
 // Will check if user owns the file
-// Or iif user has at least access by share directly or indirectly
+// Or iif user has at least access by share directly or indirectly.
+// Shared access is lost while the file or any of its parents is in the trash
 func (c *Controller) CanReadFile(crf *CanReadFile) (err error) {
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		var file models.File
-		// Check if user is owner
-		err := tx.
-			Where("uuid = ? AND owner_uuid = ?", crf.FileUUID, crf.UserUUID).
-			First(&file).
-			Error
-		if err == nil {
-			return err
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		_, err := accessibleFile(tx, crf.UserUUID, crf.FileUUID, models.RoleViewer)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrPermissionDenied
 		}
+		return err
+	})
+	return err
+}
+
+// Lists the shares of the user still active at the given time, filtered by the other fields of the listing.
+// Includes the ones granted to the groups the user belongs to, which come with the group set.
+// Both kinds of shares are merged in order of creation, so a limit applies to all of them together
+func sharesOf(tx store.MetadataStore, userUUID uuid.UUID, now time.Time, ls store.ListShares) (shares []models.SharedFile, err error) {
+	ls.UserUUID = &userUUID
+	ls.ActiveAt = &now
+	shares, err = tx.ListShares(&ls)
+	if err != nil {
+		return shares, err
+	}
+	groups, err := tx.ListGroups(&store.ListGroups{MemberUUID: &userUUID})
+	if err != nil || len(groups) == 0 {
+		return shares, err
+	}
+	var groupUUIDs = make([]uuid.UUID, 0, len(groups))
+	for _, group := range groups {
+		groupUUIDs = append(groupUUIDs, group.UUID)
+	}
+	grants, err := tx.ListGroupShares(&store.ListGroupShares{
+		GroupUUIDs:  groupUUIDs,
+		FileUUIDs:   ls.FileUUIDs,
+		Name:        ls.Name,
+		OwnerUUID:   ls.OwnerUUID,
+		SkipTrashed: ls.SkipTrashed,
+		ActiveAt:    ls.ActiveAt,
+		Descending:  ls.Descending,
+		After:       ls.After,
+		Limit:       ls.Limit,
+	})
+	if err != nil || len(grants) == 0 {
+		return shares, err
+	}
+	for _, grant := range grants {
+		shares = append(shares, groupShareOf(grant, userUUID))
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return sharedBefore(shares[i].Model, shares[j].Model, ls.Descending)
+	})
+	if ls.Limit > 0 && len(shares) > ls.Limit {
+		shares = shares[:ls.Limit]
+	}
+	return shares, nil
+}
+
+// Entry of the group share for one of its members
+func groupShareOf(grant models.GroupShare, userUUID uuid.UUID) models.SharedFile {
+	var groupUUID = grant.GroupUUID
+	return models.SharedFile{
+		Model:      grant.Model,
+		UserUUID:   userUUID,
+		FileUUID:   grant.FileUUID,
+		Role:       grant.Role,
+		SharerUUID: grant.SharerUUID,
+		ExpiresAt:  grant.ExpiresAt,
+		GroupUUID:  &groupUUID,
+	}
+}
+
+// Role of the user over the file, owners get models.RoleOwner.
+// Shared users get the highest role granted to them or their groups on the file or any of its parents,
+// as long as none of them is in the trash. Expired shares are ignored.
+// Files the user has no access to are reported as not found
+func fileRole(tx store.MetadataStore, userUUID, fileUUID uuid.UUID) (file models.File, role models.ShareRole, err error) {
+	hierarchy, err := tx.Ancestors(fileUUID)
+	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			err = fmt.Errorf("failed to query file information: %w", err)
-			return err
 		}
-		var sf models.SharedFile
-		// Check direct access
-		err = c.DB.
-			Where("file_uuid = ? AND user_uuid = ?", crf.FileUUID, crf.UserUUID).
-			First(&sf).
-			Error
-		if err == nil {
-			return err
+		return file, role, err
+	}
+	file = hierarchy[0]
+	if file.OwnerUUID == userUUID {
+		return file, models.RoleOwner, nil
+	}
+	var fileUUIDs = make([]uuid.UUID, 0, len(hierarchy))
+	for _, ancestor := range hierarchy {
+		// None of the files in the hierarchy can be in the trash
+		if ancestor.TrashedAt != nil {
+			return file, role, gorm.ErrRecordNotFound
 		}
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			err = fmt.Errorf("failed to query shared files information: %w", err)
-			return err
+		fileUUIDs = append(fileUUIDs, ancestor.UUID)
+	}
+	// Check if any of the files in the hierarchy are shared with the given user or its groups
+	shares, err := sharesOf(tx, userUUID, time.Now(), store.ListShares{FileUUIDs: fileUUIDs})
+	if err != nil {
+		return file, role, err
+	}
+	role = hierarchyRole(hierarchy, grantedRoles(shares))
+	if role == "" {
+		err = gorm.ErrRecordNotFound
+	}
+	return file, role, err
+}
+
+// Same as fileRole for several files at once, with a fixed number of queries.
+// Files the user has no access to are left out
+func fileRoles(tx store.MetadataStore, userUUID uuid.UUID, fileUUIDs []uuid.UUID) (roles map[uuid.UUID]models.ShareRole, err error) {
+	hierarchies, err := tx.ListAncestors(fileUUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file information: %w", err)
+	}
+	roles = make(map[uuid.UUID]models.ShareRole, len(hierarchies))
+	var ancestors []uuid.UUID
+	for fileUUID, hierarchy := range hierarchies {
+		if hierarchy[0].OwnerUUID == userUUID {
+			roles[fileUUID] = models.RoleOwner
+			continue
 		}
-		// Check nested
-		var found struct {
-			Found bool `gorm:"column:found"`
+		for _, ancestor := range hierarchy {
+			ancestors = append(ancestors, ancestor.UUID)
 		}
-		fmt.Println(crf.FileUUID, crf.UserUUID)
-		err = c.DB.Raw(
-			`WITH RECURSIVE file_hierarchy AS (
-				-- Base case: start with the initial file UUID
-				SELECT uuid, parent_uuid
-				FROM files
-				WHERE uuid = $1
-			
-				UNION ALL
-			
-				-- Recursive case: get the parent file of the current file
-				SELECT f.uuid, f.parent_uuid
-				FROM files f
-				JOIN file_hierarchy fh ON f.uuid = fh.parent_uuid
-			)
-			
-			-- Check if any of the files in the hierarchy are shared with the given user
-			SELECT CASE 
-					   WHEN EXISTS (
-						   SELECT 1 
-						   FROM shared_files sf
-						   JOIN file_hierarchy fh ON sf.file_uuid = fh.uuid
-						   WHERE sf.user_uuid = $2
-					   ) THEN TRUE
-					   ELSE FALSE
-				   END AS found;
-			`, crf.FileUUID, crf.UserUUID).
-			Scan(&found).
-			Error
-		if err != nil {
-			return err
+	}
+	if len(ancestors) == 0 {
+		return roles, nil
+	}
+	shares, err := sharesOf(tx, userUUID, time.Now(), store.ListShares{FileUUIDs: ancestors})
+	if err != nil {
+		return nil, err
+	}
+	var granted = grantedRoles(shares)
+	for fileUUID, hierarchy := range hierarchies {
+		if _, owned := roles[fileUUID]; owned {
+			continue
 		}
-		if !found.Found {
-			err = fmt.Errorf("permission denied")
+		if role := hierarchyRole(hierarchy, granted); role != "" {
+			roles[fileUUID] = role
 		}
-		return err
-	})
-	return err
+	}
+	return roles, nil
+}
+
+// Highest role of the shares of every file
+func grantedRoles(shares []models.SharedFile) (granted map[uuid.UUID]models.ShareRole) {
+	granted = make(map[uuid.UUID]models.ShareRole, len(shares))
+	for _, share := range shares {
+		if role := granted[share.FileUUID]; !role.Includes(share.Role) {
+			granted[share.FileUUID] = share.Role
+		}
+	}
+	return granted
+}
+
+// Highest role granted on any file of the hierarchy, empty when none is or any of them is in the trash
+func hierarchyRole(hierarchy []models.File, granted map[uuid.UUID]models.ShareRole) (role models.ShareRole) {
+	for _, ancestor := range hierarchy {
+		if ancestor.TrashedAt != nil {
+			return ""
+		}
+		if !role.Includes(granted[ancestor.UUID]) {
+			role = granted[ancestor.UUID]
+		}
+	}
+	return role
+}
+
+// Queries a file the user has at least the given role over
+func accessibleFile(tx store.MetadataStore, userUUID, fileUUID uuid.UUID, minimum models.ShareRole) (file models.File, err error) {
+	file, role, err := fileRole(tx, userUUID, fileUUID)
+	if err == nil && !role.Includes(minimum) {
+		err = fmt.Errorf("%w: requires %s role", ErrPermissionDenied, minimum)
+	}
+	return file, err
+}
+
+// Same as accessibleFile but files in the trash, or inside a directory in the trash, are reported as not found
+func accessibleActiveFile(tx store.MetadataStore, userUUID, fileUUID uuid.UUID, minimum models.ShareRole) (file models.File, err error) {
+	file, err = accessibleFile(tx, userUUID, fileUUID, minimum)
+	if err != nil {
+		return file, err
+	}
+	trashed, err := isTrashed(tx, file.UUID)
+	if err == nil && trashed {
+		err = gorm.ErrRecordNotFound
+	}
+	return file, err
+}
+
+// Queries a file owned by the user, files of other users are reported as not found
+func ownedFile(tx store.MetadataStore, ownerUUID, fileUUID uuid.UUID) (file models.File, err error) {
+	file, err = tx.GetFile(fileUUID)
+	if err == nil && file.OwnerUUID != ownerUUID {
+		err = gorm.ErrRecordNotFound
+	}
+	return file, err
+}
+
+// Same as ownedFile but files in the trash are reported as not found too
+func ownedActiveFile(tx store.MetadataStore, ownerUUID, fileUUID uuid.UUID) (file models.File, err error) {
+	file, err = ownedFile(tx, ownerUUID, fileUUID)
+	if err == nil && file.TrashedAt != nil {
+		err = gorm.ErrRecordNotFound
+	}
+	return file, err
+}
+
+// Finds a name that is not used by the other files of the directory,
+// appending a counter like "notes (1).txt" when the name is already taken
+func availableName(tx store.MetadataStore, p *placement) (available string, err error) {
+	var (
+		base = p.Name
+		ext  string
+	)
+	if !p.IsDirectory {
+		ext = path.Ext(p.Name)
+		base = strings.TrimSuffix(p.Name, ext)
+	}
+	available = p.Name
+	for counter := 1; ; counter++ {
+		existing, err := tx.FindChild(p.OwnerUUID, p.ParentUUID, available)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && p.File != nil && existing.UUID == p.File.UUID) {
+			return available, nil
+		}
+		if err != nil {
+			return available, err
+		}
+		available = fmt.Sprintf("%s (%d)%s", base, counter, ext)
+	}
 }
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/store"
+	"gorm.io/gorm"
+)
+
+type UpdateContent struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+	Hash     string    `json:"hash"`
+	Size     uint64    `json:"size"`
+}
+
+// Replaces the contents of a file, the previous contents are kept in the version history.
+// Editors of shared files can replace them too, the new version is authored by them.
+// Fails with ErrQuotaExceeded when the owner has no room for the growth of the file,
+// and with blobstore.ErrInvalidHash when the contents have a hash the blob store can't hold
+func (c *Controller) UpdateContent(uc *UpdateContent) (version models.FileVersion, err error) {
+	if uc.Size == 0 {
+		return version, fmt.Errorf("content size must be greater than zero")
+	}
+	err = blobstore.CheckHash(uc.Hash)
+	if err != nil {
+		return version, err
+	}
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := queryVersionedFile(tx, uc.UserUUID, uc.FileUUID, models.RoleEditor)
+		if err != nil {
+			return err
+		}
+		err = c.reserveContents(tx, &file, uc.Size)
+		if err != nil {
+			return err
+		}
+		archive, err := tx.TouchArchive(uc.Hash, uc.Size)
+		if err != nil {
+			return fmt.Errorf("failed to register archive: %w", err)
+		}
+		version, err = setCurrentArchive(tx, &file, archive.UUID, uc.UserUUID)
+		return err
+	})
+	return version, err
+}
+
+type ListVersions struct {
+	UserUUID uuid.UUID `json:"userUUID"`
+	FileUUID uuid.UUID `json:"fileUUID"`
+}
+
+// Lists the version history of a file, newest first
+func (c *Controller) ListVersions(lv *ListVersions) (versions []models.FileVersion, err error) {
+	var crf = CanReadFile{
+		UserUUID: lv.UserUUID,
+		FileUUID: lv.FileUUID,
+	}
+	err = c.CanReadFile(&crf)
+	if err != nil {
+		return versions, err
+	}
+	versions, err = c.Store.ListVersions(lv.FileUUID)
+	if err != nil {
+		err = fmt.Errorf("failed to list versions: %w", err)
+	}
+	return versions, err
+}
+
+type RestoreVersion struct {
+	UserUUID    uuid.UUID `json:"userUUID"`
+	FileUUID    uuid.UUID `json:"fileUUID"`
+	VersionUUID uuid.UUID `json:"versionUUID"`
+}
+
+// Makes the contents of an old version the current ones.
+// The history is never rewritten, restoring appends a new version.
+// Like UpdateContent, editors can restore versions and the owner must have room for the growth of the file
+func (c *Controller) RestoreVersion(rv *RestoreVersion) (version models.FileVersion, err error) {
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := queryVersionedFile(tx, rv.UserUUID, rv.FileUUID, models.RoleEditor)
+		if err != nil {
+			return err
+		}
+		old, err := tx.GetVersion(rv.VersionUUID)
+		if err == nil && old.FileUUID != file.UUID {
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query version: %w", err)
+		}
+		archive, err := tx.GetArchive(old.ArchiveUUID)
+		if err != nil {
+			return fmt.Errorf("failed to query version contents: %w", err)
+		}
+		err = c.reserveContents(tx, &file, archive.Size)
+		if err != nil {
+			return err
+		}
+		version, err = setCurrentArchive(tx, &file, old.ArchiveUUID, rv.UserUUID)
+		return err
+	})
+	return version, err
+}
+
+type PruneVersions struct {
+	UserUUID  uuid.UUID     `json:"userUUID"`
+	FileUUID  uuid.UUID     `json:"fileUUID"`
+	KeepLast  int           `json:"keepLast,omitempty"`
+	OlderThan time.Duration `json:"olderThan,omitempty"`
+}
+
+// Removes old versions of a file. Versions are removed when they are not between the KeepLast newest,
+// or when they were created more than OlderThan ago. Zero values disable the respective rule.
+// The current version is always kept, only the owner can remove versions
+func (c *Controller) PruneVersions(pv *PruneVersions) (pruned []models.FileVersion, err error) {
+	var cutoff = time.Now().Add(-pv.OlderThan)
+	err = c.Store.Transaction(func(tx store.MetadataStore) error {
+		file, err := queryVersionedFile(tx, pv.UserUUID, pv.FileUUID, models.RoleOwner)
+		if err != nil {
+			return err
+		}
+		versions, err := tx.ListVersions(file.UUID)
+		if err != nil {
+			return fmt.Errorf("failed to query versions: %w", err)
+		}
+		var uuids []uuid.UUID
+		for index, version := range versions {
+			if index == 0 {
+				continue
+			}
+			if (pv.KeepLast > 0 && index >= pv.KeepLast) || (pv.OlderThan > 0 && version.Timestamp.Before(cutoff)) {
+				pruned = append(pruned, version)
+				uuids = append(uuids, version.UUID)
+			}
+		}
+		if len(uuids) == 0 {
+			return nil
+		}
+		err = tx.DeleteVersions(uuids...)
+		if err != nil {
+			err = fmt.Errorf("failed to delete versions: %w", err)
+		}
+		return err
+	})
+	return pruned, err
+}
+
+// Queries a file whose versions the user can manage with at least the given role
+func queryVersionedFile(tx store.MetadataStore, userUUID, fileUUID uuid.UUID, minimum models.ShareRole) (file models.File, err error) {
+	file, err = accessibleActiveFile(tx, userUUID, fileUUID, minimum)
+	if err != nil {
+		return file, fmt.Errorf("failed to query file: %w", err)
+	}
+	if file.ArchiveUUID == nil {
+		return file, ErrIsDirectory
+	}
+	return file, nil
+}
+
+// Points the file to a new archive and records it in the history.
+// Files indexed before versioning existed get their current contents recorded first
+func setCurrentArchive(tx store.MetadataStore, file *models.File, archiveUUID, authorUUID uuid.UUID) (version models.FileVersion, err error) {
+	versions, err := tx.ListVersions(file.UUID)
+	if err != nil {
+		return version, fmt.Errorf("failed to query versions: %w", err)
+	}
+	if len(versions) == 0 {
+		err = tx.CreateVersion(&models.FileVersion{
+			FileUUID:    file.UUID,
+			Number:      1,
+			ArchiveUUID: *file.ArchiveUUID,
+			AuthorUUID:  file.OwnerUUID,
+			Timestamp:   file.UpdatedAt,
+		})
+		if err != nil {
+			return version, fmt.Errorf("failed to record previous version: %w", err)
+		}
+	}
+	file.ArchiveUUID = &archiveUUID
+	err = tx.SaveFile(file)
+	if err != nil {
+		return version, fmt.Errorf("failed to update file contents: %w", err)
+	}
+	return createVersion(tx, file, authorUUID)
+}
+
+// Appends the current archive of the file to its history
+func createVersion(tx store.MetadataStore, file *models.File, authorUUID uuid.UUID) (version models.FileVersion, err error) {
+	versions, err := tx.ListVersions(file.UUID)
+	if err != nil {
+		return version, fmt.Errorf("failed to query last version: %w", err)
+	}
+	var last uint
+	if len(versions) > 0 {
+		last = versions[0].Number
+	}
+	version = models.FileVersion{
+		FileUUID:    file.UUID,
+		Number:      last + 1,
+		ArchiveUUID: *file.ArchiveUUID,
+		AuthorUUID:  authorUUID,
+		Timestamp:   time.Now(),
+	}
+	err = tx.CreateVersion(&version)
+	if err != nil {
+		err = fmt.Errorf("failed to create version: %w", err)
+	}
+	return version, err
+}
@@ -0,0 +1,422 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Creates a file and updates its contents with every revision
+func createVersionedFile(t *testing.T, c *Controller, owner uuid.UUID, revisions ...string) (file models.File) {
+	assertions := assert.New(t)
+
+	var (
+		contents = "version 1"
+		cf       = CreateFile{
+			Filename:  "versioned.txt",
+			OwnerUUID: owner,
+			Hash:      utils.Hash(contents),
+			Size:      uint64(len(contents)),
+		}
+	)
+	file, err := c.CreateFile(&cf)
+	assertions.Nil(err)
+	markArchiveReady(t, c, cf.Hash, cf.Size)
+
+	for _, revision := range revisions {
+		var uc = UpdateContent{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+			Hash:     utils.Hash(revision),
+			Size:     uint64(len(revision)),
+		}
+		_, err = c.UpdateContent(&uc)
+		assertions.Nil(err)
+		markArchiveReady(t, c, uc.Hash, uc.Size)
+	}
+	return file
+}
+
+func TestController_UpdateContent(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner)
+		)
+		var (
+			contents = "version 2"
+			uc       = UpdateContent{
+				UserUUID: owner,
+				FileUUID: file.UUID,
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+			}
+		)
+		version, err := c.UpdateContent(&uc)
+		assertions.Nil(err)
+		markArchiveReady(t, c, uc.Hash, uc.Size)
+
+		assertions.Equal(uint(2), version.Number)
+		assertions.Equal(owner, version.AuthorUUID)
+
+		var qf = QueryFile{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.Nil(err)
+
+		assertions.Equal(uc.Hash, archive.Hash)
+	})
+	t.Run("Not owned file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			file     = createVersionedFile(t, c, uuid.New())
+			contents = "version 2"
+			uc       = UpdateContent{
+				UserUUID: uuid.New(),
+				FileUUID: file.UUID,
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+			}
+		)
+		_, err = c.UpdateContent(&uc)
+		assertions.NotNil(err)
+	})
+	t.Run("Directory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			root  = createTestTree(t, c, owner)
+		)
+		var (
+			contents = "version 2"
+			uc       = UpdateContent{
+				UserUUID: owner,
+				FileUUID: root.UUID,
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+			}
+		)
+		_, err = c.UpdateContent(&uc)
+		assertions.ErrorIs(err, ErrIsDirectory)
+	})
+	t.Run("Invalid hash", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner)
+			uc    = UpdateContent{
+				UserUUID: owner,
+				FileUUID: file.UUID,
+				Hash:     "NOT-A-HASH",
+				Size:     5,
+			}
+		)
+		_, err = c.UpdateContent(&uc)
+		assertions.ErrorIs(err, blobstore.ErrInvalidHash)
+
+		versions, err := c.ListVersions(&ListVersions{UserUUID: owner, FileUUID: file.UUID})
+		assertions.Nil(err)
+		assertions.Len(versions, 1)
+	})
+	t.Run("Editor", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		dir, file, editor := createSharedDirectory(t, c, models.RoleEditor)
+		var (
+			contents = "fmt.Println(`bye`)"
+			uc       = UpdateContent{
+				UserUUID: editor,
+				FileUUID: file.UUID,
+				Hash:     utils.Hash(contents),
+				Size:     uint64(len(contents)),
+			}
+		)
+		version, err := c.UpdateContent(&uc)
+		assertions.Nil(err)
+		assertions.Equal(editor, version.AuthorUUID)
+
+		// The growth is charged to the owner
+		var limit = uint64(len(contents))
+		_, err = c.SetQuota(&SetQuota{UserUUID: dir.OwnerUUID, MaxBytes: &limit})
+		assertions.Nil(err)
+		uc.Hash = utils.Hash(contents + "!")
+		uc.Size++
+		_, err = c.UpdateContent(&uc)
+		assertions.ErrorIs(err, ErrQuotaExceeded)
+
+		var viewer = uuid.New()
+		assertions.Nil(c.ShareFile(&ShareRequest{
+			OwnerUUID:      dir.OwnerUUID,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: viewer,
+		}))
+		uc.UserUUID = viewer
+		_, err = c.UpdateContent(&uc)
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+}
+
+func TestController_ListVersions(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner, "version 2", "version 3")
+		)
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+
+		assertions.Len(versions, 3)
+		assertions.Equal(uint(3), versions[0].Number)
+		assertions.Equal(utils.Hash("version 3"), versions[0].Archive.Hash)
+		assertions.Equal(uint(1), versions[2].Number)
+		assertions.Equal(utils.Hash("version 1"), versions[2].Archive.Hash)
+
+		// Query old version
+		var qf = QueryFile{
+			UserUUID:    owner,
+			FileUUID:    file.UUID,
+			VersionUUID: &versions[1].UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.Nil(err)
+
+		assertions.Equal(utils.Hash("version 2"), archive.Hash)
+	})
+	t.Run("Version of other file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner)
+			other = createVersionedFile(t, c, uuid.New())
+		)
+		var lv = ListVersions{
+			UserUUID: other.OwnerUUID,
+			FileUUID: other.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+		assertions.Len(versions, 1)
+
+		var qf = QueryFile{
+			UserUUID:    owner,
+			FileUUID:    file.UUID,
+			VersionUUID: &versions[0].UUID,
+		}
+		_, err = c.QueryFile(&qf)
+		assertions.NotNil(err)
+	})
+	t.Run("Zero Access", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var file = createVersionedFile(t, c, uuid.New(), "version 2")
+		var lv = ListVersions{
+			UserUUID: uuid.New(),
+			FileUUID: file.UUID,
+		}
+		_, err = c.ListVersions(&lv)
+		assertions.NotNil(err)
+	})
+}
+
+func TestController_RestoreVersion(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner, "version 2")
+		)
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+
+		var rv = RestoreVersion{
+			UserUUID:    owner,
+			FileUUID:    file.UUID,
+			VersionUUID: versions[1].UUID,
+		}
+		version, err := c.RestoreVersion(&rv)
+		assertions.Nil(err)
+
+		assertions.Equal(uint(3), version.Number)
+		assertions.Equal(versions[1].ArchiveUUID, version.ArchiveUUID)
+
+		var qf = QueryFile{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		archive, err := c.QueryFile(&qf)
+		assertions.Nil(err)
+
+		assertions.Equal(utils.Hash("version 1"), archive.Hash)
+	})
+	t.Run("Editor", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner  = uuid.New()
+			file   = createVersionedFile(t, c, owner, "version 2")
+			editor = uuid.New()
+		)
+		assertions.Nil(c.ShareFile(&ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       file.UUID,
+			TargetUserUUID: editor,
+			Role:           models.RoleEditor,
+		}))
+		versions, err := c.ListVersions(&ListVersions{UserUUID: editor, FileUUID: file.UUID})
+		assertions.Nil(err)
+
+		version, err := c.RestoreVersion(&RestoreVersion{
+			UserUUID:    editor,
+			FileUUID:    file.UUID,
+			VersionUUID: versions[1].UUID,
+		})
+		assertions.Nil(err)
+		assertions.Equal(editor, version.AuthorUUID)
+		assertions.Equal(versions[1].ArchiveUUID, version.ArchiveUUID)
+
+		// Pruning is still reserved to the owner
+		_, err = c.PruneVersions(&PruneVersions{UserUUID: editor, FileUUID: file.UUID, KeepLast: 1})
+		assertions.ErrorIs(err, ErrPermissionDenied)
+	})
+}
+
+func TestController_PruneVersions(t *testing.T) {
+	t.Run("Keep last", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner, "version 2", "version 3", "version 4")
+		)
+		var pv = PruneVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+			KeepLast: 2,
+		}
+		pruned, err := c.PruneVersions(&pv)
+		assertions.Nil(err)
+		assertions.Len(pruned, 2)
+
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+
+		assertions.Len(versions, 2)
+		assertions.Equal(uint(4), versions[0].Number)
+		assertions.Equal(uint(3), versions[1].Number)
+	})
+	t.Run("Older than", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		c, err := Default()
+		assertions.Nil(err)
+		defer c.Close()
+
+		var (
+			owner = uuid.New()
+			file  = createVersionedFile(t, c, owner, "version 2", "version 3")
+		)
+
+		// Age every version, the current one must survive anyway
+		aged, err := c.Store.ListVersions(file.UUID)
+		assertions.Nil(err)
+		for _, version := range aged {
+			err = c.Store.DeleteVersions(version.UUID)
+			assertions.Nil(err)
+			version.Archive = nil
+			version.Timestamp = time.Now().Add(-48 * time.Hour)
+			err = c.Store.CreateVersion(&version)
+			assertions.Nil(err)
+		}
+
+		var pv = PruneVersions{
+			UserUUID:  owner,
+			FileUUID:  file.UUID,
+			OlderThan: 24 * time.Hour,
+		}
+		pruned, err := c.PruneVersions(&pv)
+		assertions.Nil(err)
+		assertions.Len(pruned, 2)
+
+		var lv = ListVersions{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		}
+		versions, err := c.ListVersions(&lv)
+		assertions.Nil(err)
+
+		assertions.Len(versions, 1)
+		assertions.Equal(uint(3), versions[0].Number)
+	})
+}
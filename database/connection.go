@@ -1,17 +1,112 @@
 package database
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hawks-atlanta/fs-prototype/config"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
+// DSNs starting with this prefix select the SQLite backend, the rest is the database file,
+// like sqlite:index.db, sqlite:file:index.db?cache=private or sqlite::memory:
+const SQLitePrefix = "sqlite:"
+
+const sqliteMemory = ":memory:"
+
+var logLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// Reports if the DSN selects the SQLite backend
+func IsSQLite(dsn string) bool {
+	return strings.HasPrefix(dsn, SQLitePrefix)
+}
+
+// In-memory databases only live as long as their connection
+func isMemory(dsn string) bool {
+	return IsSQLite(dsn) && strings.Contains(dsn, sqliteMemory)
+}
+
+// Foreign keys are required by the cascades of the models and SQLite disables them by default.
+// Waiting for locks avoids failing when several connections of the pool write at the same time
+func sqliteDSN(dsn string) string {
+	var params = url.Values{}
+	params.Set("_foreign_keys", "1")
+	params.Set("_busy_timeout", "5000")
+	if !strings.Contains(dsn, sqliteMemory) {
+		params.Set("_journal_mode", "WAL")
+	}
+	var separator = "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + params.Encode()
+}
+
+func open(dsn string, config *gorm.Config) (db *gorm.DB, err error) {
+	if !IsSQLite(dsn) {
+		return gorm.Open(postgres.Open(dsn), config)
+	}
+	path := strings.TrimPrefix(dsn, SQLitePrefix)
+	db, err = gorm.Open(sqlite.Open(sqliteDSN(path)), config)
+	if err == nil && isMemory(dsn) {
+		// Every connection would get its own database, keep a single one open forever
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
+	return db, err
+}
+
+// Opens a Postgres database, or a SQLite one when the DSN starts with SQLitePrefix
 func New(dsn string) (db *gorm.DB, err error) {
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err = open(dsn, &gorm.Config{TranslateError: true})
 	return db, err
 }
 
+// Opens the database described by the configuration and applies its pool settings
+func FromConfig(cfg *config.Config) (db *gorm.DB, err error) {
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	db, err = open(cfg.Database.DSN, &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logLevels[cfg.Log.Level]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if isMemory(cfg.Database.DSN) {
+		return db, nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	return db, nil
+}
+
+// Opens the database of the configuration returned by config.Load
 func Default() (db *gorm.DB, err error) {
-	const dsn = "host=127.0.0.1 user=sulcud password=sulcud dbname=sulcud port=5432 sslmode=disable"
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	cfg, err := config.Load()
+	if err == nil {
+		db, err = FromConfig(&cfg)
+	}
 	return db, err
 }
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("SQLite file", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		db, err := New(SQLitePrefix + filepath.Join(t.TempDir(), "index.db"))
+		assertions.Nil(err)
+		conn, _ := db.DB()
+		defer conn.Close()
+
+		var foreignKeys int
+		err = db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error
+		assertions.Nil(err)
+		assertions.Equal(1, foreignKeys)
+	})
+	t.Run("SQLite memory", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		db, err := New(SQLitePrefix + ":memory:")
+		assertions.Nil(err)
+		conn, _ := db.DB()
+		defer conn.Close()
+
+		assertions.Nil(db.Exec("CREATE TABLE items (name TEXT)").Error)
+		assertions.Nil(db.Exec("INSERT INTO items VALUES ('a')").Error)
+		var count int64
+		assertions.Nil(db.Table("items").Count(&count).Error)
+		assertions.Equal(int64(1), count)
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		cfg := config.Default()
+		cfg.Database.DSN = ""
+		_, err := FromConfig(&cfg)
+		assertions.ErrorIs(err, config.ErrInvalidConfig)
+	})
+	t.Run("Pool settings", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		cfg := config.Default()
+		cfg.Database.DSN = SQLitePrefix + filepath.Join(t.TempDir(), "index.db")
+		cfg.Database.MaxOpenConns = 3
+		db, err := FromConfig(&cfg)
+		assertions.Nil(err)
+		conn, _ := db.DB()
+		defer conn.Close()
+		assertions.Equal(3, conn.Stats().MaxOpenConnections)
+	})
+}
@@ -1,6 +1,10 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type File struct {
 	Model
@@ -10,4 +14,9 @@ type File struct {
 	Archive     *Archive   `json:"archive,omitempty" gorm:"foreignKey:ArchiveUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	ArchiveUUID *uuid.UUID `json:"archiveUUID,omitempty"`
 	Name        string     `json:"name" gorm:"uniqueIndex:idx_unique_file;not null;"`
+	// Set when an editor of a shared directory created the file on behalf of the owner
+	CreatorUUID *uuid.UUID `json:"creatorUUID,omitempty"`
+	// Trashed files are detached from their parent until restored or purged
+	TrashedAt       *time.Time `json:"trashedAt,omitempty" gorm:"index;"`
+	TrashedFromUUID *uuid.UUID `json:"trashedFromUUID,omitempty"`
 }
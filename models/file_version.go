@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileVersion struct {
+	Model
+	File        *File     `json:"file,omitempty" gorm:"foreignKey:FileUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	FileUUID    uuid.UUID `json:"fileUUID" gorm:"uniqueIndex:idx_unique_file_version;not null;"`
+	Number      uint      `json:"number" gorm:"uniqueIndex:idx_unique_file_version;not null;"`
+	Archive     *Archive  `json:"archive,omitempty" gorm:"foreignKey:ArchiveUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ArchiveUUID uuid.UUID `json:"archiveUUID" gorm:"not null;"`
+	AuthorUUID  uuid.UUID `json:"authorUUID" gorm:"not null;"`
+	Timestamp   time.Time `json:"timestamp" gorm:"not null;"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Named set of users files can be shared with at once, managed by its owner
+type Group struct {
+	Model
+	OwnerUUID uuid.UUID `json:"ownerUUID" gorm:"uniqueIndex:idx_unique_group;not null;"`
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_unique_group;not null;"`
+}
+
+type GroupMember struct {
+	Model
+	Group     *Group    `json:"group,omitempty" gorm:"foreignKey:GroupUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	GroupUUID uuid.UUID `json:"groupUUID" gorm:"uniqueIndex:idx_unique_group_member;not null;"`
+	UserUUID  uuid.UUID `json:"userUUID" gorm:"uniqueIndex:idx_unique_group_member;not null;"`
+}
+
+// Share granting the role to every current member of the group
+type GroupShare struct {
+	Model
+	Group     *Group    `json:"group,omitempty" gorm:"foreignKey:GroupUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	GroupUUID uuid.UUID `json:"groupUUID" gorm:"uniqueIndex:idx_unique_group_share;not null;"`
+	File      *File     `json:"file,omitempty" gorm:"foreignKey:FileUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	FileUUID  uuid.UUID `json:"fileUUID" gorm:"uniqueIndex:idx_unique_group_share;not null;"`
+	Role      ShareRole `json:"role" gorm:"not null;default:viewer;"`
+	// Unknown for the shares created before sharers were recorded
+	SharerUUID *uuid.UUID `json:"sharerUUID,omitempty"`
+	// Never expires when nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" gorm:"index;"`
+}
@@ -13,7 +13,8 @@ type Model struct {
 	UpdatedAt time.Time `json:"-"`
 }
 
-func (m *Model) BeforeSave(tx *gorm.DB) error {
+// Only assigned on creation, updates through an empty model must not generate a primary key
+func (m *Model) BeforeCreate(tx *gorm.DB) error {
 	if m.UUID == uuid.Nil {
 		m.UUID = uuid.New()
 	}
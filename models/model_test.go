@@ -1,14 +1,24 @@
 package models
 
 import (
+	"os"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
 	"github.com/hawks-atlanta/fs-prototype/database"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
+
 /*
 TestBaseModel basic unit test to reduce the coverage footprint
 */
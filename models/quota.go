@@ -0,0 +1,12 @@
+package models
+
+import "github.com/google/uuid"
+
+// Limits of a user overriding the defaults of the configuration
+type Quota struct {
+	Model
+	UserUUID uuid.UUID `json:"userUUID" gorm:"uniqueIndex;not null;"`
+	// The default applies when nil, zero means unlimited
+	MaxBytes *uint64 `json:"maxBytes,omitempty"`
+	MaxFiles *uint64 `json:"maxFiles,omitempty"`
+}
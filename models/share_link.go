@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Read only access to a file or directory for anyone knowing the token
+type ShareLink struct {
+	Model
+	File        *File     `json:"file,omitempty" gorm:"foreignKey:FileUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	FileUUID    uuid.UUID `json:"fileUUID" gorm:"index;not null;"`
+	CreatorUUID uuid.UUID `json:"creatorUUID" gorm:"not null;"`
+	// Hex encoded SHA-256 of the token, the links are looked up by it
+	TokenHash string `json:"-" gorm:"uniqueIndex;not null;"`
+	// Only known when the link is created, it is never stored
+	Token string `json:"token,omitempty" gorm:"-"`
+	// Bcrypt hash of the password, empty for links without password
+	PasswordHash string `json:"-" gorm:"not null;default:'';"`
+	// Never expires when nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Unlimited when nil
+	MaxDownloads *uint `json:"maxDownloads,omitempty"`
+	Downloads    uint  `json:"downloads" gorm:"not null;default:0;"`
+}
+
+// Whether the link requires a password
+func (l *ShareLink) Protected() bool {
+	return l.PasswordHash != ""
+}
@@ -1,10 +1,56 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Access granted by a share, every role includes the permissions of the previous ones
+type ShareRole string
+
+const (
+	// Read the file and list the directory
+	RoleViewer ShareRole = "viewer"
+	// Same access as viewers, reserved for the annotations of the clients
+	RoleCommenter ShareRole = "commenter"
+	// Create, rename, move and trash the shared content
+	RoleEditor ShareRole = "editor"
+	// Share the content with other users and change their roles
+	RoleManager ShareRole = "manager"
+	// Implicit role of the owner of the file, can't be granted through shares
+	RoleOwner ShareRole = "owner"
+)
+
+var shareRoleLevels = map[ShareRole]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleManager:   4,
+	RoleOwner:     5,
+}
+
+// Whether the role can be granted through a share
+func (r ShareRole) Valid() bool {
+	return r != RoleOwner && shareRoleLevels[r] > 0
+}
+
+// Whether the role grants at least the permissions of the other one
+func (r ShareRole) Includes(other ShareRole) bool {
+	return shareRoleLevels[r] >= shareRoleLevels[other]
+}
 
 type SharedFile struct {
 	Model
 	UserUUID uuid.UUID `json:"userUUID" gorm:"uniqueIndex:idx_unique_shared_file;not null;"`
 	File     *File     `json:"file,omitempty" gorm:"foreignKey:FileUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	FileUUID uuid.UUID `json:"fileUUID,omitempty" gorm:"uniqueIndex:idx_unique_shared_file;not null;"`
+	// Shares created before roles existed are read only
+	Role ShareRole `json:"role" gorm:"not null;default:viewer;"`
+	// Unknown for the shares created before sharers were recorded
+	SharerUUID *uuid.UUID `json:"sharerUUID,omitempty"`
+	// Never expires when nil, expired shares grant nothing until they are reaped
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" gorm:"index;"`
+	// Set on the entries the controller resolves from the shares of a group, never stored
+	GroupUUID *uuid.UUID `json:"groupUUID,omitempty" gorm:"-"`
 }
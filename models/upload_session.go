@@ -0,0 +1,22 @@
+package models
+
+import "github.com/google/uuid"
+
+type UploadSession struct {
+	Model
+	OwnerUUID   uuid.UUID `json:"ownerUUID" gorm:"not null;"`
+	File        *File     `json:"file,omitempty" gorm:"foreignKey:FileUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	FileUUID    uuid.UUID `json:"fileUUID" gorm:"not null;"`
+	Archive     *Archive  `json:"archive,omitempty" gorm:"foreignKey:ArchiveUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ArchiveUUID uuid.UUID `json:"archiveUUID" gorm:"not null;"`
+	ChunkSize   uint64    `json:"chunkSize" gorm:"not null;"`
+	ChunkCount  uint      `json:"chunkCount" gorm:"not null;"`
+}
+
+type UploadChunk struct {
+	Model
+	Session     *UploadSession `json:"session,omitempty" gorm:"foreignKey:SessionUUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SessionUUID uuid.UUID      `json:"sessionUUID" gorm:"uniqueIndex:idx_unique_upload_chunk;not null;"`
+	Number      uint           `json:"number" gorm:"uniqueIndex:idx_unique_upload_chunk;not null;"`
+	Size        uint64         `json:"size" gorm:"not null;"`
+}
@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	metadatav1 "github.com/hawks-atlanta/fs-prototype/proto/metadata/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Domain of the reasons attached to the errors
+const ErrorDomain = "fs-prototype.hawks-atlanta.github.com"
+
+// gRPC interface of the controller
+type Service struct {
+	metadatav1.UnimplementedMetadataServiceServer
+	Controller *controller.Controller
+}
+
+func New(c *controller.Controller) (s *Service) {
+	return &Service{Controller: c}
+}
+
+// Registers the service in a new gRPC server
+func NewServer(c *controller.Controller, opts ...grpc.ServerOption) (srv *grpc.Server) {
+	srv = grpc.NewServer(opts...)
+	metadatav1.RegisterMetadataServiceServer(srv, New(c))
+	return srv
+}
+
+// Checked in order, permission errors also wrap the not found error of the query
+var errorCodes = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{controller.ErrPermissionDenied, codes.PermissionDenied, "PERMISSION_DENIED"},
+	{gorm.ErrRecordNotFound, codes.NotFound, "NOT_FOUND"},
+	{controller.ErrNameConflict, codes.AlreadyExists, "NAME_CONFLICT"},
+	{gorm.ErrDuplicatedKey, codes.AlreadyExists, "ALREADY_EXISTS"},
+	{controller.ErrNotDirectory, codes.FailedPrecondition, "NOT_DIRECTORY"},
+	{controller.ErrIsDirectory, codes.FailedPrecondition, "IS_DIRECTORY"},
+	{controller.ErrMoveCycle, codes.FailedPrecondition, "MOVE_CYCLE"},
+	{controller.ErrArchiveNotReady, codes.FailedPrecondition, "ARCHIVE_NOT_READY"},
+	{controller.ErrCopyTooLarge, codes.FailedPrecondition, "COPY_TOO_LARGE"},
+	{controller.ErrQuotaExceeded, codes.ResourceExhausted, "QUOTA_EXCEEDED"},
+	{controller.ErrInvalidCursor, codes.InvalidArgument, "INVALID_CURSOR"},
+	{controller.ErrInvalidShare, codes.InvalidArgument, "INVALID_SHARE"},
+	{controller.ErrInvalidMove, codes.InvalidArgument, "INVALID_MOVE"},
+	{controller.ErrInvalidConflictPolicy, codes.InvalidArgument, "INVALID_CONFLICT_POLICY"},
+	{controller.ErrInvalidPath, codes.InvalidArgument, "INVALID_PATH"},
+	{controller.ErrAmbiguousPath, codes.FailedPrecondition, "AMBIGUOUS_PATH"},
+	{controller.ErrInvalidGroup, codes.InvalidArgument, "INVALID_GROUP"},
+	{controller.ErrInvalidChunk, codes.InvalidArgument, "INVALID_CHUNK"},
+	{controller.ErrMissingChunks, codes.FailedPrecondition, "MISSING_CHUNKS"},
+	{controller.ErrArchiveReady, codes.FailedPrecondition, "ARCHIVE_READY"},
+	{controller.ErrBlobMissing, codes.FailedPrecondition, "BLOB_MISSING"},
+	{controller.ErrLinkExpired, codes.FailedPrecondition, "LINK_EXPIRED"},
+	{controller.ErrDownloadLimit, codes.ResourceExhausted, "DOWNLOAD_LIMIT"},
+	{controller.ErrNoBlobStore, codes.Unimplemented, "NO_BLOB_STORE"},
+	{blobstore.ErrNotFound, codes.NotFound, "BLOB_NOT_FOUND"},
+	{blobstore.ErrInvalidHash, codes.InvalidArgument, "INVALID_HASH"},
+	{blobstore.ErrHashMismatch, codes.InvalidArgument, "HASH_MISMATCH"},
+	{blobstore.ErrSizeMismatch, codes.InvalidArgument, "SIZE_MISMATCH"},
+}
+
+// Translates the errors of the controller into gRPC statuses.
+// The reason of the error is attached as an ErrorInfo detail so clients don't need to parse messages
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return withReason(entry.code, err.Error(), entry.reason, nil)
+		}
+	}
+	log.Printf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func withReason(code codes.Code, message, reason string, metadata map[string]string) error {
+	st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
+
+func invalidArgument(field, message string) error {
+	return withReason(codes.InvalidArgument, field+": "+message, "INVALID_ARGUMENT", map[string]string{"field": field})
+}
+
+func parseUUID(field, value string) (id uuid.UUID, err error) {
+	id, err = uuid.Parse(value)
+	if err != nil || id == uuid.Nil {
+		return id, invalidArgument(field, "invalid UUID")
+	}
+	return id, nil
+}
+
+func parseOptionalUUID(field string, value *string) (id *uuid.UUID, err error) {
+	if value == nil {
+		return nil, nil
+	}
+	parsed, err := parseUUID(field, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func toArchive(archive *models.Archive) *metadatav1.Archive {
+	if archive == nil {
+		return nil
+	}
+	return &metadatav1.Archive{
+		Uuid:    archive.UUID.String(),
+		Hash:    archive.Hash,
+		Size:    archive.Size,
+		IsReady: archive.IsReady,
+	}
+}
+
+func toFile(file *models.File) *metadatav1.File {
+	var pb = &metadatav1.File{
+		Uuid:      file.UUID.String(),
+		OwnerUuid: file.OwnerUUID.String(),
+		Name:      file.Name,
+		Archive:   toArchive(file.Archive),
+	}
+	if file.ParentUUID != nil {
+		parent := file.ParentUUID.String()
+		pb.ParentUuid = &parent
+	}
+	if file.ArchiveUUID != nil {
+		archive := file.ArchiveUUID.String()
+		pb.ArchiveUuid = &archive
+	}
+	if file.CreatorUUID != nil {
+		creator := file.CreatorUUID.String()
+		pb.CreatorUuid = &creator
+	}
+	return pb
+}
+
+func toSharedEntry(entry *controller.SharedEntry) *metadatav1.SharedEntry {
+	var pb = &metadatav1.SharedEntry{
+		File:       toFile(&entry.File),
+		ShareUuid:  entry.ShareUUID.String(),
+		SharerUuid: entry.SharerUUID.String(),
+		SharedAt:   entry.SharedAt.Unix(),
+		Role:       fromShareRoles[entry.Role],
+		Access:     fromShareRoles[entry.Access],
+	}
+	if entry.ExpiresAt != nil {
+		expiresAt := entry.ExpiresAt.Unix()
+		pb.ExpiresAt = &expiresAt
+	}
+	if entry.GroupUUID != nil {
+		group := entry.GroupUUID.String()
+		pb.GroupUuid = &group
+	}
+	return pb
+}
+
+func toSharedFiles(shared []models.SharedFile) []*metadatav1.SharedFile {
+	var pb = make([]*metadatav1.SharedFile, 0, len(shared))
+	for _, entry := range shared {
+		var share = &metadatav1.SharedFile{
+			Uuid:     entry.UUID.String(),
+			UserUuid: entry.UserUUID.String(),
+			FileUuid: entry.FileUUID.String(),
+			Role:     fromShareRoles[entry.Role],
+		}
+		if entry.GroupUUID != nil {
+			group := entry.GroupUUID.String()
+			share.GroupUuid = &group
+		}
+		if entry.ExpiresAt != nil {
+			expiresAt := entry.ExpiresAt.Unix()
+			share.ExpiresAt = &expiresAt
+		}
+		pb = append(pb, share)
+	}
+	return pb
+}
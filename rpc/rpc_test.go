@@ -0,0 +1,399 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/database"
+	metadatav1 "github.com/hawks-atlanta/fs-prototype/proto/metadata/v1"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
+
+// Serves the controller through an in-process listener
+func newTestClient(t *testing.T) (client metadatav1.MetadataServiceClient) {
+	assertions := assert.New(t)
+
+	c, err := controller.Default()
+	assertions.Nil(err)
+	t.Cleanup(func() { c.Close() })
+
+	var (
+		listener = bufconn.Listen(1 << 20)
+		srv      = NewServer(c)
+	)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assertions.Nil(err)
+	t.Cleanup(func() { conn.Close() })
+	return metadatav1.NewMetadataServiceClient(conn)
+}
+
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestToStatus(t *testing.T) {
+	var errs = []error{
+		controller.ErrPermissionDenied,
+		controller.ErrNotDirectory,
+		controller.ErrIsDirectory,
+		controller.ErrInvalidCursor,
+		controller.ErrInvalidPath,
+		controller.ErrAmbiguousPath,
+		controller.ErrInvalidShare,
+		controller.ErrInvalidGroup,
+		controller.ErrInvalidMove,
+		controller.ErrMoveCycle,
+		controller.ErrNameConflict,
+		controller.ErrInvalidConflictPolicy,
+		controller.ErrCopyTooLarge,
+		controller.ErrQuotaExceeded,
+		controller.ErrArchiveNotReady,
+		controller.ErrBlobMissing,
+		controller.ErrNoBlobStore,
+		controller.ErrArchiveReady,
+		controller.ErrMissingChunks,
+		controller.ErrInvalidChunk,
+		controller.ErrLinkExpired,
+		controller.ErrDownloadLimit,
+		blobstore.ErrNotFound,
+		blobstore.ErrInvalidHash,
+		blobstore.ErrHashMismatch,
+		blobstore.ErrSizeMismatch,
+	}
+	for _, known := range errs {
+		t.Run(known.Error(), func(t *testing.T) {
+			assertions := assert.New(t)
+
+			err := toStatus(fmt.Errorf("failed: %w", known))
+			assertions.NotEqual(codes.Internal, status.Code(err))
+			assertions.NotEmpty(errorReason(err))
+		})
+	}
+}
+
+func TestService_Files(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		client := newTestClient(t)
+		var (
+			ctx      = context.Background()
+			owner    = uuid.NewString()
+			contents = uuid.NewString()
+		)
+
+		dir, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: owner,
+			Filename:  "docs",
+		})
+		assertions.Nil(err)
+		assertions.Nil(dir.File.ArchiveUuid)
+
+		file, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid:       owner,
+			Filename:        "notes.txt",
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &dir.File.Uuid,
+		})
+		assertions.Nil(err)
+		assertions.Equal(dir.File.Uuid, file.File.GetParentUuid())
+
+		listing, err := client.ListDirectory(ctx, &metadatav1.ListDirectoryRequest{
+			UserUuid:   owner,
+			ParentUuid: &dir.File.Uuid,
+		})
+		assertions.Nil(err)
+		assertions.Len(listing.Files, 1)
+		assertions.Equal(utils.Hash(contents), listing.Files[0].Archive.Hash)
+
+		// Contents are still being uploaded
+		_, err = client.QueryFile(ctx, &metadatav1.QueryFileRequest{
+			UserUuid: owner,
+			FileUuid: file.File.Uuid,
+		})
+		assertions.Equal(codes.FailedPrecondition, status.Code(err))
+		assertions.Equal("ARCHIVE_NOT_READY", errorReason(err))
+
+		var newName = "renamed.txt"
+		_, err = client.MoveFile(ctx, &metadatav1.MoveFileRequest{
+			OwnerUuid: owner,
+			FileUuid:  file.File.Uuid,
+			NewName:   &newName,
+			ToRoot:    true,
+		})
+		assertions.Nil(err)
+
+		_, err = client.MoveFile(ctx, &metadatav1.MoveFileRequest{
+			OwnerUuid:   owner,
+			FileUuid:    dir.File.Uuid,
+			NewLocation: &dir.File.Uuid,
+		})
+		assertions.Equal(codes.FailedPrecondition, status.Code(err))
+		assertions.Equal("MOVE_CYCLE", errorReason(err))
+
+		copied, err := client.CopyFile(ctx, &metadatav1.CopyFileRequest{
+			UserUuid:    owner,
+			FileUuid:    file.File.Uuid,
+			NewLocation: &dir.File.Uuid,
+		})
+		assertions.Nil(err)
+		assertions.Equal(newName, copied.File.Name)
+		assertions.Equal(file.File.ArchiveUuid, copied.File.ArchiveUuid)
+
+		_, err = client.CopyFile(ctx, &metadatav1.CopyFileRequest{
+			UserUuid:    owner,
+			FileUuid:    file.File.Uuid,
+			NewLocation: &dir.File.Uuid,
+		})
+		assertions.Equal(codes.AlreadyExists, status.Code(err))
+		assertions.Equal("NAME_CONFLICT", errorReason(err))
+
+		usage, err := client.GetUsage(ctx, &metadatav1.GetUsageRequest{UserUuid: owner})
+		assertions.Nil(err)
+		assertions.Equal(uint64(3), usage.Files)
+		assertions.Nil(usage.RemainingBytes)
+
+		_, err = client.DeleteFile(ctx, &metadatav1.DeleteFileRequest{
+			OwnerUuid: owner,
+			FileUuid:  file.File.Uuid,
+			Permanent: true,
+		})
+		assertions.Nil(err)
+
+		_, err = client.DeleteFile(ctx, &metadatav1.DeleteFileRequest{
+			OwnerUuid: owner,
+			FileUuid:  file.File.Uuid,
+		})
+		assertions.Equal(codes.NotFound, status.Code(err))
+	})
+	t.Run("Already exists", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		client := newTestClient(t)
+		var (
+			ctx   = context.Background()
+			owner = uuid.NewString()
+		)
+
+		dir, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: owner,
+			Filename:  "docs",
+		})
+		assertions.Nil(err)
+
+		var req = metadatav1.CreateFileRequest{
+			OwnerUuid:       owner,
+			Filename:        "duplicated",
+			ParentDirectory: &dir.File.Uuid,
+		}
+		_, err = client.CreateFile(ctx, &req)
+		assertions.Nil(err)
+
+		_, err = client.CreateFile(ctx, &req)
+		assertions.Equal(codes.AlreadyExists, status.Code(err))
+		assertions.Equal("NAME_CONFLICT", errorReason(err))
+
+		req.Conflict = metadatav1.ConflictPolicy_CONFLICT_POLICY_RENAME
+		renamed, err := client.CreateFile(ctx, &req)
+		assertions.Nil(err)
+		assertions.Equal("duplicated (1)", renamed.File.Name)
+	})
+	t.Run("Invalid arguments", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		client := newTestClient(t)
+		var ctx = context.Background()
+
+		_, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: "not-a-uuid",
+			Filename:  "docs",
+		})
+		assertions.Equal(codes.InvalidArgument, status.Code(err))
+		assertions.Equal("INVALID_ARGUMENT", errorReason(err))
+
+		_, err = client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: uuid.NewString(),
+		})
+		assertions.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = client.ListDirectory(ctx, &metadatav1.ListDirectoryRequest{
+			UserUuid: uuid.NewString(),
+			SortBy:   metadatav1.SortBy(42),
+		})
+		assertions.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = client.ListDirectory(ctx, &metadatav1.ListDirectoryRequest{
+			UserUuid: uuid.NewString(),
+			Cursor:   "invalid",
+		})
+		assertions.Equal(codes.InvalidArgument, status.Code(err))
+		assertions.Equal("INVALID_CURSOR", errorReason(err))
+	})
+}
+
+func TestService_Shares(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		client := newTestClient(t)
+		var (
+			ctx       = context.Background()
+			owner     = uuid.NewString()
+			recipient = uuid.NewString()
+		)
+
+		dir, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: owner,
+			Filename:  "shared",
+		})
+		assertions.Nil(err)
+
+		_, err = client.CanReadFile(ctx, &metadatav1.CanReadFileRequest{
+			UserUuid: recipient,
+			FileUuid: dir.File.Uuid,
+		})
+		assertions.Equal(codes.PermissionDenied, status.Code(err))
+
+		var share = metadatav1.ShareFileRequest{
+			OwnerUuid:      owner,
+			FileUuid:       dir.File.Uuid,
+			TargetUserUuid: recipient,
+		}
+		_, err = client.ShareFile(ctx, &share)
+		assertions.Nil(err)
+
+		_, err = client.ShareFile(ctx, &share)
+		assertions.Equal(codes.AlreadyExists, status.Code(err))
+
+		_, err = client.CanReadFile(ctx, &metadatav1.CanReadFileRequest{
+			UserUuid: recipient,
+			FileUuid: dir.File.Uuid,
+		})
+		assertions.Nil(err)
+
+		withMe, err := client.ShareWithMe(ctx, &metadatav1.ShareWithMeRequest{UserUuid: recipient})
+		assertions.Nil(err)
+		assertions.Len(withMe.Shared, 1)
+		assertions.Equal(dir.File.Uuid, withMe.Shared[0].FileUuid)
+		assertions.Len(withMe.Entries, 1)
+		assertions.Equal(dir.File.Name, withMe.Entries[0].File.Name)
+		assertions.Equal(owner, withMe.Entries[0].SharerUuid)
+		assertions.Equal(metadatav1.ShareRole_SHARE_ROLE_VIEWER, withMe.Entries[0].Access)
+
+		withWho, err := client.ShareWithWho(ctx, &metadatav1.ShareWithWhoRequest{
+			OwnerUuid: owner,
+			FileUuid:  dir.File.Uuid,
+		})
+		assertions.Nil(err)
+		assertions.Len(withWho.Shared, 1)
+		assertions.Equal(recipient, withWho.Shared[0].UserUuid)
+		assertions.Equal(metadatav1.ShareRole_SHARE_ROLE_VIEWER, withWho.Shared[0].Role)
+		assertions.Nil(withWho.Shared[0].ExpiresAt)
+
+		var (
+			contractor = uuid.NewString()
+			expiresAt  = time.Now().Add(time.Hour).Unix()
+		)
+		_, err = client.ShareFile(ctx, &metadatav1.ShareFileRequest{
+			OwnerUuid:      owner,
+			FileUuid:       dir.File.Uuid,
+			TargetUserUuid: contractor,
+			ExpiresAt:      &expiresAt,
+		})
+		assertions.Nil(err)
+		withMe, err = client.ShareWithMe(ctx, &metadatav1.ShareWithMeRequest{UserUuid: contractor})
+		assertions.Nil(err)
+		assertions.Len(withMe.Shared, 1)
+		assertions.Equal(expiresAt, *withMe.Shared[0].ExpiresAt)
+
+		var change = metadatav1.ChangeShareRoleRequest{
+			OwnerUuid:      owner,
+			FileUuid:       dir.File.Uuid,
+			TargetUserUuid: recipient,
+		}
+		_, err = client.ChangeShareRole(ctx, &change)
+		assertions.Equal(codes.InvalidArgument, status.Code(err))
+
+		change.Role = metadatav1.ShareRole_SHARE_ROLE_EDITOR
+		_, err = client.ChangeShareRole(ctx, &change)
+		assertions.Nil(err)
+
+		file, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid:       recipient,
+			Filename:        "notes",
+			ParentDirectory: &dir.File.Uuid,
+		})
+		assertions.Nil(err)
+		assertions.Equal(owner, file.File.OwnerUuid)
+		assertions.Equal(recipient, file.File.GetCreatorUuid())
+
+		_, err = client.UnshareFile(ctx, &metadatav1.UnshareFileRequest{
+			OwnerUuid:      owner,
+			FileUuid:       dir.File.Uuid,
+			TargetUserUuid: recipient,
+		})
+		assertions.Nil(err)
+
+		withMe, err = client.ShareWithMe(ctx, &metadatav1.ShareWithMeRequest{UserUuid: recipient})
+		assertions.Nil(err)
+		assertions.Len(withMe.Shared, 0)
+	})
+	t.Run("Permission denied", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		client := newTestClient(t)
+		var ctx = context.Background()
+
+		dir, err := client.CreateFile(ctx, &metadatav1.CreateFileRequest{
+			OwnerUuid: uuid.NewString(),
+			Filename:  "private",
+		})
+		assertions.Nil(err)
+
+		_, err = client.ShareFile(ctx, &metadatav1.ShareFileRequest{
+			OwnerUuid:      uuid.NewString(),
+			FileUuid:       dir.File.Uuid,
+			TargetUserUuid: uuid.NewString(),
+		})
+		assertions.Equal(codes.PermissionDenied, status.Code(err))
+		assertions.Equal("PERMISSION_DENIED", errorReason(err))
+	})
+}
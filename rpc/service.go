@@ -0,0 +1,367 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	metadatav1 "github.com/hawks-atlanta/fs-prototype/proto/metadata/v1"
+)
+
+var sortFields = map[metadatav1.SortBy]controller.SortBy{
+	metadatav1.SortBy_SORT_BY_UNSPECIFIED: controller.SortByName,
+	metadatav1.SortBy_SORT_BY_NAME:        controller.SortByName,
+	metadatav1.SortBy_SORT_BY_SIZE:        controller.SortBySize,
+	metadatav1.SortBy_SORT_BY_CREATED_AT:  controller.SortByCreatedAt,
+}
+
+var conflictPolicies = map[metadatav1.ConflictPolicy]controller.ConflictPolicy{
+	metadatav1.ConflictPolicy_CONFLICT_POLICY_UNSPECIFIED: controller.ConflictFail,
+	metadatav1.ConflictPolicy_CONFLICT_POLICY_FAIL:        controller.ConflictFail,
+	metadatav1.ConflictPolicy_CONFLICT_POLICY_RENAME:      controller.ConflictRename,
+	metadatav1.ConflictPolicy_CONFLICT_POLICY_REPLACE:     controller.ConflictReplace,
+	metadatav1.ConflictPolicy_CONFLICT_POLICY_MERGE:       controller.ConflictMerge,
+}
+
+var shareRoles = map[metadatav1.ShareRole]models.ShareRole{
+	metadatav1.ShareRole_SHARE_ROLE_VIEWER:    models.RoleViewer,
+	metadatav1.ShareRole_SHARE_ROLE_COMMENTER: models.RoleCommenter,
+	metadatav1.ShareRole_SHARE_ROLE_EDITOR:    models.RoleEditor,
+	metadatav1.ShareRole_SHARE_ROLE_MANAGER:   models.RoleManager,
+}
+
+var fromShareRoles = map[models.ShareRole]metadatav1.ShareRole{
+	models.RoleViewer:    metadatav1.ShareRole_SHARE_ROLE_VIEWER,
+	models.RoleCommenter: metadatav1.ShareRole_SHARE_ROLE_COMMENTER,
+	models.RoleEditor:    metadatav1.ShareRole_SHARE_ROLE_EDITOR,
+	models.RoleManager:   metadatav1.ShareRole_SHARE_ROLE_MANAGER,
+}
+
+func (s *Service) CreateFile(ctx context.Context, req *metadatav1.CreateFileRequest) (res *metadatav1.CreateFileResponse, err error) {
+	conflict, found := conflictPolicies[req.Conflict]
+	if !found {
+		return nil, invalidArgument("conflict", "unknown conflict policy")
+	}
+	var cf = controller.CreateFile{
+		Filename: req.Filename,
+		Hash:     req.Hash,
+		Size:     req.Size,
+		Conflict: conflict,
+	}
+	if cf.Filename == "" {
+		return nil, invalidArgument("filename", "is required")
+	}
+	if cf.Hash == "" && cf.Size != 0 {
+		return nil, invalidArgument("hash", "is required by files")
+	}
+	cf.OwnerUUID, err = parseUUID("owner_uuid", req.OwnerUuid)
+	if err != nil {
+		return nil, err
+	}
+	cf.ParentDirectory, err = parseOptionalUUID("parent_directory", req.ParentDirectory)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.Controller.CreateFile(&cf)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.CreateFileResponse{File: toFile(&file)}, nil
+}
+
+func (s *Service) ListDirectory(ctx context.Context, req *metadatav1.ListDirectoryRequest) (res *metadatav1.ListDirectoryResponse, err error) {
+	sortBy, found := sortFields[req.SortBy]
+	if !found {
+		return nil, invalidArgument("sort_by", "unknown sort field")
+	}
+	if req.Limit < 0 {
+		return nil, invalidArgument("limit", "can't be negative")
+	}
+	var ld = controller.ListDirectory{
+		SortBy:     sortBy,
+		Descending: req.Descending,
+		Cursor:     req.Cursor,
+		Limit:      int(req.Limit),
+	}
+	ld.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	ld.ParentUUID, err = parseOptionalUUID("parent_uuid", req.ParentUuid)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := s.Controller.ListDirectory(&ld)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res = &metadatav1.ListDirectoryResponse{
+		Files:      make([]*metadatav1.File, 0, len(dir.Files)),
+		NextCursor: dir.NextCursor,
+	}
+	for index := range dir.Files {
+		res.Files = append(res.Files, toFile(&dir.Files[index]))
+	}
+	return res, nil
+}
+
+func (s *Service) QueryFile(ctx context.Context, req *metadatav1.QueryFileRequest) (res *metadatav1.QueryFileResponse, err error) {
+	var qf controller.QueryFile
+	qf.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	qf.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	qf.VersionUUID, err = parseOptionalUUID("version_uuid", req.VersionUuid)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := s.Controller.QueryFile(&qf)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.QueryFileResponse{Archive: toArchive(&archive)}, nil
+}
+
+func (s *Service) MoveFile(ctx context.Context, req *metadatav1.MoveFileRequest) (res *metadatav1.MoveFileResponse, err error) {
+	if req.NewName != nil && *req.NewName == "" {
+		return nil, invalidArgument("new_name", "can't be empty")
+	}
+	conflict, found := conflictPolicies[req.Conflict]
+	if !found {
+		return nil, invalidArgument("conflict", "unknown conflict policy")
+	}
+	var mf = controller.MoveFile{NewName: req.NewName, ToRoot: req.ToRoot, Conflict: conflict}
+	mf.OwnerUUID, err = parseUUID("owner_uuid", req.OwnerUuid)
+	if err != nil {
+		return nil, err
+	}
+	mf.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	mf.NewLocation, err = parseOptionalUUID("new_location", req.NewLocation)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Controller.MoveFile(&mf)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.MoveFileResponse{}, nil
+}
+
+func (s *Service) CopyFile(ctx context.Context, req *metadatav1.CopyFileRequest) (res *metadatav1.CopyFileResponse, err error) {
+	if req.NewName != nil && *req.NewName == "" {
+		return nil, invalidArgument("new_name", "can't be empty")
+	}
+	conflict, found := conflictPolicies[req.Conflict]
+	if !found {
+		return nil, invalidArgument("conflict", "unknown conflict policy")
+	}
+	var cf = controller.CopyFile{NewName: req.NewName, Conflict: conflict}
+	cf.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	cf.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	cf.NewLocation, err = parseOptionalUUID("new_location", req.NewLocation)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.Controller.CopyFile(&cf)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.CopyFileResponse{File: toFile(&file)}, nil
+}
+
+func (s *Service) DeleteFile(ctx context.Context, req *metadatav1.DeleteFileRequest) (res *metadatav1.DeleteFileResponse, err error) {
+	var df = controller.DeleteFile{Permanent: req.Permanent}
+	df.OwnerUUID, err = parseUUID("owner_uuid", req.OwnerUuid)
+	if err != nil {
+		return nil, err
+	}
+	df.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Controller.DeleteFile(&df)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.DeleteFileResponse{}, nil
+}
+
+func (s *Service) CanReadFile(ctx context.Context, req *metadatav1.CanReadFileRequest) (res *metadatav1.CanReadFileResponse, err error) {
+	var crf controller.CanReadFile
+	crf.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	crf.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Controller.CanReadFile(&crf)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.CanReadFileResponse{}, nil
+}
+
+func shareRequest(ownerUUID, fileUUID, targetUserUUID string) (sr controller.ShareRequest, err error) {
+	sr.OwnerUUID, err = parseUUID("owner_uuid", ownerUUID)
+	if err != nil {
+		return sr, err
+	}
+	sr.FileUUID, err = parseUUID("file_uuid", fileUUID)
+	if err != nil {
+		return sr, err
+	}
+	sr.TargetUserUUID, err = parseUUID("target_user_uuid", targetUserUUID)
+	if err != nil {
+		return sr, err
+	}
+	if sr.TargetUserUUID == sr.OwnerUUID {
+		return sr, invalidArgument("target_user_uuid", "can't share with the owner")
+	}
+	return sr, nil
+}
+
+func (s *Service) ShareFile(ctx context.Context, req *metadatav1.ShareFileRequest) (res *metadatav1.ShareFileResponse, err error) {
+	sr, err := shareRequest(req.OwnerUuid, req.FileUuid, req.TargetUserUuid)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role != metadatav1.ShareRole_SHARE_ROLE_UNSPECIFIED {
+		role, found := shareRoles[req.Role]
+		if !found {
+			return nil, invalidArgument("role", "unknown role")
+		}
+		sr.Role = role
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := time.Unix(*req.ExpiresAt, 0)
+		sr.ExpiresAt = &expiresAt
+	}
+	err = s.Controller.ShareFile(&sr)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.ShareFileResponse{}, nil
+}
+
+func (s *Service) ChangeShareRole(ctx context.Context, req *metadatav1.ChangeShareRoleRequest) (res *metadatav1.ChangeShareRoleResponse, err error) {
+	sr, err := shareRequest(req.OwnerUuid, req.FileUuid, req.TargetUserUuid)
+	if err != nil {
+		return nil, err
+	}
+	role, found := shareRoles[req.Role]
+	if !found {
+		return nil, invalidArgument("role", "is required")
+	}
+	sr.Role = role
+	err = s.Controller.ChangeShareRole(&sr)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.ChangeShareRoleResponse{}, nil
+}
+
+func (s *Service) UnshareFile(ctx context.Context, req *metadatav1.UnshareFileRequest) (res *metadatav1.UnshareFileResponse, err error) {
+	sr, err := shareRequest(req.OwnerUuid, req.FileUuid, req.TargetUserUuid)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Controller.UnshareFile(&sr)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.UnshareFileResponse{}, nil
+}
+
+func (s *Service) ShareWithMe(ctx context.Context, req *metadatav1.ShareWithMeRequest) (res *metadatav1.ShareWithMeResponse, err error) {
+	if req.Limit < 0 {
+		return nil, invalidArgument("limit", "can't be negative")
+	}
+	var swm = controller.ShareWithMe{
+		Descending: req.Descending,
+		Cursor:     req.Cursor,
+		Limit:      int(req.Limit),
+	}
+	swm.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	swm.OwnerUUID, err = parseOptionalUUID("owner_uuid", req.OwnerUuid)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := s.Controller.ShareWithMe(&swm)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res = &metadatav1.ShareWithMeResponse{
+		Shared:     make([]*metadatav1.SharedFile, 0, len(shared.Entries)),
+		Entries:    make([]*metadatav1.SharedEntry, 0, len(shared.Entries)),
+		NextCursor: shared.NextCursor,
+	}
+	for index := range shared.Entries {
+		var entry = toSharedEntry(&shared.Entries[index])
+		res.Entries = append(res.Entries, entry)
+		res.Shared = append(res.Shared, &metadatav1.SharedFile{
+			Uuid:      entry.ShareUuid,
+			UserUuid:  swm.UserUUID.String(),
+			FileUuid:  entry.File.Uuid,
+			Role:      entry.Role,
+			GroupUuid: entry.GroupUuid,
+			ExpiresAt: entry.ExpiresAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *Service) ShareWithWho(ctx context.Context, req *metadatav1.ShareWithWhoRequest) (res *metadatav1.ShareWithWhoResponse, err error) {
+	var sww controller.ShareWithWho
+	sww.OwnerUUID, err = parseUUID("owner_uuid", req.OwnerUuid)
+	if err != nil {
+		return nil, err
+	}
+	sww.FileUUID, err = parseUUID("file_uuid", req.FileUuid)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := s.Controller.ShareWithWho(&sww)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.ShareWithWhoResponse{Shared: toSharedFiles(shared)}, nil
+}
+
+func (s *Service) GetUsage(ctx context.Context, req *metadatav1.GetUsageRequest) (res *metadatav1.GetUsageResponse, err error) {
+	var gu controller.GetUsage
+	gu.UserUUID, err = parseUUID("user_uuid", req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.Controller.GetUsage(&gu)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &metadatav1.GetUsageResponse{
+		Bytes:          usage.Bytes,
+		Files:          usage.Files,
+		MaxBytes:       usage.MaxBytes,
+		MaxFiles:       usage.MaxFiles,
+		RemainingBytes: usage.RemainingBytes,
+		RemainingFiles: usage.RemainingFiles,
+	}, nil
+}
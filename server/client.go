@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"gorm.io/gorm"
+)
+
+// Returned by the client when the server answers with an error
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", http.StatusText(e.Status), e.Message)
+}
+
+// Allows checking the errors of the client with the same sentinels as the controller
+func (e *StatusError) Unwrap() error {
+	switch e.Status {
+	case http.StatusForbidden:
+		return controller.ErrPermissionDenied
+	case http.StatusNotFound:
+		return gorm.ErrRecordNotFound
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	default:
+		return nil
+	}
+}
+
+// Client of the HTTP API. Its methods mirror the ones of the controller,
+// the user of each request is sent in the UserHeader
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string) (c *Client) {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Sends the request and decodes the response into out when given
+func (c *Client) do(method, path string, query url.Values, user uuid.UUID, body, out any) (err error) {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(buf)
+	}
+	var target = c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(UserHeader, user.String())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		var e Error
+		if json.NewDecoder(res.Body).Decode(&e) != nil {
+			e.Message = res.Status
+		}
+		return &StatusError{Status: res.StatusCode, Message: e.Message}
+	}
+	if out != nil {
+		err = json.NewDecoder(res.Body).Decode(out)
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) CreateFile(cf *controller.CreateFile) (file models.File, err error) {
+	err = c.do(http.MethodPost, "/files", nil, cf.OwnerUUID, cf, &file)
+	return file, err
+}
+
+func (c *Client) ListDirectory(ld *controller.ListDirectory) (dir controller.Directory, err error) {
+	var query = url.Values{}
+	if ld.ParentUUID != nil {
+		query.Set("parent", ld.ParentUUID.String())
+	}
+	if ld.SortBy != "" {
+		query.Set("sortBy", string(ld.SortBy))
+	}
+	if ld.Descending {
+		query.Set("descending", "true")
+	}
+	if ld.Cursor != "" {
+		query.Set("cursor", ld.Cursor)
+	}
+	if ld.Limit != 0 {
+		query.Set("limit", strconv.Itoa(ld.Limit))
+	}
+	err = c.do(http.MethodGet, "/files", query, ld.UserUUID, nil, &dir)
+	return dir, err
+}
+
+// Contents still being uploaded are reported with controller.ErrArchiveNotReady,
+// but unlike the controller the archive is not returned along with it
+func (c *Client) QueryFile(qf *controller.QueryFile) (archive models.Archive, err error) {
+	var query = url.Values{}
+	if qf.VersionUUID != nil {
+		query.Set("version", qf.VersionUUID.String())
+	}
+	err = c.do(http.MethodGet, "/files/"+qf.FileUUID.String(), query, qf.UserUUID, nil, &archive)
+	if e, ok := err.(*StatusError); ok && e.Status == http.StatusConflict {
+		err = fmt.Errorf("%w: %s", controller.ErrArchiveNotReady, e.Message)
+	}
+	return archive, err
+}
+
+func (c *Client) GetFile(gf *controller.GetFile) (file models.File, err error) {
+	err = c.do(http.MethodGet, "/files/"+gf.FileUUID.String()+"/metadata", nil, gf.UserUUID, nil, &file)
+	return file, err
+}
+
+func (c *Client) MoveFile(mf *controller.MoveFile) (err error) {
+	return c.do(http.MethodPatch, "/files/"+mf.FileUUID.String(), nil, mf.OwnerUUID, mf, nil)
+}
+
+func (c *Client) CopyFile(cf *controller.CopyFile) (file models.File, err error) {
+	err = c.do(http.MethodPost, "/files/"+cf.FileUUID.String()+"/copy", nil, cf.UserUUID, cf, &file)
+	return file, err
+}
+
+func (c *Client) DeleteFile(df *controller.DeleteFile) (err error) {
+	var query = url.Values{}
+	if df.Permanent {
+		query.Set("permanent", "true")
+	}
+	return c.do(http.MethodDelete, "/files/"+df.FileUUID.String(), query, df.OwnerUUID, nil, nil)
+}
+
+func (c *Client) ShareFile(sr *controller.ShareRequest) (err error) {
+	var body = controller.ShareRequest{TargetUserUUID: sr.TargetUserUUID, Role: sr.Role, ExpiresAt: sr.ExpiresAt}
+	return c.do(http.MethodPost, "/files/"+sr.FileUUID.String()+"/shares", nil, sr.OwnerUUID, body, nil)
+}
+
+func (c *Client) ChangeShareRole(sr *controller.ShareRequest) (err error) {
+	var (
+		path = "/files/" + sr.FileUUID.String() + "/shares/" + sr.TargetUserUUID.String()
+		body = controller.ShareRequest{Role: sr.Role}
+	)
+	return c.do(http.MethodPatch, path, nil, sr.OwnerUUID, body, nil)
+}
+
+func (c *Client) UnshareFile(sr *controller.ShareRequest) (err error) {
+	var path = "/files/" + sr.FileUUID.String() + "/shares/" + sr.TargetUserUUID.String()
+	return c.do(http.MethodDelete, path, nil, sr.OwnerUUID, nil, nil)
+}
+
+func (c *Client) ShareWithMe(swm *controller.ShareWithMe) (shared controller.SharedWithMe, err error) {
+	var query = url.Values{}
+	if swm.OwnerUUID != nil {
+		query.Set("owner", swm.OwnerUUID.String())
+	}
+	if swm.Descending {
+		query.Set("descending", "true")
+	}
+	if swm.Cursor != "" {
+		query.Set("cursor", swm.Cursor)
+	}
+	if swm.Limit != 0 {
+		query.Set("limit", strconv.Itoa(swm.Limit))
+	}
+	err = c.do(http.MethodGet, "/shared", query, swm.UserUUID, nil, &shared)
+	return shared, err
+}
+
+func (c *Client) ShareWithWho(sww *controller.ShareWithWho) (shared []models.SharedFile, err error) {
+	err = c.do(http.MethodGet, "/files/"+sww.FileUUID.String()+"/shares", nil, sww.OwnerUUID, nil, &shared)
+	return shared, err
+}
+
+func (c *Client) ResolvePath(rp *controller.ResolvePath) (file models.File, err error) {
+	var query = url.Values{"path": {rp.Path}}
+	err = c.do(http.MethodGet, "/paths", query, rp.UserUUID, nil, &file)
+	return file, err
+}
+
+func (c *Client) PathOf(po *controller.PathOf) (filePath string, err error) {
+	var p Path
+	err = c.do(http.MethodGet, "/files/"+po.FileUUID.String()+"/path", nil, po.UserUUID, nil, &p)
+	return p.Path, err
+}
+
+func (c *Client) GetUsage(gu *controller.GetUsage) (usage controller.Usage, err error) {
+	err = c.do(http.MethodGet, "/usage", nil, gu.UserUUID, nil, &usage)
+	return usage, err
+}
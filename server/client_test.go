@@ -0,0 +1,163 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestClient(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			client    = NewClient(ts.URL + "/")
+			owner     = uuid.New()
+			recipient = uuid.New()
+			contents  = uuid.NewString()
+		)
+
+		dir, err := client.CreateFile(&controller.CreateFile{
+			OwnerUUID: owner,
+			Filename:  "docs",
+		})
+		assertions.Nil(err)
+
+		file, err := client.CreateFile(&controller.CreateFile{
+			OwnerUUID:       owner,
+			Filename:        "notes.txt",
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &dir.UUID,
+		})
+		assertions.Nil(err)
+
+		listing, err := client.ListDirectory(&controller.ListDirectory{
+			UserUUID:   owner,
+			ParentUUID: &dir.UUID,
+			SortBy:     controller.SortBySize,
+			Limit:      10,
+		})
+		assertions.Nil(err)
+		assertions.Len(listing.Files, 1)
+
+		_, err = client.QueryFile(&controller.QueryFile{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		})
+		assertions.ErrorIs(err, controller.ErrArchiveNotReady)
+
+		resolved, err := client.ResolvePath(&controller.ResolvePath{
+			UserUUID: owner,
+			Path:     "/docs/notes.txt",
+		})
+		assertions.Nil(err)
+		assertions.Equal(file.UUID, resolved.UUID)
+
+		found, err := client.GetFile(&controller.GetFile{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		})
+		assertions.Nil(err)
+		assertions.Equal(file.Name, found.Name)
+
+		var newName = "renamed.txt"
+		err = client.MoveFile(&controller.MoveFile{
+			OwnerUUID: owner,
+			FileUUID:  file.UUID,
+			NewName:   &newName,
+		})
+		assertions.Nil(err)
+
+		filePath, err := client.PathOf(&controller.PathOf{
+			UserUUID: owner,
+			FileUUID: file.UUID,
+		})
+		assertions.Nil(err)
+		assertions.Equal("/docs/renamed.txt", filePath)
+
+		copied, err := client.CopyFile(&controller.CopyFile{
+			UserUUID: owner,
+			FileUUID: dir.UUID,
+			NewName:  &newName,
+		})
+		assertions.Nil(err)
+		assertions.Equal(newName, copied.Name)
+
+		usage, err := client.GetUsage(&controller.GetUsage{UserUUID: owner})
+		assertions.Nil(err)
+		assertions.Equal(uint64(4), usage.Files)
+		assertions.Equal(uint64(2*len(contents)), usage.Bytes)
+
+		var sr = controller.ShareRequest{
+			OwnerUUID:      owner,
+			FileUUID:       dir.UUID,
+			TargetUserUUID: recipient,
+		}
+		err = client.ShareFile(&sr)
+		assertions.Nil(err)
+
+		withMe, err := client.ShareWithMe(&controller.ShareWithMe{UserUUID: recipient})
+		assertions.Nil(err)
+		assertions.Len(withMe.Entries, 1)
+		assertions.Equal("docs", withMe.Entries[0].File.Name)
+		assertions.Equal(owner, withMe.Entries[0].SharerUUID)
+
+		shared, err := client.ShareWithWho(&controller.ShareWithWho{
+			OwnerUUID: owner,
+			FileUUID:  dir.UUID,
+		})
+		assertions.Nil(err)
+		assertions.Len(shared, 1)
+		assertions.Equal(models.RoleViewer, shared[0].Role)
+
+		sr.Role = models.RoleEditor
+		err = client.ChangeShareRole(&sr)
+		assertions.Nil(err)
+
+		withMe, err = client.ShareWithMe(&controller.ShareWithMe{UserUUID: recipient, OwnerUUID: &owner})
+		assertions.Nil(err)
+		assertions.Equal(models.RoleEditor, withMe.Entries[0].Role)
+
+		err = client.UnshareFile(&sr)
+		assertions.Nil(err)
+
+		err = client.DeleteFile(&controller.DeleteFile{
+			OwnerUUID: owner,
+			FileUUID:  dir.UUID,
+			Permanent: true,
+		})
+		assertions.Nil(err)
+
+		_, err = client.ResolvePath(&controller.ResolvePath{
+			UserUUID: owner,
+			Path:     "/docs",
+		})
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Permission denied", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var client = NewClient(ts.URL)
+
+		dir, err := client.CreateFile(&controller.CreateFile{
+			OwnerUUID: uuid.New(),
+			Filename:  "private",
+		})
+		assertions.Nil(err)
+
+		err = client.ShareFile(&controller.ShareRequest{
+			OwnerUUID:      uuid.New(),
+			FileUUID:       dir.UUID,
+			TargetUserUUID: uuid.New(),
+		})
+		assertions.ErrorIs(err, controller.ErrPermissionDenied)
+	})
+}
@@ -0,0 +1,676 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hawks-atlanta/fs-prototype/controller"
+)
+
+func (s *Server) registerRoutes() {
+	// Files
+	s.handle(http.MethodPost, "/files", s.createFile)
+	s.handle(http.MethodGet, "/files", s.listDirectory)
+	s.handle(http.MethodGet, "/files/{file}", s.queryFile)
+	s.handle(http.MethodPatch, "/files/{file}", s.moveFile)
+	s.handle(http.MethodDelete, "/files/{file}", s.deleteFile)
+	s.handle(http.MethodPost, "/files/{file}/copy", s.copyFile)
+	s.handle(http.MethodGet, "/files/{file}/access", s.canReadFile)
+	s.handle(http.MethodGet, "/files/{file}/metadata", s.getFile)
+	s.handle(http.MethodGet, "/files/{file}/tree", s.tree)
+	s.handle(http.MethodGet, "/files/{file}/path", s.pathOf)
+	s.handle(http.MethodGet, "/paths", s.resolvePath)
+	// Versions
+	s.handle(http.MethodPut, "/files/{file}/content", s.updateContent)
+	s.handle(http.MethodGet, "/files/{file}/versions", s.listVersions)
+	s.handle(http.MethodDelete, "/files/{file}/versions", s.pruneVersions)
+	s.handle(http.MethodPost, "/files/{file}/versions/{version}/restore", s.restoreVersion)
+	// Shares
+	s.handle(http.MethodGet, "/files/{file}/shares", s.shareWithWho)
+	s.handle(http.MethodPost, "/files/{file}/shares", s.shareFile)
+	s.handle(http.MethodPatch, "/files/{file}/shares/{user}", s.changeShareRole)
+	s.handle(http.MethodDelete, "/files/{file}/shares/{user}", s.unshareFile)
+	s.handle(http.MethodPatch, "/files/{file}/groups/{group}", s.changeShareRole)
+	s.handle(http.MethodDelete, "/files/{file}/groups/{group}", s.unshareFile)
+	s.handle(http.MethodGet, "/shared", s.shareWithMe)
+	// Links
+	s.handle(http.MethodPost, "/files/{file}/links", s.createShareLink)
+	s.handle(http.MethodGet, "/files/{file}/links", s.listShareLinks)
+	s.handle(http.MethodDelete, "/files/{file}/links/{link}", s.revokeShareLink)
+	s.handle(http.MethodPost, "/links/{token}", s.queryFileByLink)
+	// Groups
+	s.handle(http.MethodPost, "/groups", s.createGroup)
+	s.handle(http.MethodGet, "/groups", s.listGroups)
+	s.handle(http.MethodDelete, "/groups/{group}", s.deleteGroup)
+	s.handle(http.MethodGet, "/groups/{group}/members", s.listGroupMembers)
+	s.handle(http.MethodPut, "/groups/{group}/members/{user}", s.addGroupMember)
+	s.handle(http.MethodDelete, "/groups/{group}/members/{user}", s.removeGroupMember)
+	// Trash
+	s.handle(http.MethodGet, "/trash", s.listTrash)
+	s.handle(http.MethodDelete, "/trash", s.emptyTrash)
+	s.handle(http.MethodPost, "/trash/{file}/restore", s.restoreFile)
+	// Quotas
+	s.handle(http.MethodGet, "/usage", s.getUsage)
+	// Contents
+	s.handle(http.MethodPut, "/archives/{hash}", s.uploadArchive)
+	s.handle(http.MethodPost, "/uploads", s.startUpload)
+	s.handle(http.MethodPut, "/uploads/{session}/chunks/{number}", s.uploadChunk)
+	s.handle(http.MethodGet, "/uploads/{session}/missing", s.missingChunks)
+	s.handle(http.MethodPost, "/uploads/{session}/finalize", s.finalizeUpload)
+	// Administration
+	s.handle(http.MethodPost, "/admin/archives/{hash}/ready", s.markArchiveReady)
+	s.handle(http.MethodPost, "/admin/archives/collect", s.collectArchives)
+	s.handle(http.MethodPost, "/admin/uploads/expire", s.expireUploads)
+	s.handle(http.MethodPost, "/admin/trash/purge", s.purgeTrash)
+	s.handle(http.MethodPost, "/admin/shares/reap", s.reapExpiredShares)
+	s.handle(http.MethodPut, "/admin/quotas/{user}", s.setQuota)
+}
+
+func (s *Server) createFile(r *request) (status int, body any, err error) {
+	var cf controller.CreateFile
+	err = r.decode(&cf)
+	if err != nil {
+		return 0, nil, err
+	}
+	if cf.Filename == "" {
+		return 0, nil, fmt.Errorf("%w: filename is required", ErrBadRequest)
+	}
+	if cf.Hash == "" && cf.Size != 0 {
+		return 0, nil, fmt.Errorf("%w: size requires a hash", ErrBadRequest)
+	}
+	cf.OwnerUUID = r.User
+	file, err := s.Controller.CreateFile(&cf)
+	return http.StatusCreated, file, err
+}
+
+func (s *Server) listDirectory(r *request) (status int, body any, err error) {
+	var ld = controller.ListDirectory{
+		UserUUID: r.User,
+		SortBy:   controller.SortBy(r.URL.Query().Get("sortBy")),
+		Cursor:   r.URL.Query().Get("cursor"),
+	}
+	switch ld.SortBy {
+	case "", controller.SortByName, controller.SortBySize, controller.SortByCreatedAt:
+	default:
+		return 0, nil, fmt.Errorf("%w: unknown sort field %q", ErrBadRequest, ld.SortBy)
+	}
+	ld.ParentUUID, err = r.queryUUID("parent")
+	if err == nil {
+		ld.Descending, err = r.queryBool("descending")
+	}
+	if err == nil {
+		ld.Limit, err = r.queryInt("limit")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	dir, err := s.Controller.ListDirectory(&ld)
+	return http.StatusOK, dir, err
+}
+
+func (s *Server) queryFile(r *request) (status int, body any, err error) {
+	var qf = controller.QueryFile{UserUUID: r.User}
+	qf.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		qf.VersionUUID, err = r.queryUUID("version")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	archive, err := s.Controller.QueryFile(&qf)
+	return http.StatusOK, archive, err
+}
+
+func (s *Server) moveFile(r *request) (status int, body any, err error) {
+	var mf controller.MoveFile
+	err = r.decode(&mf)
+	if err != nil {
+		return 0, nil, err
+	}
+	if mf.NewName != nil && *mf.NewName == "" {
+		return 0, nil, fmt.Errorf("%w: name can't be empty", ErrBadRequest)
+	}
+	mf.OwnerUUID = r.User
+	mf.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.MoveFile(&mf)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) copyFile(r *request) (status int, body any, err error) {
+	var cf controller.CopyFile
+	err = r.decode(&cf)
+	if err != nil {
+		return 0, nil, err
+	}
+	if cf.NewName != nil && *cf.NewName == "" {
+		return 0, nil, fmt.Errorf("%w: name can't be empty", ErrBadRequest)
+	}
+	cf.UserUUID = r.User
+	cf.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	file, err := s.Controller.CopyFile(&cf)
+	return http.StatusCreated, file, err
+}
+
+func (s *Server) deleteFile(r *request) (status int, body any, err error) {
+	var df = controller.DeleteFile{OwnerUUID: r.User}
+	df.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		df.Permanent, err = r.queryBool("permanent")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.DeleteFile(&df)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) canReadFile(r *request) (status int, body any, err error) {
+	var crf = controller.CanReadFile{UserUUID: r.User}
+	crf.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.CanReadFile(&crf)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) getFile(r *request) (status int, body any, err error) {
+	var gf = controller.GetFile{UserUUID: r.User}
+	gf.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	file, err := s.Controller.GetFile(&gf)
+	return http.StatusOK, file, err
+}
+
+func (s *Server) tree(r *request) (status int, body any, err error) {
+	var t = controller.Tree{
+		UserUUID: r.User,
+		Filter:   controller.TreeFilter(r.URL.Query().Get("filter")),
+	}
+	switch t.Filter {
+	case "", controller.TreeAll, controller.TreeFiles, controller.TreeDirectories:
+	default:
+		return 0, nil, fmt.Errorf("%w: unknown filter %q", ErrBadRequest, t.Filter)
+	}
+	t.RootUUID, err = r.uuidParam("file")
+	if err == nil {
+		t.MaxDepth, err = r.queryInt("maxDepth")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	root, err := s.Controller.Tree(&t)
+	return http.StatusOK, root, err
+}
+
+type Path struct {
+	Path string `json:"path"`
+}
+
+func (s *Server) pathOf(r *request) (status int, body any, err error) {
+	var po = controller.PathOf{UserUUID: r.User}
+	po.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	filePath, err := s.Controller.PathOf(&po)
+	return http.StatusOK, Path{Path: filePath}, err
+}
+
+func (s *Server) resolvePath(r *request) (status int, body any, err error) {
+	var rp = controller.ResolvePath{
+		UserUUID: r.User,
+		Path:     r.URL.Query().Get("path"),
+	}
+	if rp.Path == "" {
+		return 0, nil, fmt.Errorf("%w: path is required", ErrBadRequest)
+	}
+	file, err := s.Controller.ResolvePath(&rp)
+	return http.StatusOK, file, err
+}
+
+func (s *Server) updateContent(r *request) (status int, body any, err error) {
+	var uc controller.UpdateContent
+	err = r.decode(&uc)
+	if err != nil {
+		return 0, nil, err
+	}
+	if uc.Hash == "" {
+		return 0, nil, fmt.Errorf("%w: hash is required", ErrBadRequest)
+	}
+	uc.UserUUID = r.User
+	uc.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	version, err := s.Controller.UpdateContent(&uc)
+	return http.StatusCreated, version, err
+}
+
+func (s *Server) listVersions(r *request) (status int, body any, err error) {
+	var lv = controller.ListVersions{UserUUID: r.User}
+	lv.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	versions, err := s.Controller.ListVersions(&lv)
+	return http.StatusOK, versions, err
+}
+
+func (s *Server) pruneVersions(r *request) (status int, body any, err error) {
+	var pv = controller.PruneVersions{UserUUID: r.User}
+	pv.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		pv.KeepLast, err = r.queryInt("keepLast")
+	}
+	if err == nil {
+		pv.OlderThan, err = r.queryDuration("olderThan")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	pruned, err := s.Controller.PruneVersions(&pv)
+	return http.StatusOK, pruned, err
+}
+
+func (s *Server) restoreVersion(r *request) (status int, body any, err error) {
+	var rv = controller.RestoreVersion{UserUUID: r.User}
+	rv.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		rv.VersionUUID, err = r.uuidParam("version")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	version, err := s.Controller.RestoreVersion(&rv)
+	return http.StatusCreated, version, err
+}
+
+func (s *Server) shareWithWho(r *request) (status int, body any, err error) {
+	var sww = controller.ShareWithWho{OwnerUUID: r.User}
+	sww.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	shared, err := s.Controller.ShareWithWho(&sww)
+	return http.StatusOK, shared, err
+}
+
+func (s *Server) shareFile(r *request) (status int, body any, err error) {
+	var sr controller.ShareRequest
+	err = r.decode(&sr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if sr.TargetUserUUID == r.User {
+		return 0, nil, fmt.Errorf("%w: can't share with yourself", ErrBadRequest)
+	}
+	sr.OwnerUUID = r.User
+	sr.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.ShareFile(&sr)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) changeShareRole(r *request) (status int, body any, err error) {
+	var sr controller.ShareRequest
+	err = r.decode(&sr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if sr.Role == "" {
+		return 0, nil, fmt.Errorf("%w: role is required", ErrBadRequest)
+	}
+	sr.OwnerUUID = r.User
+	sr.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		err = r.shareTarget(&sr)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.ChangeShareRole(&sr)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) unshareFile(r *request) (status int, body any, err error) {
+	var sr = controller.ShareRequest{OwnerUUID: r.User}
+	sr.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		err = r.shareTarget(&sr)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.UnshareFile(&sr)
+	return http.StatusNoContent, nil, err
+}
+
+// Targets the group of the route when present, or its user otherwise
+func (r *request) shareTarget(sr *controller.ShareRequest) (err error) {
+	if _, found := r.params["group"]; !found {
+		sr.TargetUserUUID, err = r.uuidParam("user")
+		return err
+	}
+	groupUUID, err := r.uuidParam("group")
+	if err == nil {
+		sr.TargetGroupUUID = &groupUUID
+	}
+	return err
+}
+
+func (s *Server) shareWithMe(r *request) (status int, body any, err error) {
+	var swm = controller.ShareWithMe{
+		UserUUID: r.User,
+		Cursor:   r.URL.Query().Get("cursor"),
+	}
+	swm.OwnerUUID, err = r.queryUUID("owner")
+	if err == nil {
+		swm.Descending, err = r.queryBool("descending")
+	}
+	if err == nil {
+		swm.Limit, err = r.queryInt("limit")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	shared, err := s.Controller.ShareWithMe(&swm)
+	return http.StatusOK, shared, err
+}
+
+func (s *Server) createShareLink(r *request) (status int, body any, err error) {
+	var csl controller.CreateShareLink
+	err = r.decode(&csl)
+	if err != nil {
+		return 0, nil, err
+	}
+	csl.UserUUID = r.User
+	csl.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	link, err := s.Controller.CreateShareLink(&csl)
+	return http.StatusCreated, link, err
+}
+
+func (s *Server) listShareLinks(r *request) (status int, body any, err error) {
+	var lsl = controller.ListShareLinks{UserUUID: r.User}
+	lsl.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	links, err := s.Controller.ListShareLinks(&lsl)
+	return http.StatusOK, links, err
+}
+
+func (s *Server) revokeShareLink(r *request) (status int, body any, err error) {
+	var rsl = controller.RevokeShareLink{UserUUID: r.User}
+	rsl.FileUUID, err = r.uuidParam("file")
+	if err == nil {
+		rsl.LinkUUID, err = r.uuidParam("link")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.RevokeShareLink(&rsl)
+	return http.StatusNoContent, nil, err
+}
+
+// Uses POST since the password travels in the body and every query counts as a download
+func (s *Server) queryFileByLink(r *request) (status int, body any, err error) {
+	var qfl controller.QueryFileByLink
+	err = r.decode(&qfl)
+	if err != nil {
+		return 0, nil, err
+	}
+	qfl.Token = r.params["token"]
+	archive, err := s.Controller.QueryFileByLink(&qfl)
+	return http.StatusOK, archive, err
+}
+
+func (s *Server) createGroup(r *request) (status int, body any, err error) {
+	var cg controller.CreateGroup
+	err = r.decode(&cg)
+	if err != nil {
+		return 0, nil, err
+	}
+	cg.OwnerUUID = r.User
+	group, err := s.Controller.CreateGroup(&cg)
+	return http.StatusCreated, group, err
+}
+
+func (s *Server) listGroups(r *request) (status int, body any, err error) {
+	var lg = controller.ListGroups{UserUUID: r.User}
+	groups, err := s.Controller.ListGroups(&lg)
+	return http.StatusOK, groups, err
+}
+
+func (s *Server) deleteGroup(r *request) (status int, body any, err error) {
+	var dg = controller.DeleteGroup{OwnerUUID: r.User}
+	dg.GroupUUID, err = r.uuidParam("group")
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.DeleteGroup(&dg)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) listGroupMembers(r *request) (status int, body any, err error) {
+	var lgm = controller.ListGroupMembers{UserUUID: r.User}
+	lgm.GroupUUID, err = r.uuidParam("group")
+	if err != nil {
+		return 0, nil, err
+	}
+	members, err := s.Controller.ListGroupMembers(&lgm)
+	return http.StatusOK, members, err
+}
+
+func (r *request) groupMember() (gmr controller.GroupMemberRequest, err error) {
+	gmr.OwnerUUID = r.User
+	gmr.GroupUUID, err = r.uuidParam("group")
+	if err == nil {
+		gmr.UserUUID, err = r.uuidParam("user")
+	}
+	return gmr, err
+}
+
+func (s *Server) addGroupMember(r *request) (status int, body any, err error) {
+	gmr, err := r.groupMember()
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.AddGroupMember(&gmr)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) removeGroupMember(r *request) (status int, body any, err error) {
+	gmr, err := r.groupMember()
+	if err != nil {
+		return 0, nil, err
+	}
+	err = s.Controller.RemoveGroupMember(&gmr)
+	return http.StatusNoContent, nil, err
+}
+
+func (s *Server) listTrash(r *request) (status int, body any, err error) {
+	var lt = controller.ListTrash{OwnerUUID: r.User}
+	files, err := s.Controller.ListTrash(&lt)
+	return http.StatusOK, files, err
+}
+
+func (s *Server) emptyTrash(r *request) (status int, body any, err error) {
+	var pt = controller.PurgeTrash{OwnerUUID: &r.User}
+	pt.Retention, err = r.queryDuration("olderThan")
+	if err != nil {
+		return 0, nil, err
+	}
+	purged, err := s.Controller.PurgeTrash(&pt)
+	return http.StatusOK, purged, err
+}
+
+func (s *Server) restoreFile(r *request) (status int, body any, err error) {
+	var rf = controller.RestoreFile{
+		OwnerUUID: r.User,
+		Conflict:  controller.ConflictPolicy(r.URL.Query().Get("conflict")),
+	}
+	rf.FileUUID, err = r.uuidParam("file")
+	if err != nil {
+		return 0, nil, err
+	}
+	file, err := s.Controller.RestoreFile(&rf)
+	return http.StatusOK, file, err
+}
+
+// The contents are the body of the request, so the size is its length
+func (s *Server) uploadArchive(r *request) (status int, body any, err error) {
+	if r.ContentLength < 0 {
+		return 0, nil, fmt.Errorf("%w: content length is required", ErrBadRequest)
+	}
+	var ua = controller.UploadArchive{
+		Hash:     r.params["hash"],
+		Size:     uint64(r.ContentLength),
+		Contents: r.Body,
+	}
+	archive, err := s.Controller.UploadArchive(&ua)
+	return http.StatusOK, archive, err
+}
+
+func (s *Server) startUpload(r *request) (status int, body any, err error) {
+	var su controller.StartUpload
+	err = r.decode(&su)
+	if err != nil {
+		return 0, nil, err
+	}
+	su.UserUUID = r.User
+	session, err := s.Controller.StartUpload(&su)
+	return http.StatusCreated, session, err
+}
+
+func (s *Server) uploadChunk(r *request) (status int, body any, err error) {
+	var uc = controller.UploadChunk{
+		UserUUID: r.User,
+		Contents: r.Body,
+	}
+	uc.SessionUUID, err = r.uuidParam("session")
+	if err != nil {
+		return 0, nil, err
+	}
+	number, err := strconv.ParseUint(r.params["number"], 10, 32)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: invalid number: %w", ErrBadRequest, err)
+	}
+	uc.Number = uint(number)
+	chunk, err := s.Controller.UploadChunk(&uc)
+	return http.StatusOK, chunk, err
+}
+
+type Missing struct {
+	Missing []uint `json:"missing"`
+}
+
+func (s *Server) missingChunks(r *request) (status int, body any, err error) {
+	var mc = controller.MissingChunks{UserUUID: r.User}
+	mc.SessionUUID, err = r.uuidParam("session")
+	if err != nil {
+		return 0, nil, err
+	}
+	missing, err := s.Controller.MissingChunks(&mc)
+	if missing == nil {
+		missing = []uint{}
+	}
+	return http.StatusOK, Missing{Missing: missing}, err
+}
+
+func (s *Server) finalizeUpload(r *request) (status int, body any, err error) {
+	var fu = controller.FinalizeUpload{UserUUID: r.User}
+	fu.SessionUUID, err = r.uuidParam("session")
+	if err != nil {
+		return 0, nil, err
+	}
+	archive, err := s.Controller.FinalizeUpload(&fu)
+	return http.StatusOK, archive, err
+}
+
+func (s *Server) markArchiveReady(r *request) (status int, body any, err error) {
+	size, err := strconv.ParseUint(r.URL.Query().Get("size"), 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: invalid size: %w", ErrBadRequest, err)
+	}
+	var mar = controller.MarkArchiveReady{
+		Hash: r.params["hash"],
+		Size: size,
+	}
+	archive, err := s.Controller.MarkArchiveReady(&mar)
+	return http.StatusOK, archive, err
+}
+
+func (s *Server) collectArchives(r *request) (status int, body any, err error) {
+	var ca controller.CollectArchives
+	ca.GracePeriod, err = r.queryDuration("gracePeriod")
+	if err != nil {
+		return 0, nil, err
+	}
+	collected, err := s.Controller.CollectArchives(&ca)
+	return http.StatusOK, collected, err
+}
+
+func (s *Server) expireUploads(r *request) (status int, body any, err error) {
+	var eu controller.ExpireUploads
+	eu.Expiration, err = r.queryDuration("expiration")
+	if err != nil {
+		return 0, nil, err
+	}
+	expired, err := s.Controller.ExpireUploads(&eu)
+	return http.StatusOK, expired, err
+}
+
+func (s *Server) purgeTrash(r *request) (status int, body any, err error) {
+	var pt = controller.PurgeTrash{
+		Retention: s.Controller.TrashRetention,
+	}
+	pt.OwnerUUID, err = r.queryUUID("owner")
+	if err == nil && r.URL.Query().Has("retention") {
+		pt.Retention, err = r.queryDuration("retention")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	purged, err := s.Controller.PurgeTrash(&pt)
+	return http.StatusOK, purged, err
+}
+
+func (s *Server) reapExpiredShares(r *request) (status int, body any, err error) {
+	reaped, err := s.Controller.ReapExpiredShares()
+	return http.StatusOK, reaped, err
+}
+
+func (s *Server) getUsage(r *request) (status int, body any, err error) {
+	var gu = controller.GetUsage{UserUUID: r.User}
+	usage, err := s.Controller.GetUsage(&gu)
+	return http.StatusOK, usage, err
+}
+
+func (s *Server) setQuota(r *request) (status int, body any, err error) {
+	var sq controller.SetQuota
+	err = r.decode(&sq)
+	if err != nil {
+		return 0, nil, err
+	}
+	sq.UserUUID, err = r.uuidParam("user")
+	if err != nil {
+		return 0, nil, err
+	}
+	usage, err := s.Controller.SetQuota(&sq)
+	return http.StatusOK, usage, err
+}
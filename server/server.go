@@ -0,0 +1,283 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"gorm.io/gorm"
+)
+
+// Header used by the gateway to forward the UUID of the authenticated user
+const UserHeader = "X-User-UUID"
+
+// Maximum size of the JSON bodies, contents are streamed and not affected by it
+const maxBodySize = 1 << 20
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("missing or invalid user")
+)
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+type request struct {
+	*http.Request
+	// Authenticated user, uuid.Nil for administrative and public routes
+	User   uuid.UUID
+	params map[string]string
+}
+
+// Handlers return the status and the body to be encoded as JSON.
+// A nil body results in an empty response
+type handler func(r *request) (status int, body any, err error)
+
+type route struct {
+	method   string
+	segments []string
+	// Administrative routes don't act on behalf of any user
+	admin bool
+	// Public routes are available to anonymous users, like the holders of a share link
+	public  bool
+	handler handler
+}
+
+// HTTP/JSON interface of the controller.
+// The server is intended to run behind the gateway, that authenticates the users
+// and must not expose the administrative routes under /admin
+type Server struct {
+	Controller *controller.Controller
+	routes     []route
+}
+
+func New(c *controller.Controller) (s *Server) {
+	s = &Server{Controller: c}
+	s.registerRoutes()
+	return s
+}
+
+func (s *Server) handle(method, pattern string, h handler) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		admin:    strings.HasPrefix(pattern, "/admin/"),
+		public:   strings.HasPrefix(pattern, "/links/"),
+		handler:  h,
+	})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		segments      = strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		pathMatched   bool
+		matched       *route
+		matchedParams map[string]string
+	)
+	for index := range s.routes {
+		params, ok := match(s.routes[index].segments, segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if s.routes[index].method == r.Method {
+			matched, matchedParams = &s.routes[index], params
+			break
+		}
+	}
+	if matched == nil {
+		if pathMatched {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		} else {
+			writeError(w, http.StatusNotFound, errors.New("route not found"))
+		}
+		return
+	}
+
+	var req = request{Request: r, params: matchedParams}
+	if !matched.admin && !matched.public {
+		user, err := uuid.Parse(r.Header.Get(UserHeader))
+		if err != nil || user == uuid.Nil {
+			writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		req.User = user
+	}
+
+	status, body, err := matched.handler(&req)
+	if err != nil {
+		status = statusOf(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+			err = errors.New("internal server error")
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, status, body)
+}
+
+func match(pattern, segments []string) (params map[string]string, ok bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params = make(map[string]string)
+	for index, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if segments[index] == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = segments[index]
+			continue
+		}
+		if segment != segments[index] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Maps the errors of the controller to the HTTP status codes
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrBadRequest),
+		errors.Is(err, controller.ErrNotDirectory),
+		errors.Is(err, controller.ErrIsDirectory),
+		errors.Is(err, controller.ErrInvalidCursor),
+		errors.Is(err, controller.ErrInvalidPath),
+		errors.Is(err, controller.ErrInvalidShare),
+		errors.Is(err, controller.ErrInvalidGroup),
+		errors.Is(err, controller.ErrInvalidMove),
+		errors.Is(err, controller.ErrInvalidConflictPolicy),
+		errors.Is(err, controller.ErrCopyTooLarge),
+		errors.Is(err, controller.ErrInvalidChunk),
+		errors.Is(err, blobstore.ErrInvalidHash),
+		errors.Is(err, blobstore.ErrHashMismatch),
+		errors.Is(err, blobstore.ErrSizeMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, controller.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, blobstore.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey),
+		errors.Is(err, controller.ErrNameConflict),
+		errors.Is(err, controller.ErrAmbiguousPath),
+		errors.Is(err, controller.ErrMoveCycle),
+		errors.Is(err, controller.ErrArchiveNotReady),
+		errors.Is(err, controller.ErrArchiveReady),
+		errors.Is(err, controller.ErrBlobMissing),
+		errors.Is(err, controller.ErrMissingChunks):
+		return http.StatusConflict
+	case errors.Is(err, controller.ErrLinkExpired),
+		errors.Is(err, controller.ErrDownloadLimit):
+		return http.StatusGone
+	case errors.Is(err, controller.ErrQuotaExceeded):
+		return http.StatusInsufficientStorage
+	case errors.Is(err, controller.ErrNoBlobStore):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Message: err.Error()})
+}
+
+// Decodes the JSON body into v, unknown fields are rejected
+func (r *request) decode(v any) (err error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: invalid body: %w", ErrBadRequest, err)
+	}
+	return nil
+}
+
+func (r *request) uuidParam(name string) (id uuid.UUID, err error) {
+	id, err = uuid.Parse(r.params[name])
+	if err != nil {
+		err = fmt.Errorf("%w: invalid %s: %w", ErrBadRequest, name, err)
+	}
+	return id, err
+}
+
+// Optional query parameters keep their zero value when missing
+
+func (r *request) queryUUID(name string) (id *uuid.UUID, err error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s: %w", ErrBadRequest, name, err)
+	}
+	return &parsed, nil
+}
+
+func (r *request) queryBool(name string) (b bool, err error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err = strconv.ParseBool(value)
+	if err != nil {
+		err = fmt.Errorf("%w: invalid %s: %w", ErrBadRequest, name, err)
+	}
+	return b, err
+}
+
+func (r *request) queryInt(name string) (n int, err error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err = strconv.Atoi(value)
+	if err == nil && n < 0 {
+		err = errors.New("negative value")
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: invalid %s: %w", ErrBadRequest, name, err)
+	}
+	return n, err
+}
+
+func (r *request) queryDuration(name string) (d time.Duration, err error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	d, err = time.ParseDuration(value)
+	if err == nil && d < 0 {
+		err = errors.New("negative duration")
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: invalid %s: %w", ErrBadRequest, name, err)
+	}
+	return d, err
+}
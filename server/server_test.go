@@ -0,0 +1,636 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/blobstore"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/controller"
+	"github.com/hawks-atlanta/fs-prototype/database"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T) (ts *httptest.Server, c *controller.Controller) {
+	assertions := assert.New(t)
+
+	c, err := controller.Default()
+	assertions.Nil(err)
+	t.Cleanup(func() { c.Close() })
+
+	c.Blobs, err = blobstore.New(t.TempDir())
+	assertions.Nil(err)
+
+	ts = httptest.NewServer(New(c))
+	t.Cleanup(ts.Close)
+	return ts, c
+}
+
+// Sends the request, body is encoded as JSON unless it is already a reader.
+// The response is decoded into out when given
+func doRequest(t *testing.T, ts *httptest.Server, method, path string, user uuid.UUID, body any, out any) (status int) {
+	assertions := assert.New(t)
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		buf, err := json.Marshal(b)
+		assertions.Nil(err)
+		reader = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	assertions.Nil(err)
+	if user != uuid.Nil {
+		req.Header.Set(UserHeader, user.String())
+	}
+	res, err := ts.Client().Do(req)
+	assertions.Nil(err)
+	defer res.Body.Close()
+
+	if out != nil {
+		err = json.NewDecoder(res.Body).Decode(out)
+		assertions.Nil(err)
+	}
+	return res.StatusCode
+}
+
+func TestServer_Files(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "docs"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+		assertions.Equal(owner, dir.OwnerUUID)
+
+		var (
+			contents = uuid.NewString()
+			file     models.File
+		)
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, controller.CreateFile{
+			Filename:        "notes.txt",
+			Hash:            utils.Hash(contents),
+			Size:            uint64(len(contents)),
+			ParentDirectory: &dir.UUID,
+		}, &file)
+		assertions.Equal(http.StatusCreated, status)
+
+		var listing controller.Directory
+		status = doRequest(t, ts, http.MethodGet, "/files?parent="+dir.UUID.String(), owner, nil, &listing)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(listing.Files, 1)
+		assertions.Equal("notes.txt", listing.Files[0].Name)
+
+		// Contents are not uploaded yet
+		status = doRequest(t, ts, http.MethodGet, "/files/"+file.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusConflict, status)
+
+		status = doRequest(t, ts, http.MethodPut, "/archives/"+utils.Hash(contents), owner, strings.NewReader(contents), nil)
+		assertions.Equal(http.StatusOK, status)
+
+		var archive models.Archive
+		status = doRequest(t, ts, http.MethodGet, "/files/"+file.UUID.String(), owner, nil, &archive)
+		assertions.Equal(http.StatusOK, status)
+		assertions.True(archive.IsReady)
+
+		var newName = "renamed.txt"
+		status = doRequest(t, ts, http.MethodPatch, "/files/"+file.UUID.String(), owner, controller.MoveFile{NewName: &newName}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+		status = doRequest(t, ts, http.MethodPatch, "/files/"+dir.UUID.String(), owner, controller.MoveFile{NewLocation: &dir.UUID}, nil)
+		assertions.Equal(http.StatusConflict, status)
+		status = doRequest(t, ts, http.MethodPatch, "/files/"+dir.UUID.String(), owner, controller.MoveFile{NewLocation: &file.UUID}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+		status = doRequest(t, ts, http.MethodPatch, "/files/"+dir.UUID.String(), owner, controller.MoveFile{NewLocation: &file.UUID, ToRoot: true}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		var p Path
+		status = doRequest(t, ts, http.MethodGet, "/files/"+file.UUID.String()+"/path", owner, nil, &p)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal("/docs/renamed.txt", p.Path)
+
+		var resolved models.File
+		status = doRequest(t, ts, http.MethodGet, "/paths?path=/docs/renamed.txt", owner, nil, &resolved)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal(file.UUID, resolved.UUID)
+
+		status = doRequest(t, ts, http.MethodDelete, "/files/"+file.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var trash []models.File
+		status = doRequest(t, ts, http.MethodGet, "/trash", owner, nil, &trash)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(trash, 1)
+
+		status = doRequest(t, ts, http.MethodPost, "/trash/"+file.UUID.String()+"/restore", owner, nil, nil)
+		assertions.Equal(http.StatusOK, status)
+
+		status = doRequest(t, ts, http.MethodDelete, "/files/"+file.UUID.String()+"?permanent=true", owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		// Unknown files can't be told apart from files without access
+		status = doRequest(t, ts, http.MethodGet, "/files/"+file.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+
+		status = doRequest(t, ts, http.MethodDelete, "/files/"+file.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNotFound, status)
+	})
+	t.Run("Conflict", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "docs"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		var cf = controller.CreateFile{
+			Filename:        "duplicated",
+			ParentDirectory: &dir.UUID,
+		}
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, cf, nil)
+		assertions.Equal(http.StatusCreated, status)
+
+		var e Error
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, cf, &e)
+		assertions.Equal(http.StatusConflict, status)
+		assertions.NotEmpty(e.Message)
+
+		var merged models.File
+		cf.Conflict = controller.ConflictMerge
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, cf, &merged)
+		assertions.Equal(http.StatusCreated, status)
+		assertions.Equal(cf.Filename, merged.Name)
+		cf.Conflict = "overwrite"
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, cf, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+	})
+	t.Run("Copy", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "docs"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		var copied models.File
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/copy", owner, controller.CopyFile{}, nil)
+		assertions.Equal(http.StatusConflict, status)
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/copy", owner, controller.CopyFile{Conflict: controller.ConflictRename}, &copied)
+		assertions.Equal(http.StatusCreated, status)
+		assertions.Equal("docs (1)", copied.Name)
+		assertions.NotEqual(dir.UUID, copied.UUID)
+
+		var empty = ""
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/copy", owner, controller.CopyFile{NewName: &empty}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/copy", uuid.New(), controller.CopyFile{}, nil)
+		assertions.Equal(http.StatusNotFound, status)
+	})
+	t.Run("Invalid requests", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": ""}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "a", "unknown": 1}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, strings.NewReader("{"), nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files/not-a-uuid", owner, nil, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files?sortBy=color", owner, nil, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files?limit=-1", owner, nil, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+	})
+	t.Run("Unauthorized", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+
+		status := doRequest(t, ts, http.MethodGet, "/files", uuid.Nil, nil, nil)
+		assertions.Equal(http.StatusUnauthorized, status)
+	})
+	t.Run("Unknown routes", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		status := doRequest(t, ts, http.MethodGet, "/folders", owner, nil, nil)
+		assertions.Equal(http.StatusNotFound, status)
+
+		status = doRequest(t, ts, http.MethodPut, "/files", owner, nil, nil)
+		assertions.Equal(http.StatusMethodNotAllowed, status)
+	})
+}
+
+func TestServer_Shares(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner     = uuid.New()
+			recipient = uuid.New()
+		)
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "shared"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String()+"/access", recipient, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetUserUUID: recipient}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		// Sharing twice
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetUserUUID: recipient}, nil)
+		assertions.Equal(http.StatusConflict, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String()+"/access", recipient, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var shared controller.SharedWithMe
+		status = doRequest(t, ts, http.MethodGet, "/shared?owner="+owner.String()+"&descending=true&limit=1", recipient, nil, &shared)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(shared.Entries, 1)
+		assertions.Equal(dir.UUID, shared.Entries[0].File.UUID)
+		status = doRequest(t, ts, http.MethodGet, "/shared?owner="+uuid.NewString(), recipient, nil, &shared)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(shared.Entries, 0)
+		status = doRequest(t, ts, http.MethodGet, "/shared?cursor=invalid", recipient, nil, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		var who []models.SharedFile
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String()+"/shares", owner, nil, &who)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(who, 1)
+
+		status = doRequest(t, ts, http.MethodDelete, "/files/"+dir.UUID.String()+"/shares/"+recipient.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String()+"/access", recipient, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+	})
+	t.Run("Permission denied", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var owner = uuid.New()
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "private"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", uuid.New(), controller.ShareRequest{TargetUserUUID: uuid.New()}, nil)
+		assertions.Equal(http.StatusForbidden, status)
+	})
+	t.Run("Roles", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner     = uuid.New()
+			recipient = uuid.New()
+		)
+
+		var dir models.File
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "shared"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetUserUUID: recipient, Role: "admin"}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetUserUUID: recipient}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var cf = controller.CreateFile{Filename: "new", ParentDirectory: &dir.UUID}
+		status = doRequest(t, ts, http.MethodPost, "/files", recipient, cf, nil)
+		assertions.Equal(http.StatusForbidden, status)
+
+		var path = "/files/" + dir.UUID.String() + "/shares/" + recipient.String()
+		status = doRequest(t, ts, http.MethodPatch, path, owner, map[string]any{}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		status = doRequest(t, ts, http.MethodPatch, path, owner, controller.ShareRequest{Role: models.RoleEditor}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var file models.File
+		status = doRequest(t, ts, http.MethodPost, "/files", recipient, cf, &file)
+		assertions.Equal(http.StatusCreated, status)
+		assertions.Equal(owner, file.OwnerUUID)
+
+		var who []models.SharedFile
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String()+"/shares", owner, nil, &who)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(who, 1)
+		assertions.Equal(models.RoleEditor, who[0].Role)
+	})
+	t.Run("Groups", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner  = uuid.New()
+			member = uuid.New()
+		)
+
+		var group models.Group
+		status := doRequest(t, ts, http.MethodPost, "/groups", owner, map[string]any{"name": ""}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+		status = doRequest(t, ts, http.MethodPost, "/groups", owner, map[string]any{"name": "team"}, &group)
+		assertions.Equal(http.StatusCreated, status)
+
+		var groupPath = "/groups/" + group.UUID.String()
+		status = doRequest(t, ts, http.MethodPut, groupPath+"/members/"+member.String(), member, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+		status = doRequest(t, ts, http.MethodPut, groupPath+"/members/"+member.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var members []models.GroupMember
+		status = doRequest(t, ts, http.MethodGet, groupPath+"/members", member, nil, &members)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(members, 1)
+
+		var dir models.File
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "team"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetGroupUUID: &group.UUID}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		var shared controller.SharedWithMe
+		status = doRequest(t, ts, http.MethodGet, "/shared", member, nil, &shared)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(shared.Entries, 1)
+		assertions.Equal(group.UUID, *shared.Entries[0].GroupUUID)
+
+		var sharePath = "/files/" + dir.UUID.String() + "/groups/" + group.UUID.String()
+		status = doRequest(t, ts, http.MethodPatch, sharePath, owner, controller.ShareRequest{Role: models.RoleEditor}, nil)
+		assertions.Equal(http.StatusNoContent, status)
+		var cf = controller.CreateFile{Filename: "new", ParentDirectory: &dir.UUID}
+		status = doRequest(t, ts, http.MethodPost, "/files", member, cf, nil)
+		assertions.Equal(http.StatusCreated, status)
+
+		status = doRequest(t, ts, http.MethodDelete, sharePath, owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String(), member, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+
+		status = doRequest(t, ts, http.MethodDelete, groupPath, owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+		status = doRequest(t, ts, http.MethodGet, groupPath+"/members", owner, nil, nil)
+		assertions.Equal(http.StatusNotFound, status)
+	})
+}
+
+func TestServer_Links(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner        = uuid.New()
+			contents     = "public contents"
+			maxDownloads = uint(1)
+			file         models.File
+		)
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, controller.CreateFile{
+			Filename: "public.txt",
+			Hash:     utils.Hash(contents),
+			Size:     uint64(len(contents)),
+		}, &file)
+		assertions.Equal(http.StatusCreated, status)
+		status = doRequest(t, ts, http.MethodPut, "/archives/"+utils.Hash(contents), owner, strings.NewReader(contents), nil)
+		assertions.Equal(http.StatusOK, status)
+
+		var link models.ShareLink
+		var linksPath = "/files/" + file.UUID.String() + "/links"
+		status = doRequest(t, ts, http.MethodPost, linksPath, uuid.New(), map[string]any{}, nil)
+		assertions.Equal(http.StatusForbidden, status)
+		status = doRequest(t, ts, http.MethodPost, linksPath, owner, controller.CreateShareLink{Password: "secret", MaxDownloads: &maxDownloads}, &link)
+		assertions.Equal(http.StatusCreated, status)
+
+		// Anonymous access
+		status = doRequest(t, ts, http.MethodPost, "/links/"+link.Token, uuid.Nil, map[string]any{}, nil)
+		assertions.Equal(http.StatusForbidden, status)
+		var archive models.Archive
+		status = doRequest(t, ts, http.MethodPost, "/links/"+link.Token, uuid.Nil, controller.QueryFileByLink{Password: "secret"}, &archive)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal(utils.Hash(contents), archive.Hash)
+		status = doRequest(t, ts, http.MethodPost, "/links/"+link.Token, uuid.Nil, controller.QueryFileByLink{Password: "secret"}, nil)
+		assertions.Equal(http.StatusGone, status)
+
+		var links []models.ShareLink
+		status = doRequest(t, ts, http.MethodGet, linksPath, owner, nil, &links)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(links, 1)
+		assertions.Equal(uint(1), links[0].Downloads)
+
+		status = doRequest(t, ts, http.MethodDelete, linksPath+"/"+link.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+		status = doRequest(t, ts, http.MethodPost, "/links/"+link.Token, uuid.Nil, controller.QueryFileByLink{Password: "secret"}, nil)
+		assertions.Equal(http.StatusNotFound, status)
+	})
+}
+
+func TestServer_Uploads(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner    = uuid.New()
+			size     = int(controller.MinChunkSize)
+			contents = strings.Repeat("0123456789", size/4)
+			file     models.File
+		)
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, controller.CreateFile{
+			Filename: "upload.txt",
+			Hash:     utils.Hash(contents),
+			Size:     uint64(len(contents)),
+		}, &file)
+		assertions.Equal(http.StatusCreated, status)
+
+		var session models.UploadSession
+		status = doRequest(t, ts, http.MethodPost, "/uploads", owner, controller.StartUpload{FileUUID: file.UUID, ChunkSize: controller.MinChunkSize}, &session)
+		assertions.Equal(http.StatusCreated, status)
+		assertions.Equal(uint(3), session.ChunkCount)
+
+		var prefix = "/uploads/" + session.UUID.String()
+		status = doRequest(t, ts, http.MethodPut, prefix+"/chunks/0", owner, strings.NewReader(contents[:size]), nil)
+		assertions.Equal(http.StatusOK, status)
+
+		var missing Missing
+		status = doRequest(t, ts, http.MethodGet, prefix+"/missing", owner, nil, &missing)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal([]uint{1, 2}, missing.Missing)
+
+		status = doRequest(t, ts, http.MethodPost, prefix+"/finalize", owner, nil, nil)
+		assertions.Equal(http.StatusConflict, status)
+
+		status = doRequest(t, ts, http.MethodPut, prefix+"/chunks/1", owner, strings.NewReader(contents[size:2*size]), nil)
+		assertions.Equal(http.StatusOK, status)
+		status = doRequest(t, ts, http.MethodPut, prefix+"/chunks/2", owner, strings.NewReader(contents[2*size:]), nil)
+		assertions.Equal(http.StatusOK, status)
+		status = doRequest(t, ts, http.MethodPut, prefix+"/chunks/3", owner, strings.NewReader("x"), nil)
+		assertions.Equal(http.StatusBadRequest, status)
+
+		var archive models.Archive
+		status = doRequest(t, ts, http.MethodPost, prefix+"/finalize", owner, nil, &archive)
+		assertions.Equal(http.StatusOK, status)
+		assertions.True(archive.IsReady)
+	})
+	t.Run("Hash mismatch", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner    = uuid.New()
+			contents = uuid.NewString()
+		)
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, controller.CreateFile{
+			Filename: "upload.txt",
+			Hash:     utils.Hash(contents),
+			Size:     uint64(len(contents)),
+		}, nil)
+		assertions.Equal(http.StatusCreated, status)
+
+		status = doRequest(t, ts, http.MethodPut, "/archives/"+utils.Hash(contents), owner, strings.NewReader(strings.ToUpper(contents)), nil)
+		assertions.Equal(http.StatusBadRequest, status)
+	})
+}
+
+func TestServer_Admin(t *testing.T) {
+	t.Run("Succeed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+
+		var collected controller.CollectedArchives
+		status := doRequest(t, ts, http.MethodPost, "/admin/archives/collect", uuid.Nil, nil, &collected)
+		assertions.Equal(http.StatusOK, status)
+
+		var expired controller.ExpiredUploads
+		status = doRequest(t, ts, http.MethodPost, "/admin/uploads/expire?expiration=48h", uuid.Nil, nil, &expired)
+		assertions.Equal(http.StatusOK, status)
+
+		status = doRequest(t, ts, http.MethodPost, "/admin/trash/purge?retention=forever", uuid.Nil, nil, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+	})
+	t.Run("Reap expired shares", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, c := newTestServer(t)
+		var (
+			owner     = uuid.New()
+			recipient = uuid.New()
+			dir       models.File
+		)
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "contract"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+
+		var expiresAt = time.Now().Add(-time.Minute)
+		status = doRequest(t, ts, http.MethodPost, "/files/"+dir.UUID.String()+"/shares", owner, controller.ShareRequest{TargetUserUUID: recipient, ExpiresAt: &expiresAt}, nil)
+		assertions.Equal(http.StatusBadRequest, status)
+		assertions.Nil(c.Store.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: recipient, ExpiresAt: &expiresAt}))
+
+		status = doRequest(t, ts, http.MethodGet, "/files/"+dir.UUID.String(), recipient, nil, nil)
+		assertions.Equal(http.StatusForbidden, status)
+
+		var reaped controller.ReapedShares
+		status = doRequest(t, ts, http.MethodPost, "/admin/shares/reap", uuid.Nil, nil, &reaped)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(reaped.Shares, 1)
+		assertions.Equal(recipient, reaped.Shares[0].UserUUID)
+	})
+	t.Run("Purge trash", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, c := newTestServer(t)
+		c.TrashRetention = time.Hour
+		var (
+			owner = uuid.New()
+			dir   models.File
+		)
+		status := doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "old"}, &dir)
+		assertions.Equal(http.StatusCreated, status)
+		status = doRequest(t, ts, http.MethodDelete, "/files/"+dir.UUID.String(), owner, nil, nil)
+		assertions.Equal(http.StatusNoContent, status)
+
+		// The retention of the controller applies unless another one is given
+		var purged []models.File
+		status = doRequest(t, ts, http.MethodPost, "/admin/trash/purge", uuid.Nil, nil, &purged)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Empty(purged)
+
+		trashed, err := c.Store.GetFile(dir.UUID)
+		assertions.Nil(err)
+		var trashedAt = time.Now().Add(-2 * time.Hour)
+		trashed.TrashedAt = &trashedAt
+		assertions.Nil(c.Store.SaveFile(&trashed))
+
+		status = doRequest(t, ts, http.MethodPost, "/admin/trash/purge", uuid.Nil, nil, &purged)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Len(purged, 1)
+		assertions.Equal(dir.UUID, purged[0].UUID)
+	})
+	t.Run("Quotas", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		ts, _ := newTestServer(t)
+		var (
+			owner = uuid.New()
+			usage controller.Usage
+		)
+		status := doRequest(t, ts, http.MethodPut, "/admin/quotas/"+owner.String(), uuid.Nil, map[string]any{"maxFiles": 1}, &usage)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal(owner, usage.UserUUID)
+		assertions.Equal(uint64(1), *usage.RemainingFiles)
+
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "docs"}, nil)
+		assertions.Equal(http.StatusCreated, status)
+		status = doRequest(t, ts, http.MethodPost, "/files", owner, map[string]any{"filename": "more"}, nil)
+		assertions.Equal(http.StatusInsufficientStorage, status)
+
+		status = doRequest(t, ts, http.MethodGet, "/usage", owner, nil, &usage)
+		assertions.Equal(http.StatusOK, status)
+		assertions.Equal(uint64(1), usage.Files)
+		assertions.Zero(*usage.RemainingFiles)
+	})
+}
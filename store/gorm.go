@@ -0,0 +1,812 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Relational implementation of the store, works with Postgres and SQLite
+type GORM struct {
+	DB *gorm.DB
+}
+
+var _ MetadataStore = (*GORM)(nil)
+
+// Migrates the schema of the index before using the database
+func NewGORM(db *gorm.DB) (s *GORM, err error) {
+	err = db.AutoMigrate(
+		&models.Archive{}, &models.File{}, &models.SharedFile{},
+		&models.FileVersion{}, &models.UploadSession{}, &models.UploadChunk{},
+		&models.Group{}, &models.GroupMember{}, &models.GroupShare{}, &models.ShareLink{},
+		&models.Quota{},
+	)
+	if err == nil {
+		// idx_unique_file never compares the NULL parents of the files in the root
+		err = db.Exec(
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_root_file ON files (owner_uuid, name) " +
+				"WHERE parent_uuid IS NULL AND trashed_at IS NULL",
+		).Error
+	}
+	s = &GORM{DB: db}
+	return s, err
+}
+
+func (s *GORM) Transaction(fn func(tx MetadataStore) error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&GORM{DB: tx})
+	})
+}
+
+func (s *GORM) Close() (err error) {
+	db, err := s.DB.DB()
+	if err == nil {
+		err = db.Close()
+	}
+	return err
+}
+
+func (s *GORM) GetFile(fileUUID uuid.UUID) (file models.File, err error) {
+	err = s.DB.
+		Where("uuid = ?", fileUUID).
+		First(&file).
+		Error
+	return file, err
+}
+
+func (s *GORM) FindChild(ownerUUID uuid.UUID, parentUUID *uuid.UUID, name string) (file models.File, err error) {
+	query := s.DB.Where("name = ? AND trashed_at IS NULL", name)
+	if parentUUID == nil {
+		query = query.Where("owner_uuid = ? AND parent_uuid IS NULL", ownerUUID)
+	} else {
+		query = query.Where("parent_uuid = ?", *parentUUID)
+	}
+	err = query.
+		First(&file).
+		Error
+	return file, err
+}
+
+func (s *GORM) ListChildren(lc *ListChildren) (files []models.File, err error) {
+	var column string
+	switch lc.SortBy {
+	case SortByName:
+		column = "files.name"
+	case SortBySize:
+		column = "COALESCE(archives.size, 0)"
+	case SortByCreatedAt:
+		column = "files.created_at"
+	default:
+		return nil, fmt.Errorf("unknown sort field: %s", lc.SortBy)
+	}
+
+	query := s.DB.
+		Preload("Archive").
+		Joins("LEFT JOIN archives ON archives.uuid = files.archive_uuid")
+	if lc.ParentUUID == nil {
+		query = query.Where("files.owner_uuid = ? AND files.parent_uuid IS NULL AND files.trashed_at IS NULL", lc.OwnerUUID)
+	} else {
+		query = query.Where("files.parent_uuid = ?", *lc.ParentUUID)
+	}
+
+	var (
+		order     = "ASC"
+		operation = ">"
+	)
+	if lc.Descending {
+		order = "DESC"
+		operation = "<"
+	}
+	if lc.After != nil {
+		var value any
+		switch lc.SortBy {
+		case SortByName:
+			value = lc.After.Name
+		case SortBySize:
+			value = lc.After.Size
+		case SortByCreatedAt:
+			value = lc.After.CreatedAt
+		}
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND files.uuid %[2]s ?))", column, operation),
+			value, value, lc.After.UUID,
+		)
+	}
+	if lc.Limit > 0 {
+		query = query.Limit(lc.Limit)
+	}
+	err = query.
+		Order(fmt.Sprintf("%s %s, files.uuid %s", column, order, order)).
+		Find(&files).
+		Error
+	return files, err
+}
+
+func (s *GORM) Ancestors(fileUUID uuid.UUID) (files []models.File, err error) {
+	var rows []struct {
+		models.File
+		Depth int `gorm:"column:depth"`
+	}
+	err = s.DB.Raw(
+		`WITH RECURSIVE file_hierarchy AS (
+			-- Base case: start with the requested file
+			SELECT files.*, 0 AS depth
+			FROM files
+			WHERE uuid = ?
+
+			UNION ALL
+
+			-- Recursive case: get the parent file of the current file
+			SELECT f.*, fh.depth + 1
+			FROM files f
+			JOIN file_hierarchy fh ON f.uuid = fh.parent_uuid
+		)
+		SELECT * FROM file_hierarchy
+		ORDER BY depth`,
+		fileUUID).
+		Scan(&rows).
+		Error
+	if err == nil && len(rows) == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	files = make([]models.File, 0, len(rows))
+	for _, row := range rows {
+		files = append(files, row.File)
+	}
+	return files, nil
+}
+
+func (s *GORM) ListAncestors(fileUUIDs []uuid.UUID) (hierarchies map[uuid.UUID][]models.File, err error) {
+	hierarchies = make(map[uuid.UUID][]models.File, len(fileUUIDs))
+	if len(fileUUIDs) == 0 {
+		return hierarchies, nil
+	}
+	var rows []struct {
+		models.File
+		OriginUUID uuid.UUID `gorm:"column:origin_uuid"`
+		Depth      int       `gorm:"column:depth"`
+	}
+	err = s.DB.Raw(
+		`WITH RECURSIVE file_hierarchy AS (
+			-- Base case: start with the requested files
+			SELECT files.*, files.uuid AS origin_uuid, 0 AS depth
+			FROM files
+			WHERE uuid IN ?
+
+			UNION ALL
+
+			-- Recursive case: get the parent file of the current file
+			SELECT f.*, fh.origin_uuid, fh.depth + 1
+			FROM files f
+			JOIN file_hierarchy fh ON f.uuid = fh.parent_uuid
+		)
+		SELECT * FROM file_hierarchy
+		ORDER BY origin_uuid, depth`,
+		fileUUIDs).
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		hierarchies[row.OriginUUID] = append(hierarchies[row.OriginUUID], row.File)
+	}
+	return hierarchies, nil
+}
+
+func (s *GORM) Subtree(st *Subtree) (files []models.File, err error) {
+	var rows []struct {
+		models.File
+		Depth int `gorm:"column:depth"`
+	}
+	var query = `WITH RECURSIVE file_tree AS (
+			-- Base case: the requested directory
+			SELECT files.*, 0 AS depth
+			FROM files
+			WHERE uuid = ?
+
+			UNION ALL
+
+			-- Recursive case: children of the current level
+			SELECT f.*, ft.depth + 1
+			FROM files f
+			JOIN file_tree ft ON f.parent_uuid = ft.uuid
+			WHERE (? <= 0 OR ft.depth < ?)
+				AND (? = FALSE OR f.archive_uuid IS NULL)
+		)
+		SELECT * FROM file_tree
+		ORDER BY depth, name`
+	var args = []any{st.RootUUID, st.MaxDepth, st.MaxDepth, st.DirectoriesOnly}
+	if st.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, st.Limit)
+	}
+	err = s.DB.Raw(query, args...).
+		Scan(&rows).
+		Error
+	if err == nil && len(rows) == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach archive information to files
+	var archiveUUIDs []uuid.UUID
+	for _, row := range rows {
+		if row.ArchiveUUID != nil {
+			archiveUUIDs = append(archiveUUIDs, *row.ArchiveUUID)
+		}
+	}
+	var archives = make(map[uuid.UUID]*models.Archive, len(archiveUUIDs))
+	if len(archiveUUIDs) > 0 {
+		var found []models.Archive
+		err = s.DB.
+			Where("uuid IN ?", archiveUUIDs).
+			Find(&found).
+			Error
+		if err != nil {
+			return nil, err
+		}
+		for index := range found {
+			archives[found[index].UUID] = &found[index]
+		}
+	}
+	files = make([]models.File, 0, len(rows))
+	for _, row := range rows {
+		if row.ArchiveUUID != nil {
+			row.Archive = archives[*row.ArchiveUUID]
+		}
+		files = append(files, row.File)
+	}
+	return files, nil
+}
+
+func (s *GORM) ListTrashed(lt *ListTrashed) (files []models.File, err error) {
+	query := s.DB.
+		Preload("Archive").
+		Where("trashed_at IS NOT NULL")
+	if lt.OwnerUUID != nil {
+		query = query.Where("owner_uuid = ?", *lt.OwnerUUID)
+	}
+	if lt.Before != nil {
+		query = query.Where("trashed_at <= ?", *lt.Before)
+	}
+	err = query.
+		Order("trashed_at DESC").
+		Find(&files).
+		Error
+	return files, err
+}
+
+func (s *GORM) FilesWithArchives(archiveUUIDs []uuid.UUID) (files []models.File, err error) {
+	err = s.DB.
+		Where("archive_uuid IN ?", archiveUUIDs).
+		Find(&files).
+		Error
+	return files, err
+}
+
+func (s *GORM) GetFiles(fileUUIDs []uuid.UUID) (files []models.File, err error) {
+	err = s.DB.
+		Preload("Archive").
+		Where("uuid IN ?", fileUUIDs).
+		Find(&files).
+		Error
+	return files, err
+}
+
+func (s *GORM) CreateFile(file *models.File) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(file).
+		Error
+}
+
+func (s *GORM) SaveFile(file *models.File) error {
+	result := s.DB.
+		Model(file).
+		Select("*").
+		Omit("created_at", clause.Associations).
+		Updates(file)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (s *GORM) DeleteFiles(fileUUIDs ...uuid.UUID) error {
+	if len(fileUUIDs) == 0 {
+		return nil
+	}
+	return s.DB.
+		Where("uuid IN ?", fileUUIDs).
+		Delete(&models.File{}).
+		Error
+}
+
+func (s *GORM) CreateShare(share *models.SharedFile) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(share).
+		Error
+}
+
+func (s *GORM) SaveShare(share *models.SharedFile) error {
+	result := s.DB.
+		Model(share).
+		Select("*").
+		Omit("created_at", clause.Associations).
+		Updates(share)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (s *GORM) DeleteShare(fileUUID, userUUID uuid.UUID) error {
+	return s.DB.
+		Where("file_uuid = ? AND user_uuid = ?", fileUUID, userUUID).
+		Delete(&models.SharedFile{}).
+		Error
+}
+
+func (s *GORM) ListShares(ls *ListShares) (shares []models.SharedFile, err error) {
+	query := s.DB.
+		Select("shared_files.*").
+		Joins("JOIN files ON files.uuid = shared_files.file_uuid")
+	if ls.UserUUID != nil {
+		query = query.Where("shared_files.user_uuid = ?", *ls.UserUUID)
+	}
+	if ls.FileUUIDs != nil {
+		query = query.Where("shared_files.file_uuid IN ?", ls.FileUUIDs)
+	}
+	if ls.Name != "" {
+		query = query.Where("files.name = ?", ls.Name)
+	}
+	if ls.OwnerUUID != nil {
+		query = query.Where("files.owner_uuid = ?", *ls.OwnerUUID)
+	}
+	if ls.SkipTrashed {
+		query = query.Where("files.trashed_at IS NULL")
+	}
+	if ls.ActiveAt != nil {
+		query = query.Where("shared_files.expires_at IS NULL OR shared_files.expires_at > ?", *ls.ActiveAt)
+	}
+	if ls.ExpiredAt != nil {
+		query = query.Where("shared_files.expires_at <= ?", *ls.ExpiredAt)
+	}
+	err = sortedByCreation(query, "shared_files", ls.Descending, ls.After, ls.Limit).
+		Find(&shares).
+		Error
+	return shares, err
+}
+
+// Sorts the records of the table by creation, resuming after the position and stopping after the limit when set
+func sortedByCreation(query *gorm.DB, table string, descending bool, after *Position, limit int) *gorm.DB {
+	var (
+		order     = "ASC"
+		operation = ">"
+	)
+	if descending {
+		order = "DESC"
+		operation = "<"
+	}
+	if after != nil {
+		query = query.Where(
+			fmt.Sprintf("(%[1]s.created_at %[2]s ? OR (%[1]s.created_at = ? AND %[1]s.uuid %[2]s ?))", table, operation),
+			after.CreatedAt, after.CreatedAt, after.UUID,
+		)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return query.Order(fmt.Sprintf("%[1]s.created_at %[2]s, %[1]s.uuid %[2]s", table, order))
+}
+
+func (s *GORM) GetGroup(groupUUID uuid.UUID) (group models.Group, err error) {
+	err = s.DB.
+		Where("uuid = ?", groupUUID).
+		First(&group).
+		Error
+	return group, err
+}
+
+func (s *GORM) ListGroups(lg *ListGroups) (groups []models.Group, err error) {
+	query := s.DB.Model(&models.Group{})
+	if lg.OwnerUUID != nil {
+		query = query.Where("owner_uuid = ?", *lg.OwnerUUID)
+	}
+	if lg.MemberUUID != nil {
+		query = query.Where(
+			"uuid IN (?)",
+			s.DB.Model(&models.GroupMember{}).Select("group_uuid").Where("user_uuid = ?", *lg.MemberUUID),
+		)
+	}
+	err = query.
+		Order("name ASC").
+		Order("uuid ASC").
+		Find(&groups).
+		Error
+	return groups, err
+}
+
+func (s *GORM) CreateGroup(group *models.Group) error {
+	return s.DB.
+		Create(group).
+		Error
+}
+
+func (s *GORM) DeleteGroup(groupUUID uuid.UUID) error {
+	return s.DB.
+		Where("uuid = ?", groupUUID).
+		Delete(&models.Group{}).
+		Error
+}
+
+func (s *GORM) ListGroupMembers(groupUUID uuid.UUID) (members []models.GroupMember, err error) {
+	err = s.DB.
+		Where("group_uuid = ?", groupUUID).
+		Order("created_at ASC").
+		Order("uuid ASC").
+		Find(&members).
+		Error
+	return members, err
+}
+
+func (s *GORM) CreateGroupMember(member *models.GroupMember) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(member).
+		Error
+}
+
+func (s *GORM) DeleteGroupMember(groupUUID, userUUID uuid.UUID) error {
+	return s.DB.
+		Where("group_uuid = ? AND user_uuid = ?", groupUUID, userUUID).
+		Delete(&models.GroupMember{}).
+		Error
+}
+
+func (s *GORM) CreateGroupShare(share *models.GroupShare) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(share).
+		Error
+}
+
+func (s *GORM) SaveGroupShare(share *models.GroupShare) error {
+	result := s.DB.
+		Model(share).
+		Select("*").
+		Omit("created_at", clause.Associations).
+		Updates(share)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (s *GORM) DeleteGroupShare(fileUUID, groupUUID uuid.UUID) error {
+	return s.DB.
+		Where("file_uuid = ? AND group_uuid = ?", fileUUID, groupUUID).
+		Delete(&models.GroupShare{}).
+		Error
+}
+
+func (s *GORM) ListGroupShares(ls *ListGroupShares) (shares []models.GroupShare, err error) {
+	query := s.DB.
+		Select("group_shares.*").
+		Joins("JOIN files ON files.uuid = group_shares.file_uuid")
+	if ls.GroupUUIDs != nil {
+		query = query.Where("group_shares.group_uuid IN ?", ls.GroupUUIDs)
+	}
+	if ls.FileUUIDs != nil {
+		query = query.Where("group_shares.file_uuid IN ?", ls.FileUUIDs)
+	}
+	if ls.Name != "" {
+		query = query.Where("files.name = ?", ls.Name)
+	}
+	if ls.OwnerUUID != nil {
+		query = query.Where("files.owner_uuid = ?", *ls.OwnerUUID)
+	}
+	if ls.SkipTrashed {
+		query = query.Where("files.trashed_at IS NULL")
+	}
+	if ls.ActiveAt != nil {
+		query = query.Where("group_shares.expires_at IS NULL OR group_shares.expires_at > ?", *ls.ActiveAt)
+	}
+	if ls.ExpiredAt != nil {
+		query = query.Where("group_shares.expires_at <= ?", *ls.ExpiredAt)
+	}
+	err = sortedByCreation(query, "group_shares", ls.Descending, ls.After, ls.Limit).
+		Find(&shares).
+		Error
+	return shares, err
+}
+
+func (s *GORM) FindShareLink(tokenHash string) (link models.ShareLink, err error) {
+	err = s.DB.
+		Where("token_hash = ?", tokenHash).
+		First(&link).
+		Error
+	return link, err
+}
+
+func (s *GORM) ListShareLinks(fileUUID uuid.UUID) (links []models.ShareLink, err error) {
+	err = s.DB.
+		Where("file_uuid = ?", fileUUID).
+		Order("created_at ASC").
+		Order("uuid ASC").
+		Find(&links).
+		Error
+	return links, err
+}
+
+func (s *GORM) CreateShareLink(link *models.ShareLink) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(link).
+		Error
+}
+
+func (s *GORM) DeleteShareLink(fileUUID, linkUUID uuid.UUID) error {
+	return s.DB.
+		Where("file_uuid = ? AND uuid = ?", fileUUID, linkUUID).
+		Delete(&models.ShareLink{}).
+		Error
+}
+
+// The limit is checked by the update itself so concurrent downloads can't exceed it
+func (s *GORM) CountShareLinkDownload(linkUUID uuid.UUID) error {
+	result := s.DB.
+		Model(&models.ShareLink{}).
+		Where("uuid = ? AND (max_downloads IS NULL OR downloads < max_downloads)", linkUUID).
+		Update("downloads", gorm.Expr("downloads + 1"))
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (s *GORM) GetArchive(archiveUUID uuid.UUID) (archive models.Archive, err error) {
+	err = s.DB.
+		Where("uuid = ?", archiveUUID).
+		First(&archive).
+		Error
+	return archive, err
+}
+
+func (s *GORM) FindArchive(hash string, size uint64) (archive models.Archive, err error) {
+	err = s.DB.
+		Where("hash = ? AND size = ?", hash, size).
+		First(&archive).
+		Error
+	return archive, err
+}
+
+// Refreshing the update time on conflict locks the row until the transaction ends,
+// this prevents CollectArchives from removing an archive that is about to be referenced
+func (s *GORM) TouchArchive(hash string, size uint64) (archive models.Archive, err error) {
+	archive = models.Archive{
+		Hash: hash,
+		Size: size,
+	}
+	err = s.DB.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "hash"}, {Name: "size"}},
+			DoUpdates: clause.Assignments(map[string]any{"updated_at": time.Now()}),
+		}).
+		Create(&archive).
+		Error
+	if err != nil {
+		return archive, err
+	}
+	return s.FindArchive(hash, size)
+}
+
+func (s *GORM) SaveArchive(archive *models.Archive) error {
+	result := s.DB.
+		Model(archive).
+		Select("*").
+		Omit("created_at").
+		UpdateColumns(archive)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (s *GORM) StaleArchives(before time.Time) (archives []models.Archive, err error) {
+	err = s.DB.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("is_ready = ? AND updated_at < ?", false, before).
+		Find(&archives).
+		Error
+	return archives, err
+}
+
+func (s *GORM) DeleteArchives(archiveUUIDs ...uuid.UUID) error {
+	if len(archiveUUIDs) == 0 {
+		return nil
+	}
+	return s.DB.
+		Where("uuid IN ?", archiveUUIDs).
+		Delete(&models.Archive{}).
+		Error
+}
+
+func (s *GORM) CollectArchives(before time.Time) (archives []models.Archive, err error) {
+	err = s.DB.
+		Clauses(clause.Returning{}).
+		Where("updated_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM files WHERE files.archive_uuid = archives.uuid)").
+		Where("NOT EXISTS (SELECT 1 FROM file_versions WHERE file_versions.archive_uuid = archives.uuid)").
+		Delete(&archives).
+		Error
+	return archives, err
+}
+
+func (s *GORM) GetVersion(versionUUID uuid.UUID) (version models.FileVersion, err error) {
+	err = s.DB.
+		Where("uuid = ?", versionUUID).
+		First(&version).
+		Error
+	return version, err
+}
+
+func (s *GORM) ListVersions(fileUUID uuid.UUID) (versions []models.FileVersion, err error) {
+	err = s.DB.
+		Preload("Archive").
+		Where("file_uuid = ?", fileUUID).
+		Order("number DESC").
+		Find(&versions).
+		Error
+	return versions, err
+}
+
+func (s *GORM) CreateVersion(version *models.FileVersion) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(version).
+		Error
+}
+
+func (s *GORM) DeleteVersions(versionUUIDs ...uuid.UUID) error {
+	if len(versionUUIDs) == 0 {
+		return nil
+	}
+	return s.DB.
+		Where("uuid IN ?", versionUUIDs).
+		Delete(&models.FileVersion{}).
+		Error
+}
+
+func (s *GORM) GetUploadSession(sessionUUID uuid.UUID) (session models.UploadSession, err error) {
+	err = s.DB.
+		Preload("Archive").
+		Where("uuid = ?", sessionUUID).
+		First(&session).
+		Error
+	return session, err
+}
+
+func (s *GORM) FindUploadSession(ownerUUID, fileUUID, archiveUUID uuid.UUID) (session models.UploadSession, err error) {
+	err = s.DB.
+		Preload("Archive").
+		Where("owner_uuid = ? AND file_uuid = ? AND archive_uuid = ?", ownerUUID, fileUUID, archiveUUID).
+		First(&session).
+		Error
+	return session, err
+}
+
+func (s *GORM) UploadSessionsOf(archiveUUIDs []uuid.UUID) (sessions []models.UploadSession, err error) {
+	err = s.DB.
+		Where("archive_uuid IN ?", archiveUUIDs).
+		Find(&sessions).
+		Error
+	return sessions, err
+}
+
+func (s *GORM) CreateUploadSession(session *models.UploadSession) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Create(session).
+		Error
+}
+
+func (s *GORM) DeleteUploadSession(sessionUUID uuid.UUID) error {
+	return s.DB.
+		Where("uuid = ?", sessionUUID).
+		Delete(&models.UploadSession{}).
+		Error
+}
+
+func (s *GORM) TouchUploadChunk(chunk *models.UploadChunk) error {
+	return s.DB.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "session_uuid"}, {Name: "number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"updated_at"}),
+		}).
+		Create(chunk).
+		Error
+}
+
+func (s *GORM) UploadedChunks(sessionUUID uuid.UUID) (numbers []uint, err error) {
+	err = s.DB.
+		Model(&models.UploadChunk{}).
+		Where("session_uuid = ?", sessionUUID).
+		Order("number").
+		Pluck("number", &numbers).
+		Error
+	return numbers, err
+}
+
+func (s *GORM) DeleteUploadChunks(sessionUUID uuid.UUID) error {
+	return s.DB.
+		Where("session_uuid = ?", sessionUUID).
+		Delete(&models.UploadChunk{}).
+		Error
+}
+
+func (s *GORM) GetQuota(userUUID uuid.UUID) (quota models.Quota, err error) {
+	err = s.DB.
+		Where("user_uuid = ?", userUUID).
+		First(&quota).
+		Error
+	return quota, err
+}
+
+func (s *GORM) SaveQuota(quota *models.Quota) error {
+	err := s.DB.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"max_bytes", "max_files", "updated_at"}),
+		}).
+		Create(quota).
+		Error
+	if err != nil {
+		return err
+	}
+	*quota, err = s.GetQuota(quota.UserUUID)
+	return err
+}
+
+func (s *GORM) LockQuota(userUUID uuid.UUID) (quota models.Quota, err error) {
+	// The row must exist to be locked, users without quota get one keeping the defaults
+	err = s.DB.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_uuid"}},
+			DoNothing: true,
+		}).
+		Create(&models.Quota{UserUUID: userUUID}).
+		Error
+	if err != nil {
+		return quota, err
+	}
+	err = s.DB.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_uuid = ?", userUUID).
+		First(&quota).
+		Error
+	return quota, err
+}
+
+func (s *GORM) Usage(ownerUUID uuid.UUID) (usage Usage, err error) {
+	err = s.DB.
+		Model(&models.File{}).
+		Select("COUNT(*) AS files, COALESCE(SUM(archives.size), 0) AS bytes").
+		Joins("LEFT JOIN archives ON archives.uuid = files.archive_uuid").
+		Where("files.owner_uuid = ?", ownerUUID).
+		Scan(&usage).
+		Error
+	return usage, err
+}
@@ -0,0 +1,1227 @@
+package store
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"gorm.io/gorm"
+)
+
+// Records of the in-memory store, associations are never stored
+type memoryState struct {
+	files    map[uuid.UUID]models.File
+	shares   map[uuid.UUID]models.SharedFile
+	archives map[uuid.UUID]models.Archive
+	versions map[uuid.UUID]models.FileVersion
+	sessions map[uuid.UUID]models.UploadSession
+	chunks   map[uuid.UUID]models.UploadChunk
+	groups   map[uuid.UUID]models.Group
+	members  map[uuid.UUID]models.GroupMember
+	// Shares of groups
+	grants map[uuid.UUID]models.GroupShare
+	links  map[uuid.UUID]models.ShareLink
+	quotas map[uuid.UUID]models.Quota
+}
+
+func (ms *memoryState) clone() memoryState {
+	return memoryState{
+		files:    maps.Clone(ms.files),
+		shares:   maps.Clone(ms.shares),
+		archives: maps.Clone(ms.archives),
+		versions: maps.Clone(ms.versions),
+		sessions: maps.Clone(ms.sessions),
+		chunks:   maps.Clone(ms.chunks),
+		groups:   maps.Clone(ms.groups),
+		members:  maps.Clone(ms.members),
+		grants:   maps.Clone(ms.grants),
+		links:    maps.Clone(ms.links),
+		quotas:   maps.Clone(ms.quotas),
+	}
+}
+
+// Store that keeps the index in memory, intended for tests and for services embedding the controller.
+// It is safe for concurrent use, transactions are serialized and run against a snapshot
+// that is restored when they fail
+type Memory struct {
+	mutex *sync.Mutex
+	state *memoryState
+	// Set for the stores received by Transaction, which already hold the lock
+	inTransaction bool
+}
+
+var _ MetadataStore = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{
+		mutex: &sync.Mutex{},
+		state: &memoryState{
+			files:    map[uuid.UUID]models.File{},
+			shares:   map[uuid.UUID]models.SharedFile{},
+			archives: map[uuid.UUID]models.Archive{},
+			versions: map[uuid.UUID]models.FileVersion{},
+			sessions: map[uuid.UUID]models.UploadSession{},
+			chunks:   map[uuid.UUID]models.UploadChunk{},
+			groups:   map[uuid.UUID]models.Group{},
+			members:  map[uuid.UUID]models.GroupMember{},
+			grants:   map[uuid.UUID]models.GroupShare{},
+			links:    map[uuid.UUID]models.ShareLink{},
+			quotas:   map[uuid.UUID]models.Quota{},
+		},
+	}
+}
+
+// Usage: defer m.lock()()
+func (m *Memory) lock() (unlock func()) {
+	if m.inTransaction {
+		return func() {}
+	}
+	m.mutex.Lock()
+	return m.mutex.Unlock
+}
+
+func (m *Memory) Transaction(fn func(tx MetadataStore) error) (err error) {
+	defer m.lock()()
+	var snapshot = m.state.clone()
+	defer func() {
+		if err != nil {
+			*m.state = snapshot
+		}
+	}()
+	err = fn(&Memory{mutex: m.mutex, state: m.state, inTransaction: true})
+	return err
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+// Assigns the fields gorm fills on creation
+func prepareModel(model *models.Model) {
+	if model.UUID == uuid.Nil {
+		model.UUID = uuid.New()
+	}
+	var now = time.Now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = now
+	}
+}
+
+func copyUUID(u *uuid.UUID) *uuid.UUID {
+	if u == nil {
+		return nil
+	}
+	var c = *u
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	var c = *t
+	return &c
+}
+
+// Drops the associations and detaches the pointers from the caller
+func storedFile(file *models.File) models.File {
+	var stored = *file
+	stored.Parent = nil
+	stored.Archive = nil
+	stored.ParentUUID = copyUUID(file.ParentUUID)
+	stored.ArchiveUUID = copyUUID(file.ArchiveUUID)
+	stored.CreatorUUID = copyUUID(file.CreatorUUID)
+	stored.TrashedAt = copyTime(file.TrashedAt)
+	stored.TrashedFromUUID = copyUUID(file.TrashedFromUUID)
+	return stored
+}
+
+// Copies the stored file attaching its archive
+func (m *Memory) loadFile(stored models.File) models.File {
+	var file = storedFile(&stored)
+	if file.ArchiveUUID != nil {
+		if archive, found := m.state.archives[*file.ArchiveUUID]; found {
+			file.Archive = &archive
+		}
+	}
+	return file
+}
+
+func (m *Memory) GetFile(fileUUID uuid.UUID) (file models.File, err error) {
+	defer m.lock()()
+	stored, found := m.state.files[fileUUID]
+	if !found {
+		return file, gorm.ErrRecordNotFound
+	}
+	file = storedFile(&stored)
+	return file, nil
+}
+
+func (m *Memory) FindChild(ownerUUID uuid.UUID, parentUUID *uuid.UUID, name string) (file models.File, err error) {
+	defer m.lock()()
+	for _, stored := range m.state.files {
+		if stored.Name == name && stored.TrashedAt == nil && m.inDirectory(&stored, ownerUUID, parentUUID) {
+			return storedFile(&stored), nil
+		}
+	}
+	return file, gorm.ErrRecordNotFound
+}
+
+// Reports if the file is a child of the directory, or of the root of the owner when the parent is nil
+func (m *Memory) inDirectory(file *models.File, ownerUUID uuid.UUID, parentUUID *uuid.UUID) bool {
+	if parentUUID == nil {
+		return file.ParentUUID == nil && file.OwnerUUID == ownerUUID && file.TrashedAt == nil
+	}
+	return file.ParentUUID != nil && *file.ParentUUID == *parentUUID
+}
+
+func (m *Memory) ListChildren(lc *ListChildren) (files []models.File, err error) {
+	defer m.lock()()
+	var key func(file *models.File) any
+	switch lc.SortBy {
+	case SortByName:
+		key = func(file *models.File) any { return file.Name }
+	case SortBySize:
+		key = func(file *models.File) any {
+			if file.Archive == nil {
+				return uint64(0)
+			}
+			return file.Archive.Size
+		}
+	case SortByCreatedAt:
+		key = func(file *models.File) any { return file.CreatedAt }
+	default:
+		return nil, fmt.Errorf("unknown sort field: %s", lc.SortBy)
+	}
+	// Negative when a goes before b in ascending order
+	var compare = func(a, b *models.File) int {
+		var result int
+		switch aKey := key(a).(type) {
+		case string:
+			result = strings.Compare(aKey, key(b).(string))
+		case uint64:
+			result = cmp.Compare(aKey, key(b).(uint64))
+		case time.Time:
+			result = aKey.Compare(key(b).(time.Time))
+		}
+		if result == 0 {
+			result = strings.Compare(a.UUID.String(), b.UUID.String())
+		}
+		if lc.Descending {
+			result = -result
+		}
+		return result
+	}
+
+	var after *models.File
+	if lc.After != nil {
+		after = &models.File{
+			Model: models.Model{UUID: lc.After.UUID, CreatedAt: lc.After.CreatedAt},
+			Name:  lc.After.Name,
+		}
+		if lc.After.Size > 0 {
+			after.Archive = &models.Archive{Size: lc.After.Size}
+		}
+	}
+	for _, stored := range m.state.files {
+		if !m.inDirectory(&stored, lc.OwnerUUID, lc.ParentUUID) {
+			continue
+		}
+		file := m.loadFile(stored)
+		if after != nil && compare(&file, after) <= 0 {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return compare(&files[i], &files[j]) < 0
+	})
+	if lc.Limit > 0 && len(files) > lc.Limit {
+		files = files[:lc.Limit]
+	}
+	return files, nil
+}
+
+func (m *Memory) Ancestors(fileUUID uuid.UUID) (files []models.File, err error) {
+	defer m.lock()()
+	files = m.ancestors(fileUUID)
+	if len(files) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return files, nil
+}
+
+func (m *Memory) ListAncestors(fileUUIDs []uuid.UUID) (hierarchies map[uuid.UUID][]models.File, err error) {
+	defer m.lock()()
+	hierarchies = make(map[uuid.UUID][]models.File, len(fileUUIDs))
+	for _, fileUUID := range fileUUIDs {
+		if files := m.ancestors(fileUUID); len(files) > 0 {
+			hierarchies[fileUUID] = files
+		}
+	}
+	return hierarchies, nil
+}
+
+// Files from the given one up to the root, the caller must hold the lock
+func (m *Memory) ancestors(fileUUID uuid.UUID) (files []models.File) {
+	var current = &fileUUID
+	for current != nil {
+		stored, found := m.state.files[*current]
+		if !found {
+			break
+		}
+		files = append(files, storedFile(&stored))
+		current = stored.ParentUUID
+	}
+	return files
+}
+
+func (m *Memory) Subtree(st *Subtree) (files []models.File, err error) {
+	defer m.lock()()
+	root, found := m.state.files[st.RootUUID]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var children = map[uuid.UUID][]models.File{}
+	for _, stored := range m.state.files {
+		if stored.ParentUUID != nil {
+			children[*stored.ParentUUID] = append(children[*stored.ParentUUID], stored)
+		}
+	}
+	var level = []models.File{root}
+	for depth := 0; len(level) > 0; depth++ {
+		sort.Slice(level, func(i, j int) bool {
+			return level[i].Name < level[j].Name
+		})
+		var next []models.File
+		for _, stored := range level {
+			if st.Limit > 0 && len(files) == st.Limit {
+				return files, nil
+			}
+			files = append(files, m.loadFile(stored))
+			if st.MaxDepth > 0 && depth >= st.MaxDepth {
+				continue
+			}
+			for _, child := range children[stored.UUID] {
+				if !st.DirectoriesOnly || child.ArchiveUUID == nil {
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+	return files, nil
+}
+
+func (m *Memory) ListTrashed(lt *ListTrashed) (files []models.File, err error) {
+	defer m.lock()()
+	for _, stored := range m.state.files {
+		if stored.TrashedAt == nil ||
+			(lt.OwnerUUID != nil && stored.OwnerUUID != *lt.OwnerUUID) ||
+			(lt.Before != nil && stored.TrashedAt.After(*lt.Before)) {
+			continue
+		}
+		files = append(files, m.loadFile(stored))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].TrashedAt.After(*files[j].TrashedAt)
+	})
+	return files, nil
+}
+
+func (m *Memory) GetFiles(fileUUIDs []uuid.UUID) (files []models.File, err error) {
+	defer m.lock()()
+	for fileUUID := range uuidSet(fileUUIDs) {
+		if stored, found := m.state.files[fileUUID]; found {
+			files = append(files, m.loadFile(stored))
+		}
+	}
+	return files, nil
+}
+
+func (m *Memory) FilesWithArchives(archiveUUIDs []uuid.UUID) (files []models.File, err error) {
+	defer m.lock()()
+	var wanted = uuidSet(archiveUUIDs)
+	for _, stored := range m.state.files {
+		if stored.ArchiveUUID != nil && wanted[*stored.ArchiveUUID] {
+			files = append(files, storedFile(&stored))
+		}
+	}
+	return files, nil
+}
+
+func uuidSet(uuids []uuid.UUID) map[uuid.UUID]bool {
+	var set = make(map[uuid.UUID]bool, len(uuids))
+	for _, u := range uuids {
+		set[u] = true
+	}
+	return set
+}
+
+// Enforces the constraints of the files table. Like in SQL, only the files in the root
+// that are not in the trash collide with each other
+func (m *Memory) checkFile(file *models.File) error {
+	if file.ParentUUID != nil {
+		if _, found := m.state.files[*file.ParentUUID]; !found {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	for _, stored := range m.state.files {
+		if stored.UUID != file.UUID && stored.OwnerUUID == file.OwnerUUID && stored.Name == file.Name &&
+			sameDirectory(&stored, file) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if file.ArchiveUUID != nil {
+		if _, found := m.state.archives[*file.ArchiveUUID]; !found {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	return nil
+}
+
+// Whether both files are in the same directory, or both in the root outside the trash
+func sameDirectory(a, b *models.File) bool {
+	if a.ParentUUID == nil || b.ParentUUID == nil {
+		return a.ParentUUID == nil && b.ParentUUID == nil && a.TrashedAt == nil && b.TrashedAt == nil
+	}
+	return *a.ParentUUID == *b.ParentUUID
+}
+
+func (m *Memory) CreateFile(file *models.File) error {
+	defer m.lock()()
+	prepareModel(&file.Model)
+	if _, found := m.state.files[file.UUID]; found {
+		return gorm.ErrDuplicatedKey
+	}
+	err := m.checkFile(file)
+	if err != nil {
+		return err
+	}
+	m.state.files[file.UUID] = storedFile(file)
+	return nil
+}
+
+func (m *Memory) SaveFile(file *models.File) error {
+	defer m.lock()()
+	stored, found := m.state.files[file.UUID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	err := m.checkFile(file)
+	if err != nil {
+		return err
+	}
+	file.CreatedAt = stored.CreatedAt
+	file.UpdatedAt = time.Now()
+	m.state.files[file.UUID] = storedFile(file)
+	return nil
+}
+
+func (m *Memory) DeleteFiles(fileUUIDs ...uuid.UUID) error {
+	defer m.lock()()
+	m.deleteFiles(uuidSet(fileUUIDs))
+	return nil
+}
+
+// Removes the files and, in cascade, everything depending on them
+func (m *Memory) deleteFiles(fileUUIDs map[uuid.UUID]bool) {
+	for len(fileUUIDs) > 0 {
+		for fileUUID := range fileUUIDs {
+			delete(m.state.files, fileUUID)
+		}
+		for key, share := range m.state.shares {
+			if fileUUIDs[share.FileUUID] {
+				delete(m.state.shares, key)
+			}
+		}
+		for key, grant := range m.state.grants {
+			if fileUUIDs[grant.FileUUID] {
+				delete(m.state.grants, key)
+			}
+		}
+		for key, link := range m.state.links {
+			if fileUUIDs[link.FileUUID] {
+				delete(m.state.links, key)
+			}
+		}
+		for key, version := range m.state.versions {
+			if fileUUIDs[version.FileUUID] {
+				delete(m.state.versions, key)
+			}
+		}
+		var sessions = map[uuid.UUID]bool{}
+		for key, session := range m.state.sessions {
+			if fileUUIDs[session.FileUUID] {
+				sessions[key] = true
+			}
+		}
+		m.deleteSessions(sessions)
+
+		var children = map[uuid.UUID]bool{}
+		for key, file := range m.state.files {
+			if file.ParentUUID != nil && fileUUIDs[*file.ParentUUID] {
+				children[key] = true
+			}
+		}
+		fileUUIDs = children
+	}
+}
+
+func (m *Memory) deleteSessions(sessionUUIDs map[uuid.UUID]bool) {
+	for sessionUUID := range sessionUUIDs {
+		delete(m.state.sessions, sessionUUID)
+	}
+	for key, chunk := range m.state.chunks {
+		if sessionUUIDs[chunk.SessionUUID] {
+			delete(m.state.chunks, key)
+		}
+	}
+}
+
+// Drops the associations and detaches the pointers from the caller
+func storedShare(share *models.SharedFile) models.SharedFile {
+	var stored = *share
+	stored.File = nil
+	stored.GroupUUID = nil
+	stored.SharerUUID = copyUUID(share.SharerUUID)
+	stored.ExpiresAt = copyTime(share.ExpiresAt)
+	return stored
+}
+
+// Drops the associations and detaches the pointers from the caller
+func storedGroupShare(share *models.GroupShare) models.GroupShare {
+	var stored = *share
+	stored.Group = nil
+	stored.File = nil
+	stored.SharerUUID = copyUUID(share.SharerUUID)
+	stored.ExpiresAt = copyTime(share.ExpiresAt)
+	return stored
+}
+
+// Whether a share with the given expiration passes the filters of the listings
+func expirationMatches(expiresAt, activeAt, expiredAt *time.Time) bool {
+	if activeAt != nil && expiresAt != nil && !expiresAt.After(*activeAt) {
+		return false
+	}
+	if expiredAt != nil && (expiresAt == nil || expiresAt.After(*expiredAt)) {
+		return false
+	}
+	return true
+}
+
+func (m *Memory) CreateShare(share *models.SharedFile) error {
+	defer m.lock()()
+	prepareModel(&share.Model)
+	if share.Role == "" {
+		share.Role = models.RoleViewer
+	}
+	if _, found := m.state.files[share.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, stored := range m.state.shares {
+		if stored.UUID == share.UUID || (stored.FileUUID == share.FileUUID && stored.UserUUID == share.UserUUID) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.state.shares[share.UUID] = storedShare(share)
+	return nil
+}
+
+func (m *Memory) SaveShare(share *models.SharedFile) error {
+	defer m.lock()()
+	stored, found := m.state.shares[share.UUID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	if _, found := m.state.files[share.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, other := range m.state.shares {
+		if other.UUID != share.UUID && other.FileUUID == share.FileUUID && other.UserUUID == share.UserUUID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	share.CreatedAt = stored.CreatedAt
+	share.UpdatedAt = time.Now()
+	m.state.shares[share.UUID] = storedShare(share)
+	return nil
+}
+
+func (m *Memory) DeleteShare(fileUUID, userUUID uuid.UUID) error {
+	defer m.lock()()
+	for key, share := range m.state.shares {
+		if share.FileUUID == fileUUID && share.UserUUID == userUUID {
+			delete(m.state.shares, key)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) ListShares(ls *ListShares) (shares []models.SharedFile, err error) {
+	defer m.lock()()
+	var fileUUIDs map[uuid.UUID]bool
+	if ls.FileUUIDs != nil {
+		fileUUIDs = uuidSet(ls.FileUUIDs)
+	}
+	for _, share := range m.state.shares {
+		file := m.state.files[share.FileUUID]
+		if (ls.UserUUID != nil && share.UserUUID != *ls.UserUUID) ||
+			(fileUUIDs != nil && !fileUUIDs[share.FileUUID]) ||
+			(ls.Name != "" && file.Name != ls.Name) ||
+			(ls.OwnerUUID != nil && file.OwnerUUID != *ls.OwnerUUID) ||
+			(ls.SkipTrashed && file.TrashedAt != nil) ||
+			!expirationMatches(share.ExpiresAt, ls.ActiveAt, ls.ExpiredAt) {
+			continue
+		}
+		shares = append(shares, storedShare(&share))
+	}
+	return pageByCreation(shares, func(share *models.SharedFile) *models.Model { return &share.Model }, ls.Descending, ls.After, ls.Limit), nil
+}
+
+// Sorts the records by creation, keeping the ones after the position up to the limit when set
+func pageByCreation[T any](records []T, model func(record *T) *models.Model, descending bool, after *Position, limit int) []T {
+	var compare = func(a, b *models.Model) int {
+		var result = a.CreatedAt.Compare(b.CreatedAt)
+		if result == 0 {
+			result = strings.Compare(a.UUID.String(), b.UUID.String())
+		}
+		if descending {
+			result = -result
+		}
+		return result
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return compare(model(&records[i]), model(&records[j])) < 0
+	})
+	if after != nil {
+		var position = models.Model{UUID: after.UUID, CreatedAt: after.CreatedAt}
+		var start = sort.Search(len(records), func(i int) bool {
+			return compare(model(&records[i]), &position) > 0
+		})
+		records = records[start:]
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
+
+func (m *Memory) GetGroup(groupUUID uuid.UUID) (group models.Group, err error) {
+	defer m.lock()()
+	group, found := m.state.groups[groupUUID]
+	if !found {
+		return group, gorm.ErrRecordNotFound
+	}
+	return group, nil
+}
+
+func (m *Memory) ListGroups(lg *ListGroups) (groups []models.Group, err error) {
+	defer m.lock()()
+	var membership map[uuid.UUID]bool
+	if lg.MemberUUID != nil {
+		membership = map[uuid.UUID]bool{}
+		for _, member := range m.state.members {
+			if member.UserUUID == *lg.MemberUUID {
+				membership[member.GroupUUID] = true
+			}
+		}
+	}
+	for _, group := range m.state.groups {
+		if (lg.OwnerUUID != nil && group.OwnerUUID != *lg.OwnerUUID) ||
+			(membership != nil && !membership[group.UUID]) {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].UUID.String() < groups[j].UUID.String()
+	})
+	return groups, nil
+}
+
+func (m *Memory) CreateGroup(group *models.Group) error {
+	defer m.lock()()
+	prepareModel(&group.Model)
+	for _, stored := range m.state.groups {
+		if stored.UUID == group.UUID || (stored.OwnerUUID == group.OwnerUUID && stored.Name == group.Name) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.state.groups[group.UUID] = *group
+	return nil
+}
+
+func (m *Memory) DeleteGroup(groupUUID uuid.UUID) error {
+	defer m.lock()()
+	delete(m.state.groups, groupUUID)
+	for key, member := range m.state.members {
+		if member.GroupUUID == groupUUID {
+			delete(m.state.members, key)
+		}
+	}
+	for key, grant := range m.state.grants {
+		if grant.GroupUUID == groupUUID {
+			delete(m.state.grants, key)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) ListGroupMembers(groupUUID uuid.UUID) (members []models.GroupMember, err error) {
+	defer m.lock()()
+	for _, member := range m.state.members {
+		if member.GroupUUID == groupUUID {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UUID.String() < members[j].UUID.String()
+	})
+	return members, nil
+}
+
+func (m *Memory) CreateGroupMember(member *models.GroupMember) error {
+	defer m.lock()()
+	prepareModel(&member.Model)
+	if _, found := m.state.groups[member.GroupUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, stored := range m.state.members {
+		if stored.UUID == member.UUID || (stored.GroupUUID == member.GroupUUID && stored.UserUUID == member.UserUUID) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	var stored = *member
+	stored.Group = nil
+	m.state.members[member.UUID] = stored
+	return nil
+}
+
+func (m *Memory) DeleteGroupMember(groupUUID, userUUID uuid.UUID) error {
+	defer m.lock()()
+	for key, member := range m.state.members {
+		if member.GroupUUID == groupUUID && member.UserUUID == userUUID {
+			delete(m.state.members, key)
+		}
+	}
+	return nil
+}
+
+// Checks the references and the unique index of the share
+func (m *Memory) checkGroupShare(share *models.GroupShare) error {
+	if _, found := m.state.groups[share.GroupUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, found := m.state.files[share.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, other := range m.state.grants {
+		if other.UUID != share.UUID && other.FileUUID == share.FileUUID && other.GroupUUID == share.GroupUUID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	return nil
+}
+
+func (m *Memory) CreateGroupShare(share *models.GroupShare) error {
+	defer m.lock()()
+	prepareModel(&share.Model)
+	if share.Role == "" {
+		share.Role = models.RoleViewer
+	}
+	if _, found := m.state.grants[share.UUID]; found {
+		return gorm.ErrDuplicatedKey
+	}
+	err := m.checkGroupShare(share)
+	if err != nil {
+		return err
+	}
+	m.state.grants[share.UUID] = storedGroupShare(share)
+	return nil
+}
+
+func (m *Memory) SaveGroupShare(share *models.GroupShare) error {
+	defer m.lock()()
+	stored, found := m.state.grants[share.UUID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	err := m.checkGroupShare(share)
+	if err != nil {
+		return err
+	}
+	share.CreatedAt = stored.CreatedAt
+	share.UpdatedAt = time.Now()
+	m.state.grants[share.UUID] = storedGroupShare(share)
+	return nil
+}
+
+func (m *Memory) DeleteGroupShare(fileUUID, groupUUID uuid.UUID) error {
+	defer m.lock()()
+	for key, grant := range m.state.grants {
+		if grant.FileUUID == fileUUID && grant.GroupUUID == groupUUID {
+			delete(m.state.grants, key)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) ListGroupShares(ls *ListGroupShares) (shares []models.GroupShare, err error) {
+	defer m.lock()()
+	var groupUUIDs, fileUUIDs map[uuid.UUID]bool
+	if ls.GroupUUIDs != nil {
+		groupUUIDs = uuidSet(ls.GroupUUIDs)
+	}
+	if ls.FileUUIDs != nil {
+		fileUUIDs = uuidSet(ls.FileUUIDs)
+	}
+	for _, grant := range m.state.grants {
+		file := m.state.files[grant.FileUUID]
+		if (groupUUIDs != nil && !groupUUIDs[grant.GroupUUID]) ||
+			(fileUUIDs != nil && !fileUUIDs[grant.FileUUID]) ||
+			(ls.Name != "" && file.Name != ls.Name) ||
+			(ls.OwnerUUID != nil && file.OwnerUUID != *ls.OwnerUUID) ||
+			(ls.SkipTrashed && file.TrashedAt != nil) ||
+			!expirationMatches(grant.ExpiresAt, ls.ActiveAt, ls.ExpiredAt) {
+			continue
+		}
+		shares = append(shares, storedGroupShare(&grant))
+	}
+	return pageByCreation(shares, func(share *models.GroupShare) *models.Model { return &share.Model }, ls.Descending, ls.After, ls.Limit), nil
+}
+
+func (m *Memory) FindShareLink(tokenHash string) (link models.ShareLink, err error) {
+	defer m.lock()()
+	for _, link := range m.state.links {
+		if link.TokenHash == tokenHash {
+			return storedShareLink(&link), nil
+		}
+	}
+	return link, gorm.ErrRecordNotFound
+}
+
+func (m *Memory) ListShareLinks(fileUUID uuid.UUID) (links []models.ShareLink, err error) {
+	defer m.lock()()
+	for _, link := range m.state.links {
+		if link.FileUUID == fileUUID {
+			links = append(links, storedShareLink(&link))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.Before(links[j].CreatedAt)
+		}
+		return links[i].UUID.String() < links[j].UUID.String()
+	})
+	return links, nil
+}
+
+// Drops the associations and detaches the pointers from the caller
+func storedShareLink(link *models.ShareLink) models.ShareLink {
+	var stored = *link
+	stored.File = nil
+	stored.Token = ""
+	stored.ExpiresAt = copyTime(link.ExpiresAt)
+	if link.MaxDownloads != nil {
+		var maxDownloads = *link.MaxDownloads
+		stored.MaxDownloads = &maxDownloads
+	}
+	return stored
+}
+
+func (m *Memory) CreateShareLink(link *models.ShareLink) error {
+	defer m.lock()()
+	prepareModel(&link.Model)
+	if _, found := m.state.files[link.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, stored := range m.state.links {
+		if stored.UUID == link.UUID || stored.TokenHash == link.TokenHash {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.state.links[link.UUID] = storedShareLink(link)
+	return nil
+}
+
+func (m *Memory) DeleteShareLink(fileUUID, linkUUID uuid.UUID) error {
+	defer m.lock()()
+	if link, found := m.state.links[linkUUID]; found && link.FileUUID == fileUUID {
+		delete(m.state.links, linkUUID)
+	}
+	return nil
+}
+
+func (m *Memory) CountShareLinkDownload(linkUUID uuid.UUID) error {
+	defer m.lock()()
+	link, found := m.state.links[linkUUID]
+	if !found || (link.MaxDownloads != nil && link.Downloads >= *link.MaxDownloads) {
+		return gorm.ErrRecordNotFound
+	}
+	link.Downloads++
+	link.UpdatedAt = time.Now()
+	m.state.links[linkUUID] = link
+	return nil
+}
+
+func (m *Memory) GetArchive(archiveUUID uuid.UUID) (archive models.Archive, err error) {
+	defer m.lock()()
+	archive, found := m.state.archives[archiveUUID]
+	if !found {
+		return archive, gorm.ErrRecordNotFound
+	}
+	return archive, nil
+}
+
+func (m *Memory) findArchive(hash string, size uint64) (archive models.Archive, err error) {
+	for _, archive := range m.state.archives {
+		if archive.Hash == hash && archive.Size == size {
+			return archive, nil
+		}
+	}
+	return archive, gorm.ErrRecordNotFound
+}
+
+func (m *Memory) FindArchive(hash string, size uint64) (archive models.Archive, err error) {
+	defer m.lock()()
+	return m.findArchive(hash, size)
+}
+
+func (m *Memory) TouchArchive(hash string, size uint64) (archive models.Archive, err error) {
+	defer m.lock()()
+	archive, err = m.findArchive(hash, size)
+	if err == nil {
+		archive.UpdatedAt = time.Now()
+	} else {
+		archive = models.Archive{
+			Hash: hash,
+			Size: size,
+		}
+		prepareModel(&archive.Model)
+	}
+	m.state.archives[archive.UUID] = archive
+	return archive, nil
+}
+
+func (m *Memory) SaveArchive(archive *models.Archive) error {
+	defer m.lock()()
+	stored, found := m.state.archives[archive.UUID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	if other, err := m.findArchive(archive.Hash, archive.Size); err == nil && other.UUID != archive.UUID {
+		return gorm.ErrDuplicatedKey
+	}
+	archive.CreatedAt = stored.CreatedAt
+	m.state.archives[archive.UUID] = *archive
+	return nil
+}
+
+func (m *Memory) StaleArchives(before time.Time) (archives []models.Archive, err error) {
+	defer m.lock()()
+	for _, archive := range m.state.archives {
+		if !archive.IsReady && archive.UpdatedAt.Before(before) {
+			archives = append(archives, archive)
+		}
+	}
+	return archives, nil
+}
+
+func (m *Memory) DeleteArchives(archiveUUIDs ...uuid.UUID) error {
+	defer m.lock()()
+	m.deleteArchives(uuidSet(archiveUUIDs))
+	return nil
+}
+
+func (m *Memory) deleteArchives(archiveUUIDs map[uuid.UUID]bool) {
+	for archiveUUID := range archiveUUIDs {
+		delete(m.state.archives, archiveUUID)
+	}
+	var files = map[uuid.UUID]bool{}
+	for key, file := range m.state.files {
+		if file.ArchiveUUID != nil && archiveUUIDs[*file.ArchiveUUID] {
+			files[key] = true
+		}
+	}
+	m.deleteFiles(files)
+	for key, version := range m.state.versions {
+		if archiveUUIDs[version.ArchiveUUID] {
+			delete(m.state.versions, key)
+		}
+	}
+	var sessions = map[uuid.UUID]bool{}
+	for key, session := range m.state.sessions {
+		if archiveUUIDs[session.ArchiveUUID] {
+			sessions[key] = true
+		}
+	}
+	m.deleteSessions(sessions)
+}
+
+func (m *Memory) CollectArchives(before time.Time) (archives []models.Archive, err error) {
+	defer m.lock()()
+	var referenced = map[uuid.UUID]bool{}
+	for _, file := range m.state.files {
+		if file.ArchiveUUID != nil {
+			referenced[*file.ArchiveUUID] = true
+		}
+	}
+	for _, version := range m.state.versions {
+		referenced[version.ArchiveUUID] = true
+	}
+	var collected = map[uuid.UUID]bool{}
+	for _, archive := range m.state.archives {
+		if !referenced[archive.UUID] && archive.UpdatedAt.Before(before) {
+			archives = append(archives, archive)
+			collected[archive.UUID] = true
+		}
+	}
+	m.deleteArchives(collected)
+	return archives, nil
+}
+
+func (m *Memory) GetVersion(versionUUID uuid.UUID) (version models.FileVersion, err error) {
+	defer m.lock()()
+	version, found := m.state.versions[versionUUID]
+	if !found {
+		return version, gorm.ErrRecordNotFound
+	}
+	return version, nil
+}
+
+func (m *Memory) ListVersions(fileUUID uuid.UUID) (versions []models.FileVersion, err error) {
+	defer m.lock()()
+	for _, version := range m.state.versions {
+		if version.FileUUID != fileUUID {
+			continue
+		}
+		if archive, found := m.state.archives[version.ArchiveUUID]; found {
+			version.Archive = &archive
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Number > versions[j].Number
+	})
+	return versions, nil
+}
+
+func (m *Memory) CreateVersion(version *models.FileVersion) error {
+	defer m.lock()()
+	prepareModel(&version.Model)
+	if _, found := m.state.files[version.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, found := m.state.archives[version.ArchiveUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for _, stored := range m.state.versions {
+		if stored.UUID == version.UUID || (stored.FileUUID == version.FileUUID && stored.Number == version.Number) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	var stored = *version
+	stored.File = nil
+	stored.Archive = nil
+	m.state.versions[version.UUID] = stored
+	return nil
+}
+
+func (m *Memory) DeleteVersions(versionUUIDs ...uuid.UUID) error {
+	defer m.lock()()
+	for _, versionUUID := range versionUUIDs {
+		delete(m.state.versions, versionUUID)
+	}
+	return nil
+}
+
+// Copies the stored session attaching its archive
+func (m *Memory) loadSession(session models.UploadSession) models.UploadSession {
+	if archive, found := m.state.archives[session.ArchiveUUID]; found {
+		session.Archive = &archive
+	}
+	return session
+}
+
+func (m *Memory) GetUploadSession(sessionUUID uuid.UUID) (session models.UploadSession, err error) {
+	defer m.lock()()
+	session, found := m.state.sessions[sessionUUID]
+	if !found {
+		return session, gorm.ErrRecordNotFound
+	}
+	return m.loadSession(session), nil
+}
+
+func (m *Memory) FindUploadSession(ownerUUID, fileUUID, archiveUUID uuid.UUID) (session models.UploadSession, err error) {
+	defer m.lock()()
+	for _, session := range m.state.sessions {
+		if session.OwnerUUID == ownerUUID && session.FileUUID == fileUUID && session.ArchiveUUID == archiveUUID {
+			return m.loadSession(session), nil
+		}
+	}
+	return session, gorm.ErrRecordNotFound
+}
+
+func (m *Memory) UploadSessionsOf(archiveUUIDs []uuid.UUID) (sessions []models.UploadSession, err error) {
+	defer m.lock()()
+	var wanted = uuidSet(archiveUUIDs)
+	for _, session := range m.state.sessions {
+		if wanted[session.ArchiveUUID] {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *Memory) CreateUploadSession(session *models.UploadSession) error {
+	defer m.lock()()
+	prepareModel(&session.Model)
+	if _, found := m.state.sessions[session.UUID]; found {
+		return gorm.ErrDuplicatedKey
+	}
+	if _, found := m.state.files[session.FileUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, found := m.state.archives[session.ArchiveUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	var stored = *session
+	stored.File = nil
+	stored.Archive = nil
+	m.state.sessions[session.UUID] = stored
+	return nil
+}
+
+func (m *Memory) DeleteUploadSession(sessionUUID uuid.UUID) error {
+	defer m.lock()()
+	m.deleteSessions(map[uuid.UUID]bool{sessionUUID: true})
+	return nil
+}
+
+func (m *Memory) TouchUploadChunk(chunk *models.UploadChunk) error {
+	defer m.lock()()
+	if _, found := m.state.sessions[chunk.SessionUUID]; !found {
+		return gorm.ErrForeignKeyViolated
+	}
+	for key, stored := range m.state.chunks {
+		if stored.SessionUUID == chunk.SessionUUID && stored.Number == chunk.Number {
+			stored.UpdatedAt = time.Now()
+			m.state.chunks[key] = stored
+			chunk.UpdatedAt = stored.UpdatedAt
+			return nil
+		}
+	}
+	prepareModel(&chunk.Model)
+	var stored = *chunk
+	stored.Session = nil
+	m.state.chunks[chunk.UUID] = stored
+	return nil
+}
+
+func (m *Memory) UploadedChunks(sessionUUID uuid.UUID) (numbers []uint, err error) {
+	defer m.lock()()
+	for _, chunk := range m.state.chunks {
+		if chunk.SessionUUID == sessionUUID {
+			numbers = append(numbers, chunk.Number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool {
+		return numbers[i] < numbers[j]
+	})
+	return numbers, nil
+}
+
+func (m *Memory) DeleteUploadChunks(sessionUUID uuid.UUID) error {
+	defer m.lock()()
+	for key, chunk := range m.state.chunks {
+		if chunk.SessionUUID == sessionUUID {
+			delete(m.state.chunks, key)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) GetQuota(userUUID uuid.UUID) (quota models.Quota, err error) {
+	defer m.lock()()
+	for _, stored := range m.state.quotas {
+		if stored.UserUUID == userUUID {
+			return storedQuota(&stored), nil
+		}
+	}
+	return quota, gorm.ErrRecordNotFound
+}
+
+func (m *Memory) SaveQuota(quota *models.Quota) error {
+	defer m.lock()()
+	for _, stored := range m.state.quotas {
+		if stored.UserUUID == quota.UserUUID {
+			quota.Model = stored.Model
+			quota.UpdatedAt = time.Now()
+			m.state.quotas[quota.UUID] = storedQuota(quota)
+			return nil
+		}
+	}
+	prepareModel(&quota.Model)
+	m.state.quotas[quota.UUID] = storedQuota(quota)
+	return nil
+}
+
+// Transactions are already serialized, so registering the quota is enough
+func (m *Memory) LockQuota(userUUID uuid.UUID) (quota models.Quota, err error) {
+	defer m.lock()()
+	for _, stored := range m.state.quotas {
+		if stored.UserUUID == userUUID {
+			return storedQuota(&stored), nil
+		}
+	}
+	quota = models.Quota{UserUUID: userUUID}
+	prepareModel(&quota.Model)
+	m.state.quotas[quota.UUID] = storedQuota(&quota)
+	return quota, nil
+}
+
+// Detaches the limits from the caller
+func storedQuota(quota *models.Quota) models.Quota {
+	var stored = *quota
+	stored.MaxBytes = copyUint64(quota.MaxBytes)
+	stored.MaxFiles = copyUint64(quota.MaxFiles)
+	return stored
+}
+
+func copyUint64(u *uint64) *uint64 {
+	if u == nil {
+		return nil
+	}
+	var c = *u
+	return &c
+}
+
+func (m *Memory) Usage(ownerUUID uuid.UUID) (usage Usage, err error) {
+	defer m.lock()()
+	for _, file := range m.state.files {
+		if file.OwnerUUID != ownerUUID {
+			continue
+		}
+		usage.Files++
+		if file.ArchiveUUID != nil {
+			usage.Bytes += m.state.archives[*file.ArchiveUUID].Size
+		}
+	}
+	return usage, nil
+}
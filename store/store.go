@@ -0,0 +1,219 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/models"
+)
+
+// Storage of the filesystem index used by the controller.
+// Every implementation reports missing records with gorm.ErrRecordNotFound, unique constraint
+// violations with gorm.ErrDuplicatedKey and references to missing records with gorm.ErrForeignKeyViolated,
+// so callers handle all of them the same way.
+// Deletions cascade like the constraints of the models: removing a file removes its children, shares,
+// versions, upload sessions and links, removing a group removes its members and shares, and removing an archive
+// removes everything pointing to it
+type MetadataStore interface {
+	// Runs the function in a transaction, the changes are discarded when it returns an error.
+	// The store received by the function must not be used once it returns
+	Transaction(fn func(tx MetadataStore) error) error
+	Close() error
+
+	GetFile(fileUUID uuid.UUID) (models.File, error)
+	// Finds the file with the given name in the directory, or in the root of the owner when the parent is nil.
+	// Files in the trash are ignored
+	FindChild(ownerUUID uuid.UUID, parentUUID *uuid.UUID, name string) (models.File, error)
+	// Lists a page of the directory, files come with their archive
+	ListChildren(lc *ListChildren) ([]models.File, error)
+	// Returns the file followed by its parents up to the root, or gorm.ErrRecordNotFound when the file doesn't exist
+	Ancestors(fileUUID uuid.UUID) ([]models.File, error)
+	// Same as Ancestors for several files at once, keyed by the requested file. Missing files are skipped
+	ListAncestors(fileUUIDs []uuid.UUID) (map[uuid.UUID][]models.File, error)
+	// Returns the root and everything under it sorted by depth and name, files come with their archive.
+	// Fails with gorm.ErrRecordNotFound when the root doesn't exist
+	Subtree(st *Subtree) ([]models.File, error)
+	// Lists the files in the trash, most recently trashed first. Files come with their archive
+	ListTrashed(lt *ListTrashed) ([]models.File, error)
+	FilesWithArchives(archiveUUIDs []uuid.UUID) ([]models.File, error)
+	// Files come with their archive, missing files are skipped
+	GetFiles(fileUUIDs []uuid.UUID) ([]models.File, error)
+	CreateFile(file *models.File) error
+	// Overwrites all the columns of an existing file
+	SaveFile(file *models.File) error
+	DeleteFiles(fileUUIDs ...uuid.UUID) error
+
+	// Shares without a role are created as viewers
+	CreateShare(share *models.SharedFile) error
+	// Overwrites all the columns of an existing share
+	SaveShare(share *models.SharedFile) error
+	DeleteShare(fileUUID, userUUID uuid.UUID) error
+	// Lists the shares sorted by creation, ties are broken by UUID
+	ListShares(ls *ListShares) ([]models.SharedFile, error)
+
+	GetGroup(groupUUID uuid.UUID) (models.Group, error)
+	// Lists the groups sorted by name
+	ListGroups(lg *ListGroups) ([]models.Group, error)
+	CreateGroup(group *models.Group) error
+	DeleteGroup(groupUUID uuid.UUID) error
+	ListGroupMembers(groupUUID uuid.UUID) ([]models.GroupMember, error)
+	CreateGroupMember(member *models.GroupMember) error
+	DeleteGroupMember(groupUUID, userUUID uuid.UUID) error
+	// Shares without a role are created as viewers
+	CreateGroupShare(share *models.GroupShare) error
+	// Overwrites all the columns of an existing share
+	SaveGroupShare(share *models.GroupShare) error
+	DeleteGroupShare(fileUUID, groupUUID uuid.UUID) error
+	// Lists the shares sorted by creation, ties are broken by UUID
+	ListGroupShares(ls *ListGroupShares) ([]models.GroupShare, error)
+
+	FindShareLink(tokenHash string) (models.ShareLink, error)
+	// Lists the links of the file, oldest first
+	ListShareLinks(fileUUID uuid.UUID) ([]models.ShareLink, error)
+	CreateShareLink(link *models.ShareLink) error
+	DeleteShareLink(fileUUID, linkUUID uuid.UUID) error
+	// Counts a download of the link unless it reached its limit, fails with gorm.ErrRecordNotFound otherwise
+	CountShareLinkDownload(linkUUID uuid.UUID) error
+
+	GetArchive(archiveUUID uuid.UUID) (models.Archive, error)
+	FindArchive(hash string, size uint64) (models.Archive, error)
+	// Registers the archive, or refreshes the update time of the one already indexed with the same contents.
+	// Inside a transaction the archive stays locked until it ends
+	TouchArchive(hash string, size uint64) (models.Archive, error)
+	// Overwrites all the columns of an existing archive, including its update time
+	SaveArchive(archive *models.Archive) error
+	// Lists the archives still pending that were last updated before the given time.
+	// Inside a transaction the archives stay locked until it ends
+	StaleArchives(before time.Time) ([]models.Archive, error)
+	DeleteArchives(archiveUUIDs ...uuid.UUID) error
+	// Deletes the archives updated before the given time that no file or version points to
+	CollectArchives(before time.Time) ([]models.Archive, error)
+
+	GetVersion(versionUUID uuid.UUID) (models.FileVersion, error)
+	// Lists the versions of the file newest first, versions come with their archive
+	ListVersions(fileUUID uuid.UUID) ([]models.FileVersion, error)
+	CreateVersion(version *models.FileVersion) error
+	DeleteVersions(versionUUIDs ...uuid.UUID) error
+
+	// Sessions come with their archive
+	GetUploadSession(sessionUUID uuid.UUID) (models.UploadSession, error)
+	FindUploadSession(ownerUUID, fileUUID, archiveUUID uuid.UUID) (models.UploadSession, error)
+	UploadSessionsOf(archiveUUIDs []uuid.UUID) ([]models.UploadSession, error)
+	CreateUploadSession(session *models.UploadSession) error
+	DeleteUploadSession(sessionUUID uuid.UUID) error
+	// Registers the chunk, or refreshes the update time of the one already registered with the same number
+	TouchUploadChunk(chunk *models.UploadChunk) error
+	UploadedChunks(sessionUUID uuid.UUID) ([]uint, error)
+	DeleteUploadChunks(sessionUUID uuid.UUID) error
+
+	GetQuota(userUUID uuid.UUID) (models.Quota, error)
+	// Registers the quota of the user, or replaces the limits of the one already registered
+	SaveQuota(quota *models.Quota) error
+	// Returns the quota of the user, registering one without limits when missing.
+	// Inside a transaction the quota stays locked until it ends, which serializes the changes to the usage of the user
+	LockQuota(userUUID uuid.UUID) (models.Quota, error)
+	// Counts the files of the owner, directories and trashed files included,
+	// and adds up the sizes of their current contents. Old versions are not counted
+	Usage(ownerUUID uuid.UUID) (Usage, error)
+}
+
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortBySize      SortField = "size"
+	SortByCreatedAt SortField = "createdAt"
+)
+
+// Entry a listing resumes after
+type Position struct {
+	Name      string
+	Size      uint64
+	CreatedAt time.Time
+	UUID      uuid.UUID
+}
+
+type ListChildren struct {
+	// Lists the root of the owner when the parent is nil
+	OwnerUUID  uuid.UUID
+	ParentUUID *uuid.UUID
+	// Directories have size zero, ties are broken by UUID
+	SortBy     SortField
+	Descending bool
+	After      *Position
+	Limit      int
+}
+
+type Usage struct {
+	Bytes uint64
+	Files uint64
+}
+
+type Subtree struct {
+	RootUUID uuid.UUID
+	// A MaxDepth of zero means no limit, the root is at depth zero
+	MaxDepth        int
+	DirectoriesOnly bool
+	// Stops after this number of files when positive, the shallowest files come first
+	Limit int
+}
+
+type ListTrashed struct {
+	// All the owners when nil
+	OwnerUUID *uuid.UUID
+	// Only files trashed at or before this time when set
+	Before *time.Time
+}
+
+type ListShares struct {
+	// Any user when nil
+	UserUUID *uuid.UUID
+	// Any file when nil
+	FileUUIDs []uuid.UUID
+	// Only shares of files with this name when set
+	Name string
+	// Only shares of files owned by this user when set
+	OwnerUUID *uuid.UUID
+	// Skip the shares of files in the trash
+	SkipTrashed bool
+	// Skip the shares already expired at this time when set
+	ActiveAt *time.Time
+	// Only the shares already expired at this time when set
+	ExpiredAt *time.Time
+	// Newest shares first when set
+	Descending bool
+	// Resumes after this share when set, only its creation time and UUID are used
+	After *Position
+	// Stops after this number of shares when positive
+	Limit int
+}
+
+type ListGroups struct {
+	// Any owner when nil
+	OwnerUUID *uuid.UUID
+	// Only groups the user is a member of when set
+	MemberUUID *uuid.UUID
+}
+
+type ListGroupShares struct {
+	// Any group when nil
+	GroupUUIDs []uuid.UUID
+	// Any file when nil
+	FileUUIDs []uuid.UUID
+	// Only shares of files with this name when set
+	Name string
+	// Only shares of files owned by this user when set
+	OwnerUUID *uuid.UUID
+	// Skip the shares of files in the trash
+	SkipTrashed bool
+	// Skip the shares already expired at this time when set
+	ActiveAt *time.Time
+	// Only the shares already expired at this time when set
+	ExpiredAt *time.Time
+	// Newest shares first when set
+	Descending bool
+	// Resumes after this share when set, only its creation time and UUID are used
+	After *Position
+	// Stops after this number of shares when positive
+	Limit int
+}
@@ -0,0 +1,943 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hawks-atlanta/fs-prototype/config"
+	"github.com/hawks-atlanta/fs-prototype/database"
+	"github.com/hawks-atlanta/fs-prototype/models"
+	"github.com/hawks-atlanta/fs-prototype/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Runs against an in-memory SQLite database unless another one is configured
+func TestMain(m *testing.M) {
+	if os.Getenv("FS_DATABASE_DSN") == "" && os.Getenv(config.FileEnv) == "" {
+		os.Setenv("FS_DATABASE_DSN", database.SQLitePrefix+":memory:")
+	}
+	os.Exit(m.Run())
+}
+
+func TestGORM(t *testing.T) {
+	testMetadataStore(t, func(t *testing.T) MetadataStore {
+		db, err := database.Default()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := NewGORM(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+func TestMemory(t *testing.T) {
+	testMetadataStore(t, func(t *testing.T) MetadataStore {
+		return NewMemory()
+	})
+}
+
+func createDirectory(t *testing.T, s MetadataStore, ownerUUID uuid.UUID, parentUUID *uuid.UUID, name string) models.File {
+	var file = models.File{
+		OwnerUUID:  ownerUUID,
+		ParentUUID: parentUUID,
+		Name:       name,
+	}
+	assert.Nil(t, s.CreateFile(&file))
+	return file
+}
+
+func createFile(t *testing.T, s MetadataStore, ownerUUID uuid.UUID, parentUUID *uuid.UUID, name, contents string) models.File {
+	archive, err := s.TouchArchive(utils.Hash(contents), uint64(len(contents)))
+	assert.Nil(t, err)
+	var file = models.File{
+		OwnerUUID:   ownerUUID,
+		ParentUUID:  parentUUID,
+		ArchiveUUID: &archive.UUID,
+		Name:        name,
+	}
+	assert.Nil(t, s.CreateFile(&file))
+	return file
+}
+
+func fileNames(files []models.File) (names []string) {
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+// Conformance suite every implementation of the store must pass
+func testMetadataStore(t *testing.T, open func(t *testing.T) MetadataStore) {
+	t.Run("Files", func(t *testing.T) {
+		t.Run("Create and get", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				file  = createFile(t, s, owner, &dir.UUID, "notes.txt", uuid.NewString())
+			)
+			assertions.NotEqual(uuid.Nil, file.UUID)
+			assertions.False(file.CreatedAt.IsZero())
+
+			found, err := s.GetFile(file.UUID)
+			assertions.Nil(err)
+			assertions.Equal(file.Name, found.Name)
+			assertions.Equal(dir.UUID, *found.ParentUUID)
+			assertions.Equal(*file.ArchiveUUID, *found.ArchiveUUID)
+
+			_, err = s.GetFile(uuid.New())
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+			files, err := s.GetFiles([]uuid.UUID{file.UUID, uuid.New()})
+			assertions.Nil(err)
+			assertions.Len(files, 1)
+			assertions.Equal(file.UUID, files[0].UUID)
+			assertions.NotNil(files[0].Archive)
+		})
+		t.Run("Duplicated name", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+			)
+			createDirectory(t, s, owner, &dir.UUID, "child")
+			var duplicated = models.File{
+				OwnerUUID:  owner,
+				ParentUUID: &dir.UUID,
+				Name:       "child",
+			}
+			assertions.ErrorIs(s.CreateFile(&duplicated), gorm.ErrDuplicatedKey)
+
+			// Files in the root collide too, unless they are in the trash
+			duplicated = models.File{OwnerUUID: owner, Name: "docs"}
+			assertions.ErrorIs(s.CreateFile(&duplicated), gorm.ErrDuplicatedKey)
+			createDirectory(t, s, uuid.New(), nil, "docs")
+			var now = time.Now()
+			dir.TrashedAt = &now
+			assertions.Nil(s.SaveFile(&dir))
+			createDirectory(t, s, owner, nil, "docs")
+		})
+		t.Run("Missing parent", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				parent = uuid.New()
+				file   = models.File{
+					OwnerUUID:  uuid.New(),
+					ParentUUID: &parent,
+					Name:       "orphan",
+				}
+			)
+			assertions.ErrorIs(s.CreateFile(&file), gorm.ErrForeignKeyViolated)
+		})
+		t.Run("Find child", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				file  = createFile(t, s, owner, &dir.UUID, "notes.txt", uuid.NewString())
+			)
+			found, err := s.FindChild(owner, nil, "docs")
+			assertions.Nil(err)
+			assertions.Equal(dir.UUID, found.UUID)
+
+			found, err = s.FindChild(owner, &dir.UUID, "notes.txt")
+			assertions.Nil(err)
+			assertions.Equal(file.UUID, found.UUID)
+
+			_, err = s.FindChild(uuid.New(), nil, "docs")
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+			// Trashed files are ignored
+			var now = time.Now()
+			dir.TrashedAt = &now
+			assertions.Nil(s.SaveFile(&dir))
+			_, err = s.FindChild(owner, nil, "docs")
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+		t.Run("Save", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				file  = createFile(t, s, owner, &dir.UUID, "notes.txt", uuid.NewString())
+			)
+			var now = time.Now()
+			file.Name = "renamed.txt"
+			file.TrashedAt = &now
+			file.TrashedFromUUID = file.ParentUUID
+			file.ParentUUID = nil
+			assertions.Nil(s.SaveFile(&file))
+
+			found, err := s.GetFile(file.UUID)
+			assertions.Nil(err)
+			assertions.Equal("renamed.txt", found.Name)
+			assertions.Nil(found.ParentUUID)
+			assertions.Equal(dir.UUID, *found.TrashedFromUUID)
+			assertions.NotNil(found.TrashedAt)
+
+			var missing = models.File{Model: models.Model{UUID: uuid.New()}, Name: "missing"}
+			assertions.ErrorIs(s.SaveFile(&missing), gorm.ErrRecordNotFound)
+		})
+		t.Run("Delete in cascade", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				child = createDirectory(t, s, owner, &dir.UUID, "child")
+				file  = createFile(t, s, owner, &child.UUID, "notes.txt", uuid.NewString())
+				user  = uuid.New()
+			)
+			assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: file.UUID, UserUUID: user}))
+			assertions.Nil(s.CreateVersion(&models.FileVersion{
+				FileUUID:    file.UUID,
+				Number:      1,
+				ArchiveUUID: *file.ArchiveUUID,
+				AuthorUUID:  owner,
+				Timestamp:   time.Now(),
+			}))
+
+			assertions.Nil(s.DeleteFiles(dir.UUID))
+			for _, fileUUID := range []uuid.UUID{dir.UUID, child.UUID, file.UUID} {
+				_, err := s.GetFile(fileUUID)
+				assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+			}
+			shares, err := s.ListShares(&ListShares{UserUUID: &user})
+			assertions.Nil(err)
+			assertions.Len(shares, 0)
+			versions, err := s.ListVersions(file.UUID)
+			assertions.Nil(err)
+			assertions.Len(versions, 0)
+		})
+	})
+	t.Run("List children", func(t *testing.T) {
+		t.Run("Sort and paginate", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+			)
+			createFile(t, s, owner, &dir.UUID, "b.txt", "12345")
+			createFile(t, s, owner, &dir.UUID, "a.txt", "123")
+			createDirectory(t, s, owner, &dir.UUID, "c")
+
+			var lc = ListChildren{
+				OwnerUUID:  owner,
+				ParentUUID: &dir.UUID,
+				SortBy:     SortByName,
+				Limit:      2,
+			}
+			files, err := s.ListChildren(&lc)
+			assertions.Nil(err)
+			assertions.Equal([]string{"a.txt", "b.txt"}, fileNames(files))
+			assertions.NotNil(files[0].Archive)
+
+			var last = files[1]
+			lc.After = &Position{
+				Name:      last.Name,
+				Size:      last.Archive.Size,
+				CreatedAt: last.CreatedAt,
+				UUID:      last.UUID,
+			}
+			files, err = s.ListChildren(&lc)
+			assertions.Nil(err)
+			assertions.Equal([]string{"c"}, fileNames(files))
+
+			lc = ListChildren{
+				OwnerUUID:  owner,
+				ParentUUID: &dir.UUID,
+				SortBy:     SortBySize,
+				Descending: true,
+			}
+			files, err = s.ListChildren(&lc)
+			assertions.Nil(err)
+			assertions.Equal([]string{"b.txt", "a.txt", "c"}, fileNames(files))
+		})
+		t.Run("Root", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var owner = uuid.New()
+			createDirectory(t, s, owner, nil, "docs")
+			trashed := createDirectory(t, s, owner, nil, "trashed")
+			createDirectory(t, s, uuid.New(), nil, "other")
+			var now = time.Now()
+			trashed.TrashedAt = &now
+			assertions.Nil(s.SaveFile(&trashed))
+
+			files, err := s.ListChildren(&ListChildren{OwnerUUID: owner, SortBy: SortByName})
+			assertions.Nil(err)
+			assertions.Equal([]string{"docs"}, fileNames(files))
+		})
+		t.Run("Unknown sort field", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			_, err := s.ListChildren(&ListChildren{OwnerUUID: uuid.New(), SortBy: "color"})
+			assertions.NotNil(err)
+		})
+	})
+	t.Run("Hierarchy", func(t *testing.T) {
+		t.Run("Ancestors", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				child = createDirectory(t, s, owner, &dir.UUID, "child")
+				file  = createFile(t, s, owner, &child.UUID, "notes.txt", uuid.NewString())
+			)
+			files, err := s.Ancestors(file.UUID)
+			assertions.Nil(err)
+			assertions.Equal([]string{"notes.txt", "child", "docs"}, fileNames(files))
+
+			_, err = s.Ancestors(uuid.New())
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+			hierarchies, err := s.ListAncestors([]uuid.UUID{file.UUID, dir.UUID, uuid.New()})
+			assertions.Nil(err)
+			assertions.Len(hierarchies, 2)
+			assertions.Equal([]string{"notes.txt", "child", "docs"}, fileNames(hierarchies[file.UUID]))
+			assertions.Equal([]string{"docs"}, fileNames(hierarchies[dir.UUID]))
+		})
+		t.Run("Subtree", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				dir   = createDirectory(t, s, owner, nil, "docs")
+				b     = createDirectory(t, s, owner, &dir.UUID, "b")
+			)
+			createFile(t, s, owner, &dir.UUID, "a.txt", uuid.NewString())
+			createFile(t, s, owner, &b.UUID, "c.txt", uuid.NewString())
+
+			files, err := s.Subtree(&Subtree{RootUUID: dir.UUID})
+			assertions.Nil(err)
+			assertions.Equal([]string{"docs", "a.txt", "b", "c.txt"}, fileNames(files))
+			assertions.NotNil(files[1].Archive)
+
+			files, err = s.Subtree(&Subtree{RootUUID: dir.UUID, MaxDepth: 1})
+			assertions.Nil(err)
+			assertions.Equal([]string{"docs", "a.txt", "b"}, fileNames(files))
+
+			files, err = s.Subtree(&Subtree{RootUUID: dir.UUID, DirectoriesOnly: true})
+			assertions.Nil(err)
+			assertions.Equal([]string{"docs", "b"}, fileNames(files))
+
+			files, err = s.Subtree(&Subtree{RootUUID: dir.UUID, Limit: 3})
+			assertions.Nil(err)
+			assertions.Equal([]string{"docs", "a.txt", "b"}, fileNames(files))
+
+			_, err = s.Subtree(&Subtree{RootUUID: uuid.New()})
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+	})
+	t.Run("List trashed", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner  = uuid.New()
+			old    = createDirectory(t, s, owner, nil, "old")
+			recent = createDirectory(t, s, owner, nil, "recent")
+		)
+		createDirectory(t, s, owner, nil, "kept")
+		var (
+			now      = time.Now()
+			longAgo  = now.Add(-time.Hour)
+			halfHour = now.Add(-30 * time.Minute)
+		)
+		old.TrashedAt = &longAgo
+		assertions.Nil(s.SaveFile(&old))
+		recent.TrashedAt = &now
+		assertions.Nil(s.SaveFile(&recent))
+
+		files, err := s.ListTrashed(&ListTrashed{OwnerUUID: &owner})
+		assertions.Nil(err)
+		assertions.Equal([]string{"recent", "old"}, fileNames(files))
+
+		files, err = s.ListTrashed(&ListTrashed{OwnerUUID: &owner, Before: &halfHour})
+		assertions.Nil(err)
+		assertions.Equal([]string{"old"}, fileNames(files))
+	})
+	t.Run("Shares", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner = uuid.New()
+			user  = uuid.New()
+			dir   = createDirectory(t, s, owner, nil, "docs")
+			file  = createFile(t, s, owner, nil, "notes.txt", uuid.NewString())
+		)
+		assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: user}))
+		assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: file.UUID, UserUUID: user}))
+		assertions.ErrorIs(s.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: user}), gorm.ErrDuplicatedKey)
+
+		shares, err := s.ListShares(&ListShares{UserUUID: &user})
+		assertions.Nil(err)
+		assertions.Len(shares, 2)
+
+		// Pages sorted by creation
+		var position = Position{CreatedAt: shares[0].CreatedAt, UUID: shares[0].UUID}
+		page, err := s.ListShares(&ListShares{UserUUID: &user, Limit: 1})
+		assertions.Nil(err)
+		assertions.Len(page, 1)
+		assertions.Equal(shares[0].UUID, page[0].UUID)
+		page, err = s.ListShares(&ListShares{UserUUID: &user, After: &position})
+		assertions.Nil(err)
+		assertions.Len(page, 1)
+		assertions.Equal(shares[1].UUID, page[0].UUID)
+		page, err = s.ListShares(&ListShares{UserUUID: &user, Descending: true})
+		assertions.Nil(err)
+		assertions.Equal([]uuid.UUID{shares[1].UUID, shares[0].UUID}, []uuid.UUID{page[0].UUID, page[1].UUID})
+
+		shares, err = s.ListShares(&ListShares{UserUUID: &user, Name: "docs"})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.Equal(dir.UUID, shares[0].FileUUID)
+
+		shares, err = s.ListShares(&ListShares{UserUUID: &user, OwnerUUID: &user})
+		assertions.Nil(err)
+		assertions.Len(shares, 0)
+
+		shares, err = s.ListShares(&ListShares{FileUUIDs: []uuid.UUID{file.UUID}, OwnerUUID: &owner})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.Equal(models.RoleViewer, shares[0].Role)
+
+		var share = shares[0]
+		share.Role = models.RoleEditor
+		assertions.Nil(s.SaveShare(&share))
+		shares, err = s.ListShares(&ListShares{FileUUIDs: []uuid.UUID{file.UUID}})
+		assertions.Nil(err)
+		assertions.Equal(models.RoleEditor, shares[0].Role)
+		var missing = models.SharedFile{Model: models.Model{UUID: uuid.New()}, FileUUID: file.UUID, UserUUID: uuid.New()}
+		assertions.ErrorIs(s.SaveShare(&missing), gorm.ErrRecordNotFound)
+
+		var now = time.Now()
+		dir.TrashedAt = &now
+		assertions.Nil(s.SaveFile(&dir))
+		shares, err = s.ListShares(&ListShares{UserUUID: &user, SkipTrashed: true})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.Equal(file.UUID, shares[0].FileUUID)
+
+		assertions.Nil(s.DeleteShare(file.UUID, user))
+		shares, err = s.ListShares(&ListShares{UserUUID: &user, SkipTrashed: true})
+		assertions.Nil(err)
+		assertions.Len(shares, 0)
+	})
+	t.Run("Expiring shares", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner     = uuid.New()
+			dir       = createDirectory(t, s, owner, nil, "docs")
+			group     = models.Group{OwnerUUID: owner, Name: "team"}
+			now       = time.Now()
+			expired   = now.Add(-time.Minute)
+			expiring  = now.Add(time.Hour)
+			permanent = uuid.New()
+		)
+		assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: uuid.New(), ExpiresAt: &expired}))
+		assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: uuid.New(), ExpiresAt: &expiring}))
+		assertions.Nil(s.CreateShare(&models.SharedFile{FileUUID: dir.UUID, UserUUID: permanent}))
+
+		shares, err := s.ListShares(&ListShares{FileUUIDs: []uuid.UUID{dir.UUID}, ActiveAt: &now})
+		assertions.Nil(err)
+		assertions.Len(shares, 2)
+		shares, err = s.ListShares(&ListShares{FileUUIDs: []uuid.UUID{dir.UUID}, ExpiredAt: &now})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.True(shares[0].ExpiresAt.Equal(expired))
+
+		// Later on the expiring share expires too
+		var later = expiring.Add(time.Minute)
+		shares, err = s.ListShares(&ListShares{ActiveAt: &later, UserUUID: &permanent})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		shares, err = s.ListShares(&ListShares{FileUUIDs: []uuid.UUID{dir.UUID}, ExpiredAt: &later})
+		assertions.Nil(err)
+		assertions.Len(shares, 2)
+
+		assertions.Nil(s.CreateGroup(&group))
+		assertions.Nil(s.CreateGroupShare(&models.GroupShare{FileUUID: dir.UUID, GroupUUID: group.UUID, ExpiresAt: &expired}))
+		grants, err := s.ListGroupShares(&ListGroupShares{FileUUIDs: []uuid.UUID{dir.UUID}, ActiveAt: &now})
+		assertions.Nil(err)
+		assertions.Len(grants, 0)
+		grants, err = s.ListGroupShares(&ListGroupShares{FileUUIDs: []uuid.UUID{dir.UUID}, ExpiredAt: &now})
+		assertions.Nil(err)
+		assertions.Len(grants, 1)
+	})
+	t.Run("Groups", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner  = uuid.New()
+			user   = uuid.New()
+			dir    = createDirectory(t, s, owner, nil, "docs")
+			team   = models.Group{OwnerUUID: owner, Name: "team"}
+			admins = models.Group{OwnerUUID: owner, Name: "admins"}
+		)
+		assertions.Nil(s.CreateGroup(&team))
+		assertions.Nil(s.CreateGroup(&admins))
+		assertions.ErrorIs(s.CreateGroup(&models.Group{OwnerUUID: owner, Name: "team"}), gorm.ErrDuplicatedKey)
+		assertions.Nil(s.CreateGroup(&models.Group{OwnerUUID: uuid.New(), Name: "team"}))
+
+		group, err := s.GetGroup(team.UUID)
+		assertions.Nil(err)
+		assertions.Equal("team", group.Name)
+
+		groups, err := s.ListGroups(&ListGroups{OwnerUUID: &owner})
+		assertions.Nil(err)
+		assertions.Len(groups, 2)
+		assertions.Equal("admins", groups[0].Name)
+
+		// Members
+		assertions.Nil(s.CreateGroupMember(&models.GroupMember{GroupUUID: team.UUID, UserUUID: user}))
+		assertions.ErrorIs(s.CreateGroupMember(&models.GroupMember{GroupUUID: team.UUID, UserUUID: user}), gorm.ErrDuplicatedKey)
+		assertions.ErrorIs(s.CreateGroupMember(&models.GroupMember{GroupUUID: uuid.New(), UserUUID: user}), gorm.ErrForeignKeyViolated)
+		members, err := s.ListGroupMembers(team.UUID)
+		assertions.Nil(err)
+		assertions.Len(members, 1)
+		assertions.Equal(user, members[0].UserUUID)
+
+		groups, err = s.ListGroups(&ListGroups{MemberUUID: &user})
+		assertions.Nil(err)
+		assertions.Len(groups, 1)
+		assertions.Equal(team.UUID, groups[0].UUID)
+
+		// Shares
+		assertions.Nil(s.CreateGroupShare(&models.GroupShare{GroupUUID: team.UUID, FileUUID: dir.UUID}))
+		assertions.ErrorIs(s.CreateGroupShare(&models.GroupShare{GroupUUID: team.UUID, FileUUID: dir.UUID}), gorm.ErrDuplicatedKey)
+		assertions.ErrorIs(s.CreateGroupShare(&models.GroupShare{GroupUUID: uuid.New(), FileUUID: dir.UUID}), gorm.ErrForeignKeyViolated)
+		shares, err := s.ListGroupShares(&ListGroupShares{GroupUUIDs: []uuid.UUID{team.UUID}})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.Equal(models.RoleViewer, shares[0].Role)
+
+		var share = shares[0]
+		share.Role = models.RoleEditor
+		assertions.Nil(s.SaveGroupShare(&share))
+		shares, err = s.ListGroupShares(&ListGroupShares{FileUUIDs: []uuid.UUID{dir.UUID}, Name: "docs"})
+		assertions.Nil(err)
+		assertions.Len(shares, 1)
+		assertions.Equal(models.RoleEditor, shares[0].Role)
+
+		var now = time.Now()
+		dir.TrashedAt = &now
+		assertions.Nil(s.SaveFile(&dir))
+		shares, err = s.ListGroupShares(&ListGroupShares{GroupUUIDs: []uuid.UUID{team.UUID}, SkipTrashed: true})
+		assertions.Nil(err)
+		assertions.Len(shares, 0)
+
+		// Delete in cascade
+		assertions.Nil(s.DeleteGroupMember(team.UUID, user))
+		members, err = s.ListGroupMembers(team.UUID)
+		assertions.Nil(err)
+		assertions.Len(members, 0)
+		assertions.Nil(s.CreateGroupMember(&models.GroupMember{GroupUUID: team.UUID, UserUUID: user}))
+		assertions.Nil(s.DeleteGroup(team.UUID))
+		_, err = s.GetGroup(team.UUID)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		groups, err = s.ListGroups(&ListGroups{MemberUUID: &user})
+		assertions.Nil(err)
+		assertions.Len(groups, 0)
+		shares, err = s.ListGroupShares(&ListGroupShares{FileUUIDs: []uuid.UUID{dir.UUID}})
+		assertions.Nil(err)
+		assertions.Len(shares, 0)
+
+		assertions.Nil(s.CreateGroupShare(&models.GroupShare{GroupUUID: admins.UUID, FileUUID: dir.UUID}))
+		assertions.Nil(s.DeleteFiles(dir.UUID))
+		shares, err = s.ListGroupShares(&ListGroupShares{GroupUUIDs: []uuid.UUID{admins.UUID}})
+		assertions.Nil(err)
+		assertions.Len(shares, 0)
+	})
+	t.Run("Share links", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner        = uuid.New()
+			dir          = createDirectory(t, s, owner, nil, "docs")
+			maxDownloads = uint(2)
+			limited      = models.ShareLink{FileUUID: dir.UUID, CreatorUUID: owner, TokenHash: uuid.NewString(), MaxDownloads: &maxDownloads}
+			unlimited    = models.ShareLink{FileUUID: dir.UUID, CreatorUUID: owner, TokenHash: uuid.NewString()}
+		)
+		assertions.Nil(s.CreateShareLink(&limited))
+		assertions.Nil(s.CreateShareLink(&unlimited))
+		assertions.ErrorIs(s.CreateShareLink(&models.ShareLink{FileUUID: dir.UUID, CreatorUUID: owner, TokenHash: limited.TokenHash}), gorm.ErrDuplicatedKey)
+		assertions.ErrorIs(s.CreateShareLink(&models.ShareLink{FileUUID: uuid.New(), CreatorUUID: owner, TokenHash: uuid.NewString()}), gorm.ErrForeignKeyViolated)
+
+		link, err := s.FindShareLink(limited.TokenHash)
+		assertions.Nil(err)
+		assertions.Equal(limited.UUID, link.UUID)
+		assertions.Equal(maxDownloads, *link.MaxDownloads)
+		_, err = s.FindShareLink(uuid.NewString())
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		links, err := s.ListShareLinks(dir.UUID)
+		assertions.Nil(err)
+		assertions.Len(links, 2)
+
+		// Downloads
+		assertions.Nil(s.CountShareLinkDownload(limited.UUID))
+		assertions.Nil(s.CountShareLinkDownload(limited.UUID))
+		assertions.ErrorIs(s.CountShareLinkDownload(limited.UUID), gorm.ErrRecordNotFound)
+		assertions.Nil(s.CountShareLinkDownload(unlimited.UUID))
+		link, err = s.FindShareLink(limited.TokenHash)
+		assertions.Nil(err)
+		assertions.Equal(uint(2), link.Downloads)
+
+		// Delete
+		assertions.Nil(s.DeleteShareLink(uuid.New(), limited.UUID))
+		assertions.Nil(s.DeleteShareLink(dir.UUID, limited.UUID))
+		_, err = s.FindShareLink(limited.TokenHash)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		assertions.Nil(s.DeleteFiles(dir.UUID))
+		links, err = s.ListShareLinks(dir.UUID)
+		assertions.Nil(err)
+		assertions.Len(links, 0)
+	})
+	t.Run("Archives", func(t *testing.T) {
+		t.Run("Touch", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var contents = uuid.NewString()
+			archive, err := s.TouchArchive(utils.Hash(contents), uint64(len(contents)))
+			assertions.Nil(err)
+			assertions.False(archive.IsReady)
+
+			archive.IsReady = true
+			archive.UpdatedAt = time.Now().Add(-time.Hour)
+			assertions.Nil(s.SaveArchive(&archive))
+
+			touched, err := s.TouchArchive(archive.Hash, archive.Size)
+			assertions.Nil(err)
+			assertions.Equal(archive.UUID, touched.UUID)
+			assertions.True(touched.IsReady)
+			assertions.True(touched.UpdatedAt.After(archive.UpdatedAt))
+
+			found, err := s.FindArchive(archive.Hash, archive.Size)
+			assertions.Nil(err)
+			assertions.Equal(archive.UUID, found.UUID)
+			_, err = s.FindArchive(archive.Hash, archive.Size+1)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+		t.Run("Stale", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var contents = uuid.NewString()
+			archive, err := s.TouchArchive(utils.Hash(contents), uint64(len(contents)))
+			assertions.Nil(err)
+
+			archives, err := s.StaleArchives(time.Now().Add(-time.Minute))
+			assertions.Nil(err)
+			assertions.NotContains(archives, archive)
+
+			archive.UpdatedAt = time.Now().Add(-time.Hour)
+			assertions.Nil(s.SaveArchive(&archive))
+			archives, err = s.StaleArchives(time.Now().Add(-time.Minute))
+			assertions.Nil(err)
+			var found bool
+			for _, stale := range archives {
+				found = found || stale.UUID == archive.UUID
+			}
+			assertions.True(found)
+		})
+		t.Run("Delete in cascade", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner = uuid.New()
+				file  = createFile(t, s, owner, nil, "notes.txt", uuid.NewString())
+			)
+			var session = models.UploadSession{
+				OwnerUUID:   owner,
+				FileUUID:    file.UUID,
+				ArchiveUUID: *file.ArchiveUUID,
+				ChunkSize:   1,
+				ChunkCount:  1,
+			}
+			assertions.Nil(s.CreateUploadSession(&session))
+
+			assertions.Nil(s.DeleteArchives(*file.ArchiveUUID))
+			_, err := s.GetFile(file.UUID)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+			_, err = s.GetUploadSession(session.UUID)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+		t.Run("Collect", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				owner    = uuid.New()
+				file     = createFile(t, s, owner, nil, "notes.txt", uuid.NewString())
+				contents = uuid.NewString()
+			)
+			unreferenced, err := s.TouchArchive(utils.Hash(contents), uint64(len(contents)))
+			assertions.Nil(err)
+			for _, archiveUUID := range []uuid.UUID{unreferenced.UUID, *file.ArchiveUUID} {
+				archive, err := s.GetArchive(archiveUUID)
+				assertions.Nil(err)
+				archive.UpdatedAt = time.Now().Add(-time.Hour)
+				assertions.Nil(s.SaveArchive(&archive))
+			}
+
+			collected, err := s.CollectArchives(time.Now().Add(-time.Minute))
+			assertions.Nil(err)
+			var uuids []uuid.UUID
+			for _, archive := range collected {
+				uuids = append(uuids, archive.UUID)
+			}
+			assertions.Contains(uuids, unreferenced.UUID)
+			assertions.NotContains(uuids, *file.ArchiveUUID)
+			_, err = s.GetArchive(unreferenced.UUID)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+	})
+	t.Run("Versions", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner = uuid.New()
+			file  = createFile(t, s, owner, nil, "notes.txt", uuid.NewString())
+		)
+		var versions []models.FileVersion
+		for number := uint(1); number <= 3; number++ {
+			var version = models.FileVersion{
+				FileUUID:    file.UUID,
+				Number:      number,
+				ArchiveUUID: *file.ArchiveUUID,
+				AuthorUUID:  owner,
+				Timestamp:   time.Now(),
+			}
+			assertions.Nil(s.CreateVersion(&version))
+			versions = append(versions, version)
+		}
+		var duplicated = versions[0]
+		duplicated.UUID = uuid.Nil
+		assertions.ErrorIs(s.CreateVersion(&duplicated), gorm.ErrDuplicatedKey)
+
+		found, err := s.GetVersion(versions[1].UUID)
+		assertions.Nil(err)
+		assertions.Equal(uint(2), found.Number)
+
+		assertions.Nil(s.DeleteVersions(versions[1].UUID))
+		listed, err := s.ListVersions(file.UUID)
+		assertions.Nil(err)
+		assertions.Len(listed, 2)
+		assertions.Equal(uint(3), listed[0].Number)
+		assertions.Equal(uint(1), listed[1].Number)
+		assertions.NotNil(listed[0].Archive)
+	})
+	t.Run("Uploads", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner = uuid.New()
+			file  = createFile(t, s, owner, nil, "notes.txt", uuid.NewString())
+		)
+		var session = models.UploadSession{
+			OwnerUUID:   owner,
+			FileUUID:    file.UUID,
+			ArchiveUUID: *file.ArchiveUUID,
+			ChunkSize:   4,
+			ChunkCount:  3,
+		}
+		assertions.Nil(s.CreateUploadSession(&session))
+
+		found, err := s.FindUploadSession(owner, file.UUID, *file.ArchiveUUID)
+		assertions.Nil(err)
+		assertions.Equal(session.UUID, found.UUID)
+		assertions.NotNil(found.Archive)
+
+		for _, number := range []uint{2, 0, 2} {
+			var chunk = models.UploadChunk{
+				SessionUUID: session.UUID,
+				Number:      number,
+				Size:        4,
+			}
+			assertions.Nil(s.TouchUploadChunk(&chunk))
+			assertions.False(chunk.UpdatedAt.IsZero())
+		}
+		uploaded, err := s.UploadedChunks(session.UUID)
+		assertions.Nil(err)
+		assertions.Equal([]uint{0, 2}, uploaded)
+
+		sessions, err := s.UploadSessionsOf([]uuid.UUID{*file.ArchiveUUID})
+		assertions.Nil(err)
+		assertions.Len(sessions, 1)
+
+		assertions.Nil(s.DeleteUploadChunks(session.UUID))
+		uploaded, err = s.UploadedChunks(session.UUID)
+		assertions.Nil(err)
+		assertions.Len(uploaded, 0)
+
+		assertions.Nil(s.DeleteUploadSession(session.UUID))
+		_, err = s.GetUploadSession(session.UUID)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+	t.Run("Quotas", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			user     = uuid.New()
+			maxBytes = uint64(1024)
+			maxFiles = uint64(10)
+		)
+		_, err := s.GetQuota(user)
+		assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		var quota = models.Quota{UserUUID: user, MaxBytes: &maxBytes}
+		assertions.Nil(s.SaveQuota(&quota))
+		var first = quota.UUID
+
+		quota = models.Quota{UserUUID: user, MaxFiles: &maxFiles}
+		assertions.Nil(s.SaveQuota(&quota))
+		assertions.Equal(first, quota.UUID)
+		found, err := s.GetQuota(user)
+		assertions.Nil(err)
+		assertions.Nil(found.MaxBytes)
+		assertions.Equal(maxFiles, *found.MaxFiles)
+
+		// Locking keeps the existing limits and registers the missing quotas
+		locked, err := s.LockQuota(user)
+		assertions.Nil(err)
+		assertions.Equal(first, locked.UUID)
+		assertions.Equal(maxFiles, *locked.MaxFiles)
+		var other = uuid.New()
+		locked, err = s.LockQuota(other)
+		assertions.Nil(err)
+		assertions.Nil(locked.MaxBytes)
+		assertions.Nil(locked.MaxFiles)
+		found, err = s.GetQuota(other)
+		assertions.Nil(err)
+		assertions.Equal(locked.UUID, found.UUID)
+	})
+	t.Run("Usage", func(t *testing.T) {
+		assertions := assert.New(t)
+
+		s := open(t)
+		var (
+			owner    = uuid.New()
+			contents = uuid.NewString()
+			dir      = createDirectory(t, s, owner, nil, "docs")
+			file     = createFile(t, s, owner, &dir.UUID, "notes.txt", contents)
+		)
+		createFile(t, s, uuid.New(), nil, "other.txt", uuid.NewString())
+
+		// Files sharing the same contents count each
+		var duplicate = models.File{OwnerUUID: owner, ArchiveUUID: file.ArchiveUUID, Name: "copy.txt"}
+		assertions.Nil(s.CreateFile(&duplicate))
+		var now = time.Now()
+		duplicate.TrashedAt = &now
+		assertions.Nil(s.SaveFile(&duplicate))
+
+		usage, err := s.Usage(owner)
+		assertions.Nil(err)
+		assertions.Equal(Usage{Bytes: 2 * uint64(len(contents)), Files: 3}, usage)
+
+		usage, err = s.Usage(uuid.New())
+		assertions.Nil(err)
+		assertions.Equal(Usage{}, usage)
+	})
+	t.Run("Transactions", func(t *testing.T) {
+		t.Run("Commit", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var file models.File
+			err := s.Transaction(func(tx MetadataStore) error {
+				file = createDirectory(t, tx, uuid.New(), nil, "docs")
+				return nil
+			})
+			assertions.Nil(err)
+			_, err = s.GetFile(file.UUID)
+			assertions.Nil(err)
+		})
+		t.Run("Rollback", func(t *testing.T) {
+			assertions := assert.New(t)
+
+			s := open(t)
+			var (
+				file   models.File
+				failed = errors.New("failed")
+			)
+			err := s.Transaction(func(tx MetadataStore) error {
+				file = createDirectory(t, tx, uuid.New(), nil, "docs")
+				return failed
+			})
+			assertions.ErrorIs(err, failed)
+			_, err = s.GetFile(file.UUID)
+			assertions.ErrorIs(err, gorm.ErrRecordNotFound)
+		})
+	})
+}
+
+func TestMemory_Concurrency(t *testing.T) {
+	assertions := assert.New(t)
+
+	var (
+		s     = NewMemory()
+		owner = uuid.New()
+		dir   = createDirectory(t, s, owner, nil, "docs")
+		wg    sync.WaitGroup
+	)
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := 0; index < 25; index++ {
+				err := s.Transaction(func(tx MetadataStore) error {
+					var file = models.File{
+						OwnerUUID:  owner,
+						ParentUUID: &dir.UUID,
+						Name:       uuid.NewString(),
+					}
+					return tx.CreateFile(&file)
+				})
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	files, err := s.ListChildren(&ListChildren{OwnerUUID: owner, ParentUUID: &dir.UUID, SortBy: SortByName})
+	assertions.Nil(err)
+	assertions.Len(files, 8*25)
+}
@@ -3,9 +3,15 @@ package utils
 import (
 	"crypto/sha512"
 	"encoding/hex"
+	"hash"
 )
 
 func Hash[T string | []byte](buf T) string {
 	hash := sha512.Sum512_256([]byte(buf))
 	return hex.EncodeToString(hash[:])
 }
+
+// Streaming version of Hash, the hex encoded sum matches the one returned by Hash
+func NewHasher() hash.Hash {
+	return sha512.New512_256()
+}